@@ -0,0 +1,168 @@
+// Package app wires the services over a set of repositories and serves them
+// through the GraphQL schema. The server, the command-line tools and the tests
+// all build on it, so they run the same business rules.
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/render"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/stream"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/webhook"
+)
+
+// Repositories back the services. Nil fields get empty in-memory repositories.
+type Repositories struct {
+	Users         repository.UserRepository
+	Posts         repository.PostRepository
+	Revisions     repository.RevisionRepository
+	Follows       repository.FollowRepository
+	Webhooks      repository.WebhookRepository
+	Verifications repository.VerificationRepository
+}
+
+// NewRepositories returns empty in-memory repositories
+func NewRepositories() Repositories {
+	return Repositories{
+		Users:         repository.NewInMemoryUserRepository(),
+		Posts:         repository.NewInMemoryPostRepository(),
+		Revisions:     repository.NewInMemoryRevisionRepository(),
+		Follows:       repository.NewInMemoryFollowRepository(),
+		Webhooks:      repository.NewInMemoryWebhookRepository(),
+		Verifications: repository.NewInMemoryVerificationRepository(),
+	}
+}
+
+func (r *Repositories) fillDefaults() {
+	defaults := NewRepositories()
+	if r.Users == nil {
+		r.Users = defaults.Users
+	}
+	if r.Posts == nil {
+		r.Posts = defaults.Posts
+	}
+	if r.Revisions == nil {
+		r.Revisions = defaults.Revisions
+	}
+	if r.Follows == nil {
+		r.Follows = defaults.Follows
+	}
+	if r.Webhooks == nil {
+		r.Webhooks = defaults.Webhooks
+	}
+	if r.Verifications == nil {
+		r.Verifications = defaults.Verifications
+	}
+}
+
+// Options configure the services. The zero value suits tools and tests.
+type Options struct {
+	// Dispatcher delivers webhooks. When nil they are queued on a dispatcher
+	// that is never run, so nothing is delivered.
+	Dispatcher *webhook.Dispatcher
+	// Mail sends email verification tokens; nil logs them
+	Mail service.EmailSender
+	// VerificationTTL is how long email verification tokens last (default an hour)
+	VerificationTTL time.Duration
+	// RenderCacheSize is how many rendered Markdown bodies are kept (default 4096)
+	RenderCacheSize int
+	// Traced wraps every service in tracing spans
+	Traced bool
+}
+
+// Services are the business logic the API and tools call
+type Services struct {
+	Users    service.UserService
+	Posts    service.PostService
+	Stats    service.StatsService
+	Webhooks service.WebhookService
+	Emails   service.EmailService
+
+	renderer *render.Renderer
+}
+
+// NewServices builds the services over repos
+func NewServices(repos Repositories, opts Options) Services {
+	repos.fillDefaults()
+	if opts.Dispatcher == nil {
+		opts.Dispatcher = webhook.NewDispatcher(repos.Webhooks, webhook.Options{})
+	}
+	if opts.Mail == nil {
+		opts.Mail = service.LogEmailSender{}
+	}
+	if opts.VerificationTTL <= 0 {
+		opts.VerificationTTL = time.Hour
+	}
+	if opts.RenderCacheSize <= 0 {
+		opts.RenderCacheSize = 4096
+	}
+
+	s := Services{
+		Users:    service.NewUserService(repos.Users, repos.Posts, repos.Revisions, repos.Follows),
+		Posts:    service.NewPostService(repos.Posts, repos.Users, repos.Revisions, repos.Follows),
+		Stats:    service.NewStatsService(repos.Users, repos.Posts),
+		Webhooks: service.NewWebhookService(repos.Webhooks, opts.Dispatcher),
+		Emails:   service.NewEmailService(repos.Users, repos.Verifications, opts.Mail, opts.VerificationTTL),
+		renderer: render.NewRenderer(opts.RenderCacheSize),
+	}
+	if opts.Traced {
+		s.Users = service.NewTracedUserService(s.Users)
+		s.Posts = service.NewTracedPostService(s.Posts)
+		s.Stats = service.NewTracedStatsService(s.Stats)
+		s.Webhooks = service.NewTracedWebhookService(s.Webhooks)
+		s.Emails = service.NewTracedEmailService(s.Emails)
+	}
+	return s
+}
+
+// Schema is the executable GraphQL schema over the services, with the
+// directives and complexity functions the server uses
+func (s Services) Schema() graphql.ExecutableSchema {
+	return graph.NewExecutableSchema(graph.Config{
+		Resolvers:  graph.NewResolver(s.Users, s.Posts, s.Stats, s.Webhooks, s.Emails, s.renderer),
+		Directives: graph.Directives(),
+		Complexity: graph.Complexity(),
+	})
+}
+
+// HandlerOptions configure NewHandler
+type HandlerOptions struct {
+	// Admins are user IDs given the admin role
+	Admins []string
+	// Extensions are added to the gqlgen server, e.g. a ratelimit.Limiter
+	Extensions []graphql.HandlerExtension
+	// Middleware wraps the gqlgen server inside auth and tenant resolution,
+	// e.g. ratelimit.Middleware
+	Middleware func(http.Handler) http.Handler
+}
+
+// NewHandler serves /query over schema with the POST and multipart/mixed
+// transports, naming callers with the X-User-ID header and defaulting to
+// tenant.Default. It has none of the server's infrastructure (caching, rate
+// limits, metrics, logging) unless opts add it, so tools and tests get
+// repeatable results.
+func NewHandler(schema graphql.ExecutableSchema, opts HandlerOptions) http.Handler {
+	srv := handler.New(schema)
+	// Accept: multipart/mixed selects incremental delivery for @defer and @stream
+	srv.AddTransport(stream.MultipartMixed{Boundary: "graphql"})
+	srv.AddTransport(transport.POST{})
+	for _, ext := range opts.Extensions {
+		srv.Use(ext)
+	}
+
+	h := http.Handler(srv)
+	if opts.Middleware != nil {
+		h = opts.Middleware(h)
+	}
+	h = tenant.Middleware(tenant.Options{Default: tenant.Default})(h)
+	return auth.Middleware(auth.Options{TrustUserHeader: true, Admins: opts.Admins})(h)
+}
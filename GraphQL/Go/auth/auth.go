@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UserHeader names the caller directly when TrustUserHeader is enabled (development only)
+const UserHeader = "X-User-ID"
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying the verified claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by Middleware, if any
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// UserID returns the ID of the authenticated caller (the token subject), if any
+func UserID(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// RoleAdmin is the role claim that unlocks administrative operations
+const RoleAdmin = "admin"
+
+// IsAdmin reports whether the authenticated caller holds the admin role
+func IsAdmin(ctx context.Context) bool {
+	claims, ok := ClaimsFromContext(ctx)
+	return ok && claims.Role == RoleAdmin
+}
+
+// Options control how Middleware authenticates requests
+type Options struct {
+	// Secret verifies HS256 bearer tokens; without it tokens are ignored
+	Secret []byte
+	// TrustUserHeader accepts an unverified X-User-ID header as the caller.
+	// It exists for local development and must stay off in production.
+	TrustUserHeader bool
+	// Admins are subjects granted the admin role whatever their token says
+	Admins []string
+}
+
+// Middleware verifies an optional "Authorization: Bearer <jwt>" header.
+// Requests without a token pass through anonymously; requests with a bad token are rejected.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" || len(opts.Secret) == 0 {
+				if id := r.Header.Get(UserHeader); id != "" && opts.TrustUserHeader {
+					r = r.WithContext(WithClaims(r.Context(), opts.grant(&Claims{Subject: id})))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				http.Error(w, "unsupported authorization scheme", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := VerifyHS256(token, opts.Secret, time.Now())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), opts.grant(claims))))
+		})
+	}
+}
+
+// grant applies the configured admin list to claims
+func (opts Options) grant(claims *Claims) *Claims {
+	if slices.Contains(opts.Admins, claims.Subject) {
+		claims.Role = RoleAdmin
+	}
+	return claims
+}
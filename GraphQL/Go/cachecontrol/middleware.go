@@ -0,0 +1,92 @@
+package cachecontrol
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+)
+
+// Vary lists the request headers responses depend on, so shared caches keep
+// one entry per tenant and caller
+const Vary = "Authorization, X-Tenant-ID, X-User-ID"
+
+// Middleware adds Cache-Control and ETag headers to GET queries handled by
+// next and answers a matching If-None-Match with 304 Not Modified. The
+// response is buffered so the headers can depend on what was resolved.
+// Responses with errors are marked no-store. Authenticated callers never get
+// a public policy, since what they see can depend on who they are.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Websocket upgrades also arrive as GET and must reach the transport unbuffered
+		if r.Method != http.MethodGet || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res := &result{}
+		buf := &bufferedWriter{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r.WithContext(context.WithValue(r.Context(), resultKey{}, res)))
+
+		policy, ok := res.get()
+		if !ok || buf.status != http.StatusOK {
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		if _, signedIn := auth.UserID(r.Context()); signedIn || r.Header.Get("Authorization") != "" {
+			policy.Scope = Private
+		}
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		w.Header().Set("Cache-Control", policy.Header())
+		w.Header().Set("ETag", etag)
+		w.Header().Add("Vary", Vary)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.body.Bytes())
+	})
+}
+
+// etagMatches implements the weak comparison If-None-Match uses
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the response so headers can still change after the
+// handler has written it
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
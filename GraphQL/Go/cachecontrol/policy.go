@@ -0,0 +1,186 @@
+// Package cachecontrol turns @cacheControl schema hints into HTTP caching
+// headers for GET queries. The rules follow Apollo Server's cache control:
+//
+//   - a field's own hint wins, then the hint on the type it returns
+//   - root fields and fields returning objects default to maxAge 0
+//   - scalar fields, and fields or types marked inheritMaxAge, take their parent's maxAge
+//   - the response gets the lowest maxAge of any field, and is PRIVATE if any field is
+package cachecontrol
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type Scope string
+
+const (
+	Public  Scope = "PUBLIC"
+	Private Scope = "PRIVATE"
+)
+
+// Policy is the cache policy of a whole response
+type Policy struct {
+	MaxAge int
+	Scope  Scope
+}
+
+// restrict lowers p to cover a field with the given maxAge and scope
+func (p *Policy) restrict(maxAge int, scope Scope) {
+	p.MaxAge = min(p.MaxAge, maxAge)
+	if scope == Private {
+		p.Scope = Private
+	}
+}
+
+// Header renders p as a Cache-Control value. A zero maxAge still allows
+// storage so that clients can revalidate with If-None-Match.
+func (p Policy) Header() string {
+	visibility := "public"
+	if p.Scope == Private {
+		visibility = "private"
+	}
+	if p.MaxAge <= 0 {
+		return visibility + ", no-cache"
+	}
+	return fmt.Sprintf("%s, max-age=%d", visibility, p.MaxAge)
+}
+
+// hint is one @cacheControl directive; nil fields were not given
+type hint struct {
+	maxAge        *int
+	scope         Scope
+	inheritMaxAge bool
+}
+
+func hintFrom(directives ast.DirectiveList) hint {
+	var h hint
+	d := directives.ForName("cacheControl")
+	if d == nil {
+		return h
+	}
+	if arg := d.Arguments.ForName("maxAge"); arg != nil {
+		if n, err := strconv.Atoi(arg.Value.Raw); err == nil {
+			h.maxAge = &n
+		}
+	}
+	if arg := d.Arguments.ForName("scope"); arg != nil {
+		h.scope = Scope(arg.Value.Raw)
+	}
+	if arg := d.Arguments.ForName("inheritMaxAge"); arg != nil {
+		h.inheritMaxAge = arg.Value.Raw == "true"
+	}
+	return h
+}
+
+// Extension computes the policy of each operation and hands it to Middleware.
+// Register it with srv.Use on the gqlgen handler.
+type Extension struct {
+	schema *ast.Schema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = (*Extension)(nil)
+
+func (e *Extension) ExtensionName() string {
+	return "CacheControl"
+}
+
+func (e *Extension) Validate(schema graphql.ExecutableSchema) error {
+	e.schema = schema.Schema()
+	return nil
+}
+
+func (e *Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	res, ok := ctx.Value(resultKey{}).(*result)
+	if !ok {
+		return next(ctx)
+	}
+
+	resp := next(ctx)
+	oc := graphql.GetOperationContext(ctx)
+	if resp == nil || oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return resp
+	}
+
+	policy := Policy{MaxAge: math.MaxInt, Scope: Public}
+	e.walk(oc, &policy, e.schema.Query, oc.Operation.SelectionSet, 0, true)
+	if policy.MaxAge == math.MaxInt {
+		policy.MaxAge = 0 // nothing but __typename was selected
+	}
+	res.set(policy, len(resp.Errors) > 0)
+	return resp
+}
+
+// walk restricts policy by every field selected on parent; parentMaxAge is
+// what scalar and inheriting fields fall back to
+func (e *Extension) walk(oc *graphql.OperationContext, policy *Policy, parent *ast.Definition, sel ast.SelectionSet, parentMaxAge int, root bool) {
+	for _, field := range graphql.CollectFields(oc, sel, []string{parent.Name}) {
+		if field.Name == "__typename" {
+			continue
+		}
+		def := parent.Fields.ForName(field.Name)
+		if def == nil {
+			// Introspection fields carry no hints and are never cached
+			policy.restrict(0, Public)
+			continue
+		}
+
+		returns := e.schema.Types[def.Type.Name()]
+		fieldHint := hintFrom(def.Directives)
+		typeHint := hint{}
+		composite := returns != nil && (returns.Kind == ast.Object || returns.Kind == ast.Interface || returns.Kind == ast.Union)
+		if composite {
+			typeHint = hintFrom(returns.Directives)
+		}
+
+		maxAge := 0
+		switch {
+		case fieldHint.maxAge != nil:
+			maxAge = *fieldHint.maxAge
+		case typeHint.maxAge != nil:
+			maxAge = *typeHint.maxAge
+		case fieldHint.inheritMaxAge || typeHint.inheritMaxAge || (!composite && !root):
+			maxAge = parentMaxAge
+		}
+		scope := fieldHint.scope
+		if scope == "" {
+			scope = typeHint.scope
+		}
+		policy.restrict(maxAge, scope)
+
+		if composite && len(field.Selections) > 0 {
+			e.walk(oc, policy, returns, field.Selections, maxAge, false)
+		}
+	}
+}
+
+type resultKey struct{}
+
+// result carries the computed policy from the extension back to Middleware
+type result struct {
+	mu       sync.Mutex
+	policy   Policy
+	computed bool
+	failed   bool
+}
+
+func (r *result) set(policy Policy, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy, r.computed, r.failed = policy, true, failed
+}
+
+func (r *result) get() (Policy, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.policy, r.computed && !r.failed
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/config"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/seed"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// backend is where the commands read and change data: the services over the
+// configured repositories, or a running server's GraphQL API
+type backend interface {
+	ListUsers(ctx context.Context) ([]*model.User, error)
+	GetUser(ctx context.Context, id string) (*model.User, error)
+	CreateUser(ctx context.Context, name, email string) (*model.User, error)
+	DeleteUser(ctx context.Context, id string) (*model.User, error)
+
+	// ListPosts lists every post, or only authorID's when it is set
+	ListPosts(ctx context.Context, authorID string) ([]*model.Post, error)
+	GetPost(ctx context.Context, id string) (*model.Post, error)
+	DeletePost(ctx context.Context, id string) (*model.Post, error)
+	ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error)
+}
+
+// adminSubject names the CLI as the caller of the services in direct mode
+const adminSubject = "admin-cli"
+
+// direct calls the services in-process. Every call runs as an admin in the
+// chosen tenant, so the same business rules apply as through the API.
+type direct struct {
+	users  service.UserService
+	posts  service.PostService
+	tenant string
+}
+
+// newDirect builds the services the way the server does and loads the
+// configured seed data into them
+func newDirect(cfg config.Config, tenantID string) (*direct, error) {
+	services := app.NewServices(app.NewRepositories(), app.Options{})
+	d := &direct{users: services.Users, posts: services.Posts, tenant: tenantID}
+
+	var fixtures []*seed.Fixture
+	switch cfg.SeedFile {
+	case "none":
+	case "":
+		f, err := seed.Default()
+		if err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+		fixtures = append(fixtures, f)
+	default:
+		f, err := seed.LoadFile(cfg.SeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+		fixtures = append(fixtures, f)
+	}
+	if cfg.SeedSynthetic > 0 {
+		fixtures = append(fixtures, seed.Synthetic(cfg.SeedSynthetic, 5, 1))
+	}
+	seeder := seed.NewSeeder(d.users, d.posts)
+	for _, f := range fixtures {
+		if _, err := seeder.Apply(context.Background(), f, tenant.Default); err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+	}
+	return d, nil
+}
+
+func (d *direct) ctx(ctx context.Context) context.Context {
+	ctx = tenant.WithID(ctx, d.tenant)
+	return auth.WithClaims(ctx, &auth.Claims{Subject: adminSubject, Role: auth.RoleAdmin})
+}
+
+func (d *direct) ListUsers(ctx context.Context) ([]*model.User, error) {
+	return d.users.GetAllUsers(d.ctx(ctx))
+}
+
+func (d *direct) GetUser(ctx context.Context, id string) (*model.User, error) {
+	return d.users.GetUserByID(d.ctx(ctx), id)
+}
+
+func (d *direct) CreateUser(ctx context.Context, name, email string) (*model.User, error) {
+	return d.users.CreateUser(d.ctx(ctx), model.NewUser{Name: name, Email: email})
+}
+
+func (d *direct) DeleteUser(ctx context.Context, id string) (*model.User, error) {
+	return d.users.DeleteUser(d.ctx(ctx), id)
+}
+
+func (d *direct) ListPosts(ctx context.Context, authorID string) ([]*model.Post, error) {
+	if authorID != "" {
+		if _, err := d.GetUser(ctx, authorID); err != nil {
+			return nil, err
+		}
+		return d.posts.GetPostsByUser(d.ctx(ctx), authorID)
+	}
+	return d.posts.GetAllPosts(d.ctx(ctx))
+}
+
+func (d *direct) GetPost(ctx context.Context, id string) (*model.Post, error) {
+	return d.posts.GetPostByID(d.ctx(ctx), id)
+}
+
+func (d *direct) DeletePost(ctx context.Context, id string) (*model.Post, error) {
+	return d.posts.DeletePost(d.ctx(ctx), id)
+}
+
+func (d *direct) ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error) {
+	return d.posts.ReassignPost(d.ctx(ctx), id, authorID)
+}
@@ -0,0 +1,287 @@
+// Command admin inspects and fixes users and posts for support staff.
+//
+//	admin users list
+//	admin users get <id>
+//	admin users create -name <name> -email <email>
+//	admin users delete <id>
+//	admin posts list [-author <user-id>]
+//	admin posts delete <id>
+//	admin posts reassign <id> <author-id>
+//
+// Every command takes these flags before its arguments:
+//
+//	-server URL   call a running server's /query (default $ADMIN_SERVER)
+//	-token JWT    bearer token with the admin role (default $ADMIN_TOKEN)
+//	-as ID        send X-User-ID instead, for servers run with TRUST_USER_HEADER=true (default $ADMIN_USER)
+//	-tenant ID    work in this tenant
+//	-json         print JSON instead of a table
+//	-y            do not ask before deleting or reassigning
+//
+// Without -server the commands call the services directly, as an admin, over
+// repositories built the way the server builds them. With the in-memory
+// backend that means they see only the configured seed data (SEED_FILE,
+// -seed) and changes are gone when the command exits, so direct mode is
+// mostly useful to try commands out; point -server at the deployment to fix
+// real data. Either way the service layer's rules apply: users are only
+// deleted once their posts are reassigned or deleted, and while no revision
+// names them as its editor.
+//
+// The exit status is 0 on success, 1 when the operation fails or the prompt
+// is declined, and 2 for bad usage.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/config"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+type command struct {
+	args string
+	run  func(s streams, fs *flag.FlagSet, args []string) error
+}
+
+var commands = map[string]command{
+	"users list":     {"", usersList},
+	"users get":      {"<id>", usersGet},
+	"users create":   {"-name <name> -email <email>", usersCreate},
+	"users delete":   {"<id>", usersDelete},
+	"posts list":     {"[-author <user-id>]", postsList},
+	"posts delete":   {"<id>", postsDelete},
+	"posts reassign": {"<id> <author-id>", postsReassign},
+}
+
+// errUsage reports bad arguments once the usage has been printed
+var errUsage = errors.New("usage")
+
+// streams are the process's standard files, passed in so tests can replace them
+type streams struct {
+	in       io.Reader
+	out, err io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], streams{os.Stdin, os.Stdout, os.Stderr}))
+}
+
+// run is the command without the process around it; it returns the exit status
+func run(args []string, s streams) int {
+	if len(args) < 2 {
+		usage(s.err)
+		return 2
+	}
+	name := args[0] + " " + args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		usage(s.err)
+		return 2
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(s.err)
+	fs.Usage = func() {
+		fmt.Fprintf(s.err, "usage: admin %s [flags] %s\n", name, cmd.args)
+		fs.PrintDefaults()
+	}
+
+	err := cmd.run(s, fs, args[2:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		log.New(s.err, "admin: ", 0).Print(err)
+		return 1
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	for _, name := range []string{"users list", "users get", "users create", "users delete", "posts list", "posts delete", "posts reassign"} {
+		fmt.Fprintln(w, "  "+strings.TrimSpace("admin "+name+" [flags] "+commands[name].args))
+	}
+	fmt.Fprintln(w, `run "admin <command> -h" for its flags`)
+}
+
+// env is what setup gives a command
+type env struct {
+	backend
+	std streams
+	out printer
+	yes bool
+}
+
+// setup adds the shared flags, parses args, checks the number of positional
+// arguments and connects to the backend
+func setup(s streams, fs *flag.FlagSet, args []string, nargs int) (*env, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	server := fs.String("server", os.Getenv("ADMIN_SERVER"), "GraphQL endpoint of a running server, e.g. http://localhost:8080/query (default: call the services directly)")
+	token := fs.String("token", os.Getenv("ADMIN_TOKEN"), "bearer token with the admin role, for -server")
+	as := fs.String("as", os.Getenv("ADMIN_USER"), "admin user ID sent as X-User-ID, for a -server run with TRUST_USER_HEADER=true")
+	tenantID := fs.String("tenant", "", "tenant to work in (default: the server's default)")
+	fs.StringVar(&cfg.SeedFile, "seed", cfg.SeedFile, `without -server, fixture file to load first (YAML or JSON), or "none"`)
+	jsonOut := fs.Bool("json", false, "print JSON instead of a table")
+	yes := fs.Bool("y", false, "do not ask before deleting or reassigning")
+	if err := fs.Parse(args); err != nil {
+		// The flag package has printed the problem and the usage
+		return nil, errUsage
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	e := &env{std: s, out: printer{w: s.out, json: *jsonOut}, yes: *yes}
+	if *server != "" {
+		e.backend = newRemote(*server, *token, *as, *tenantID)
+		return e, nil
+	}
+	if *tenantID == "" {
+		*tenantID = tenant.Default
+	}
+	if e.backend, err = newDirect(cfg, *tenantID); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// confirm fails unless -y was given or the user agrees to prompt
+func (e *env) confirm(prompt string) error {
+	if !e.yes && !confirm(e.std.in, e.std.err, prompt) {
+		return errors.New("aborted; pass -y to skip the prompt")
+	}
+	return nil
+}
+
+func usersList(s streams, fs *flag.FlagSet, args []string) error {
+	e, err := setup(s, fs, args, 0)
+	if err != nil {
+		return err
+	}
+	users, err := e.ListUsers(context.Background())
+	if err != nil {
+		return err
+	}
+	return e.out.users(users...)
+}
+
+func usersGet(s streams, fs *flag.FlagSet, args []string) error {
+	e, err := setup(s, fs, args, 1)
+	if err != nil {
+		return err
+	}
+	user, err := e.GetUser(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return e.out.users(user)
+}
+
+func usersCreate(s streams, fs *flag.FlagSet, args []string) error {
+	name := fs.String("name", "", "display name (required)")
+	email := fs.String("email", "", "email address (required)")
+	e, err := setup(s, fs, args, 0)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*name) == "" || strings.TrimSpace(*email) == "" {
+		fs.Usage()
+		return errUsage
+	}
+	user, err := e.CreateUser(context.Background(), *name, *email)
+	if err != nil {
+		return err
+	}
+	return e.out.users(user)
+}
+
+func usersDelete(s streams, fs *flag.FlagSet, args []string) error {
+	e, err := setup(s, fs, args, 1)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	user, err := e.GetUser(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := e.confirm(fmt.Sprintf("Delete user %s (%s <%s>)?", user.ID, user.Name, user.Email)); err != nil {
+		return err
+	}
+
+	deleted, err := e.DeleteUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	return e.out.users(deleted)
+}
+
+func postsList(s streams, fs *flag.FlagSet, args []string) error {
+	author := fs.String("author", "", "only list posts by this user ID")
+	e, err := setup(s, fs, args, 0)
+	if err != nil {
+		return err
+	}
+	posts, err := e.ListPosts(context.Background(), *author)
+	if err != nil {
+		return err
+	}
+	return e.out.posts(posts...)
+}
+
+func postsDelete(s streams, fs *flag.FlagSet, args []string) error {
+	e, err := setup(s, fs, args, 1)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	post, err := e.GetPost(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := e.confirm(fmt.Sprintf("Delete post %s %q by %s?", post.ID, post.Title, post.AuthorID)); err != nil {
+		return err
+	}
+
+	deleted, err := e.DeletePost(ctx, post.ID)
+	if err != nil {
+		return err
+	}
+	return e.out.posts(deleted)
+}
+
+func postsReassign(s streams, fs *flag.FlagSet, args []string) error {
+	e, err := setup(s, fs, args, 2)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	post, err := e.GetPost(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	author, err := e.GetUser(ctx, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if err := e.confirm(fmt.Sprintf("Reassign post %s %q from %s to %s (%s)?", post.ID, post.Title, post.AuthorID, author.ID, author.Name)); err != nil {
+		return err
+	}
+
+	updated, err := e.ReassignPost(ctx, post.ID, author.ID)
+	if err != nil {
+		return err
+	}
+	return e.out.posts(updated)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// newServer serves /query over users u1 (author of post p1), u2 and admin,
+// trusting X-User-ID the way a server run with TRUST_USER_HEADER=true does
+func newServer(t *testing.T) string {
+	t.Helper()
+	repos := app.NewRepositories()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	for _, u := range []*model.User{
+		{ID: "u1", Name: "Alice", Email: "alice@example.com"},
+		{ID: "u2", Name: "Bob", Email: "bob@example.com"},
+		{ID: "admin", Name: "Admin", Email: "admin@example.com"},
+	} {
+		if err := repos.Users.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.Posts.Create(ctx, &model.Post{ID: "p1", Title: "Hello", AuthorID: "u1", Status: model.PostStatusDraft, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	services := app.NewServices(repos, app.Options{})
+	srv := httptest.NewServer(app.NewHandler(services.Schema(), app.HandlerOptions{Admins: []string{"admin"}}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/query"
+}
+
+// runAdmin runs the command with stdin as its input and returns the exit
+// status with what it wrote to stdout and stderr
+func runAdmin(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, streams{strings.NewReader(stdin), &stdout, &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no command", nil, "usage:\n  admin users list"},
+		{"unknown command", []string{"users", "rename"}, "usage:\n  admin users list"},
+		{"missing argument", []string{"users", "get"}, "usage: admin users get [flags] <id>"},
+		{"extra argument", []string{"posts", "reassign", "p1", "u2", "u3"}, "usage: admin posts reassign [flags] <id> <author-id>"},
+		{"unknown flag", []string{"users", "list", "-verbose"}, "flag provided but not defined: -verbose"},
+		{"help", []string{"posts", "list", "-h"}, "-author string"},
+		{"create without email", []string{"users", "create", "-seed", "none", "-name", "Carol"}, "usage: admin users create [flags] -name <name> -email <email>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runAdmin(t, "", tt.args...)
+			if code != 2 {
+				t.Errorf("exit status %d, want 2", code)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want nothing", stdout)
+			}
+			if !strings.Contains(stderr, tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Remote(t *testing.T) {
+	url := newServer(t)
+	admin := func(args ...string) []string {
+		return append(args[:2:2], append([]string{"-server", url, "-as", "admin"}, args[2:]...)...)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"list", admin("users", "list"), "", 0, "bob@example.com", ""},
+		{"unknown user", admin("users", "get", "u9"), "", 1, "", "admin: failed to get user: user with id u9 not found"},
+		{"not an admin", []string{"users", "delete", "-server", url, "-as", "u1", "-y", "u2"}, "", 1, "", "only admins may delete users"},
+		{"anonymous", []string{"users", "delete", "-server", url, "-y", "u2"}, "", 1, "", "only admins may delete users"},
+		{"user with posts", admin("users", "delete", "-y", "u1"), "", 1, "", "still has 1 posts"},
+		{"declined", admin("users", "delete", "u2"), "n\n", 1, "", "Delete user u2 (Bob <bob@example.com>)? [y/N] admin: aborted"},
+		{"no answer", admin("posts", "delete", "p1"), "", 1, "", "aborted"},
+		{"confirmed", admin("users", "delete", "u2"), "yes\n", 0, "u2  Bob", ""},
+		{"reassign to unknown author", admin("posts", "reassign", "-y", "p1", "u9"), "", 1, "", "user with id u9 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runAdmin(t, tt.stdin, tt.args...)
+			if code != tt.code {
+				t.Errorf("exit status %d, want %d (stderr %q)", code, tt.code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_ReassignThenDelete(t *testing.T) {
+	url := newServer(t)
+	remote := []string{"-server", url, "-as", "admin", "-y"}
+
+	code, stdout, stderr := runAdmin(t, "", append([]string{"posts", "reassign"}, append(remote, "-json", "p1", "u2")...)...)
+	if code != 0 {
+		t.Fatalf("reassign: exit status %d: %s", code, stderr)
+	}
+	var posts []*model.Post
+	if err := json.Unmarshal([]byte(stdout), &posts); err != nil || len(posts) != 1 || posts[0].AuthorID != "u2" {
+		t.Fatalf("reassign printed %q (%v), want p1 by u2", stdout, err)
+	}
+
+	if code, _, stderr := runAdmin(t, "", append([]string{"users", "delete"}, append(remote, "u1")...)...); code != 0 {
+		t.Fatalf("delete: exit status %d: %s", code, stderr)
+	}
+	if code, _, _ := runAdmin(t, "", append([]string{"users", "get"}, append(remote, "u1")...)...); code != 1 {
+		t.Errorf("get of the deleted user: exit status %d, want 1", code)
+	}
+}
+
+func TestRun_Direct(t *testing.T) {
+	code, stdout, stderr := runAdmin(t, "", "users", "create", "-seed", "none", "-json", "-name", "Carol", "-email", "carol@example.com")
+	if code != 0 {
+		t.Fatalf("exit status %d: %s", code, stderr)
+	}
+	var users []*model.User
+	if err := json.Unmarshal([]byte(stdout), &users); err != nil || len(users) != 1 || users[0].Email != "carol@example.com" {
+		t.Errorf("printed %q (%v), want Carol", stdout, err)
+	}
+
+	if code, _, stderr := runAdmin(t, "", "users", "create", "-seed", "none", "-name", "Carol", "-email", "not an address"); code != 1 || !strings.Contains(stderr, "email") {
+		t.Errorf("invalid email: exit status %d, stderr %q; want 1 and the reason", code, stderr)
+	}
+	if code, _, stderr := runAdmin(t, "", "users", "list", "-seed", "missing.yaml"); code != 1 || !strings.Contains(stderr, "seed") {
+		t.Errorf("missing seed file: exit status %d, stderr %q; want 1 and the reason", code, stderr)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+)
+
+// printer writes command results as a table or, with -json, as indented JSON
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p printer) users(users ...*model.User) error {
+	if p.json {
+		return p.encode(users)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tEMAIL")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", u.ID, u.Name, u.Email)
+	}
+	return tw.Flush()
+}
+
+func (p printer) posts(posts ...*model.Post) error {
+	if p.json {
+		return p.encode(posts)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tAUTHOR\tCREATED")
+	for _, post := range posts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", post.ID, truncate(post.Title, 48), post.Status, post.AuthorID, post.CreatedAt.Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+func (p printer) encode(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// confirm asks on w (stderr) whether to go ahead, reading the answer from r
+// (stdin). Anything but "y" or "yes", including end of input, declines.
+func confirm(r io.Reader, w io.Writer, prompt string) bool {
+	fmt.Fprintf(w, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// remote calls a running server's GraphQL API. The caller needs the admin
+// role there: a bearer token with the admin role claim, or a user listed in
+// ADMIN_USERS named with -as on a server run with TRUST_USER_HEADER=true,
+// which trusts the X-User-ID header.
+type remote struct {
+	url    string
+	token  string
+	as     string
+	tenant string
+	client *http.Client
+}
+
+func newRemote(url, token, as, tenantID string) *remote {
+	return &remote{url: url, token: token, as: as, tenant: tenantID, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// graphqlError is one entry of a response's errors list
+type graphqlError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// do runs a GraphQL operation and decodes its data into out
+func (r *remote) do(ctx context.Context, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	if r.as != "" {
+		req.Header.Set(auth.UserHeader, r.as)
+	}
+	if r.tenant != "" {
+		req.Header.Set(tenant.DefaultHeader, r.tenant)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("%s: %s", r.url, resp.Status)
+	}
+	if len(res.Errors) > 0 {
+		msgs := make([]string, len(res.Errors))
+		for i, e := range res.Errors {
+			msgs[i] = e.Message
+			if e.Extensions.Code != "" {
+				msgs[i] += " (" + e.Extensions.Code + ")"
+			}
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", r.url, resp.Status)
+	}
+	return json.Unmarshal(res.Data, out)
+}
+
+const (
+	userFields = `id name email`
+	postFields = `id title status createdAt publishedAt author { id }`
+)
+
+// apiPost is a post as the API returns it, with the author as an object
+type apiPost struct {
+	model.Post
+	Author struct{ ID string } `json:"author"`
+}
+
+func (p *apiPost) model() *model.Post {
+	if p == nil {
+		return nil
+	}
+	post := p.Post
+	post.AuthorID = p.Author.ID
+	return &post
+}
+
+func apiPosts(in []*apiPost) []*model.Post {
+	out := make([]*model.Post, len(in))
+	for i, p := range in {
+		out[i] = p.model()
+	}
+	return out
+}
+
+func (r *remote) ListUsers(ctx context.Context) ([]*model.User, error) {
+	var data struct{ Users []*model.User }
+	err := r.do(ctx, `query { users { `+userFields+` } }`, nil, &data)
+	return data.Users, err
+}
+
+func (r *remote) GetUser(ctx context.Context, id string) (*model.User, error) {
+	var data struct{ User *model.User }
+	if err := r.do(ctx, `query($id: ID!) { user(id: $id) { `+userFields+` } }`, map[string]any{"id": id}, &data); err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("user with id %s %w", id, repository.ErrNotFound)
+	}
+	return data.User, nil
+}
+
+func (r *remote) CreateUser(ctx context.Context, name, email string) (*model.User, error) {
+	var data struct{ CreateUser *model.User }
+	err := r.do(ctx, `mutation($input: NewUser!) { createUser(input: $input) { `+userFields+` } }`,
+		map[string]any{"input": map[string]any{"name": name, "email": email}}, &data)
+	return data.CreateUser, err
+}
+
+func (r *remote) DeleteUser(ctx context.Context, id string) (*model.User, error) {
+	var data struct{ DeleteUser *model.User }
+	err := r.do(ctx, `mutation($id: ID!) { deleteUser(id: $id) { `+userFields+` } }`, map[string]any{"id": id}, &data)
+	return data.DeleteUser, err
+}
+
+func (r *remote) ListPosts(ctx context.Context, authorID string) ([]*model.Post, error) {
+	if authorID == "" {
+		var data struct{ Posts []*apiPost }
+		err := r.do(ctx, `query { posts { `+postFields+` } }`, nil, &data)
+		return apiPosts(data.Posts), err
+	}
+	var data struct {
+		User *struct{ Posts []*apiPost }
+	}
+	if err := r.do(ctx, `query($id: ID!) { user(id: $id) { posts { `+postFields+` } } }`, map[string]any{"id": authorID}, &data); err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("user with id %s %w", authorID, repository.ErrNotFound)
+	}
+	return apiPosts(data.User.Posts), nil
+}
+
+// GetPost searches the post list, since the API has no query for one post
+func (r *remote) GetPost(ctx context.Context, id string) (*model.Post, error) {
+	posts, err := r.ListPosts(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("post with id %s %w", id, repository.ErrNotFound)
+}
+
+func (r *remote) DeletePost(ctx context.Context, id string) (*model.Post, error) {
+	var data struct{ DeletePost *apiPost }
+	err := r.do(ctx, `mutation($id: ID!) { deletePost(id: $id) { `+postFields+` } }`, map[string]any{"id": id}, &data)
+	return data.DeletePost.model(), err
+}
+
+func (r *remote) ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error) {
+	var data struct{ ReassignPost *apiPost }
+	err := r.do(ctx, `mutation($id: ID!, $authorId: ID!) { reassignPost(id: $id, authorId: $authorId) { `+postFields+` } }`,
+		map[string]any{"id": id, "authorId": authorID}, &data)
+	return data.ReassignPost.model(), err
+}
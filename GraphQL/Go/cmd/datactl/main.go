@@ -0,0 +1,192 @@
+// Command datactl exports and imports API data as NDJSON.
+//
+//	datactl export [-server URL] [-o file] [-tenant a,b]
+//	datactl import [-server URL] [-dry-run] [-upsert] [-tenant t] file|-
+//
+// With -server (default $ADMIN_SERVER) it moves the data of a running server
+// through its /admin/data endpoint, as an admin: pass a bearer token with the
+// admin role with -token ($ADMIN_TOKEN), or a user listed in ADMIN_USERS with
+// -as ($ADMIN_USER) on a server run with TRUST_USER_HEADER=true, which trusts
+// the X-User-ID header.
+// The server works on one tenant at a time, chosen with -tenant and sent as
+// X-Tenant-ID; a token naming a tenant is limited to that one, and an import
+// writes every record into the chosen tenant.
+//
+// Without -server the repositories are built the way the server builds them.
+// With the in-memory backend that means a run only sees the configured seed
+// data (SEED_FILE, -seed) and anything it imports is gone when it exits, so
+// local mode is only useful to check a file with -dry-run or to export the
+// seed data.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/config"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/seed"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/transfer"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("datactl: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "export":
+		runExport(os.Args[2:])
+	case "import":
+		runImport(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: datactl export [-server URL] [-o file] [-tenant a,b]")
+	fmt.Fprintln(os.Stderr, "       datactl import [-server URL] [-dry-run] [-upsert] [-tenant t] file|-")
+	os.Exit(2)
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("o", "-", `output file, or "-" for stdout`)
+	tenants := fs.String("tenant", "", "comma-separated tenants to export (default all); with -server, the one tenant to export (default the server's)")
+	store := setup(fs, args)
+
+	w := io.Writer(os.Stdout)
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var ids []string
+	if *tenants != "" {
+		ids = strings.Split(*tenants, ",")
+	}
+	counts, err := store.Export(context.Background(), w, ids)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("exported %d users, %d posts, %d revisions and %d follows",
+		counts.Users, counts.Posts, counts.Revisions, counts.Follows)
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var opts transfer.Options
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "validate and report without writing")
+	fs.BoolVar(&opts.Upsert, "upsert", false, "replace users and posts that already exist")
+	fs.StringVar(&opts.Tenant, "tenant", "", "import every record into this tenant (with -server, default the server's)")
+	store := setup(fs, args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	r := io.Reader(os.Stdin)
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	report, err := store.Import(context.Background(), r, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, e := range report.Errors {
+		log.Print(e)
+	}
+	if len(report.Errors) > 0 {
+		log.Fatalf("%d errors, nothing imported", len(report.Errors))
+	}
+
+	verb := "imported"
+	if opts.DryRun {
+		verb = "dry run: would import"
+	}
+	c, u, s := report.Created, report.Updated, report.Skipped
+	log.Printf("%s users %d new/%d updated, posts %d new/%d updated, revisions %d new, follows %d new (%d revisions and %d follows already present)",
+		verb, c.Users, u.Users, c.Posts, u.Posts, c.Revisions, c.Follows, s.Revisions, s.Follows)
+}
+
+// store is where data is exported from and imported to
+type store interface {
+	Export(ctx context.Context, w io.Writer, tenants []string) (transfer.Counts, error)
+	Import(ctx context.Context, r io.Reader, opts transfer.Options) (*transfer.Report, error)
+}
+
+// local exports and imports through repositories in this process
+type local struct {
+	repos transfer.Repositories
+}
+
+func (l local) Export(ctx context.Context, w io.Writer, tenants []string) (transfer.Counts, error) {
+	return transfer.Export(ctx, l.repos, w, tenants)
+}
+
+func (l local) Import(ctx context.Context, r io.Reader, opts transfer.Options) (*transfer.Report, error) {
+	return transfer.Import(ctx, l.repos, r, opts)
+}
+
+// setup parses the subcommand flags, then connects to the server or builds
+// the repositories and loads the configured seed data into them
+func setup(fs *flag.FlagSet, args []string) store {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	server := fs.String("server", os.Getenv("ADMIN_SERVER"), "base URL of a running server, e.g. http://localhost:8080 (default: local in-memory repositories)")
+	token := fs.String("token", os.Getenv("ADMIN_TOKEN"), "bearer token with the admin role, for -server")
+	as := fs.String("as", os.Getenv("ADMIN_USER"), "admin user ID sent as X-User-ID, for a -server run with TRUST_USER_HEADER=true")
+	fs.StringVar(&cfg.SeedFile, "seed", cfg.SeedFile, `without -server, fixture file to load first (YAML or JSON), or "none"`)
+	fs.Parse(args)
+
+	if *server != "" {
+		return newRemote(*server, *token, *as)
+	}
+
+	stores := app.NewRepositories()
+	repos := transfer.Repositories{
+		Users:     stores.Users,
+		Posts:     stores.Posts,
+		Revisions: stores.Revisions,
+		Follows:   stores.Follows,
+	}
+
+	var f *seed.Fixture
+	switch cfg.SeedFile {
+	case "none":
+		return local{repos}
+	case "":
+		f, err = seed.Default()
+	default:
+		f, err = seed.LoadFile(cfg.SeedFile)
+	}
+	if err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+	services := app.NewServices(stores, app.Options{})
+	seeder := seed.NewSeeder(services.Users, services.Posts)
+	if _, err := seeder.Apply(context.Background(), f, tenant.Default); err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+	return local{repos}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+)
+
+// Options control a run
+type Options struct {
+	URL     string
+	Headers http.Header
+	// Rate is the target requests per second; 0 runs closed-loop, each of
+	// Concurrency workers sending its next request as soon as the last returns
+	Rate        float64
+	Concurrency int
+	Warmup      time.Duration
+	Duration    time.Duration
+	Timeout     time.Duration
+	Seed        uint64
+}
+
+// sample is one completed request
+type sample struct {
+	op      *Operation
+	start   time.Time
+	latency time.Duration
+	// code is empty for a successful response
+	code string
+}
+
+// recorder keeps one worker's samples so workers never share a lock
+type recorder struct {
+	samples []sample
+}
+
+type bench struct {
+	opts     Options
+	workload *Workload
+	client   *http.Client
+	// dropped counts requests the rate could not be held for because every worker was busy
+	dropped atomic.Int64
+}
+
+func newBench(w *Workload, opts Options) *bench {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = opts.Concurrency
+	return &bench{
+		opts:     opts,
+		workload: w,
+		client:   &http.Client{Transport: transport, Timeout: opts.Timeout},
+	}
+}
+
+// run sends requests for the warm-up plus the measured duration and returns
+// the samples of requests started after the warm-up
+func (b *bench) run(ctx context.Context) []sample {
+	start := time.Now()
+	measureFrom := start.Add(b.opts.Warmup)
+	ctx, cancel := context.WithDeadline(ctx, measureFrom.Add(b.opts.Duration))
+	defer cancel()
+
+	// In rate mode a pacer hands out send times; closed-loop workers send back to back
+	var schedule chan time.Time
+	if b.opts.Rate > 0 {
+		schedule = make(chan time.Time, b.opts.Concurrency)
+		go b.pace(ctx, start, schedule)
+	}
+
+	recorders := make([]*recorder, b.opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		rec := &recorder{}
+		recorders[i] = rec
+		rng := rand.New(rand.NewPCG(b.opts.Seed, uint64(i)))
+		wg.Go(func() {
+			for {
+				sendAt := time.Now()
+				if schedule != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case sendAt = <-schedule:
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+
+				op := b.workload.pick(rng)
+				s := b.send(ctx, op, sendAt)
+				if s.code == codeCanceled {
+					// Cut short by the end of the run, not a server failure
+					return
+				}
+				if !s.start.Before(measureFrom) {
+					rec.samples = append(rec.samples, s)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	var samples []sample
+	for _, rec := range recorders {
+		samples = append(samples, rec.samples...)
+	}
+	return samples
+}
+
+// pace releases requests at the target rate. A request that finds every
+// worker busy is dropped rather than queued, so an overloaded server shows
+// up as a shortfall in throughput instead of an ever-growing backlog.
+func (b *bench) pace(ctx context.Context, start time.Time, schedule chan<- time.Time) {
+	interval := time.Duration(float64(time.Second) / b.opts.Rate)
+	for n := int64(0); ; n++ {
+		at := start.Add(time.Duration(n) * interval)
+		if d := time.Until(at); d > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(d):
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+		select {
+		case schedule <- at:
+		default:
+			if !at.Before(start.Add(b.opts.Warmup)) {
+				b.dropped.Add(1)
+			}
+		}
+	}
+}
+
+// send runs op and classifies the response. Latency is measured from sendAt,
+// which in rate mode is when the request was due, so time spent waiting for
+// a free worker is not hidden.
+func (b *bench) send(ctx context.Context, op *Operation, sendAt time.Time) sample {
+	code := b.do(ctx, op)
+	return sample{op: op, start: sendAt, latency: time.Since(sendAt), code: code}
+}
+
+// codeCanceled marks a request abandoned because the run ended
+const codeCanceled = "CANCELED"
+
+func (b *bench) do(ctx context.Context, op *Operation) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.opts.URL, bytes.NewReader(op.body))
+	if err != nil {
+		return "REQUEST_ERROR"
+	}
+	req.Header = b.opts.Headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	if op.As != "" {
+		req.Header.Set(auth.UserHeader, op.As)
+	}
+	for k, v := range op.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return codeCanceled
+		}
+		var timeout interface{ Timeout() bool }
+		if errors.As(err, &timeout) && timeout.Timeout() {
+			return "TIMEOUT"
+		}
+		return "TRANSPORT_ERROR"
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return codeCanceled
+		}
+		return "TRANSPORT_ERROR"
+	}
+	return errorCode(resp.StatusCode, body)
+}
+
+// errorCode names what went wrong with a response, or returns "" for a
+// response without errors. GraphQL errors are keyed by their first error's
+// extensions.code; a failed HTTP response without one by its status.
+func errorCode(status int, body []byte) string {
+	var res struct {
+		Errors []struct {
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &res); err == nil && len(res.Errors) > 0 {
+		if code := res.Errors[0].Extensions.Code; code != "" {
+			return code
+		}
+		if status == http.StatusOK {
+			return "UNCODED"
+		}
+	}
+	if status != http.StatusOK {
+		return fmt.Sprintf("HTTP_%d", status)
+	}
+	if len(res.Errors) == 0 && !json.Valid(body) {
+		return "INVALID_RESPONSE"
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/seed"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// readerID is a seeded user who follows everyone, so the default mix's feed
+// operation has posts to page through
+const readerID = "bench-reader"
+
+// adminID is given the admin role so the default mix can read stats
+const adminID = "bench-admin"
+
+// startInProcess serves the executable schema over synthetic in-memory data
+// on a loopback port. It runs the resolvers, services and repositories the
+// server uses but none of the middleware around them (caching, rate limits,
+// tracing, logging), so results measure the GraphQL layer alone and are
+// repeatable across runs with the same seed.
+func startInProcess(users, postsPerUser int, seedValue uint64) (*httptest.Server, error) {
+	repos := app.NewRepositories()
+	services := app.NewServices(repos, app.Options{})
+	if err := seedData(services.Users, services.Posts, repos.Users, repos.Follows, users, postsPerUser, seedValue); err != nil {
+		return nil, err
+	}
+	return httptest.NewServer(app.NewHandler(services.Schema(), app.HandlerOptions{Admins: []string{adminID}})), nil
+}
+
+func seedData(users service.UserService, posts service.PostService, userRepo repository.UserRepository, followRepo repository.FollowRepository, n, postsPerUser int, seedValue uint64) error {
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	f := seed.Synthetic(n, postsPerUser, seedValue)
+	if _, err := seed.NewSeeder(users, posts).Apply(ctx, f, tenant.Default); err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	all, err := userRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := userRepo.Create(ctx, &model.User{ID: readerID, Name: "Benchmark Reader", Email: "bench-reader@example.test"}); err != nil {
+		return fmt.Errorf("seed reader: %w", err)
+	}
+	for _, u := range all {
+		if err := followRepo.Follow(ctx, readerID, u.ID); err != nil {
+			return fmt.Errorf("seed reader: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Command gqlbench replays a weighted mix of GraphQL operations against /query
+// and reports latency percentiles, throughput and errors by code.
+//
+//	gqlbench [-url http://host/query | -inprocess] [-workload mix.yaml]
+//	         [-c 16] [-rate 500] [-warmup 5s] [-d 30s] [-o report.json|report.csv|-]
+//
+// Without -rate, -c workers each send their next request as soon as the last
+// one returns (closed loop). With -rate, requests are sent at that rate by up
+// to -c workers (open loop); a request that finds every worker busy is dropped
+// and counted, and latency includes any wait for a worker. Requests started
+// during the warm-up are sent but not measured.
+//
+// The workload is a YAML or JSON file listing operations with a weight, a
+// query (or a .graphql file), variables and headers; see default.yaml, which
+// is used when -workload is not given. Errors are counted by the first
+// GraphQL error's extensions.code, by HTTP status when there is none
+// (HTTP_503), or as TIMEOUT or TRANSPORT_ERROR.
+//
+// -inprocess starts the schema on a loopback port over synthetic in-memory
+// data (-users users with -posts posts each, from -seed) instead of calling a
+// running server, which makes local runs repeatable. The exit status is 1 when
+// -max-error-rate is exceeded, and 2 on usage errors.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// headerFlags collects repeated -H "Name: value" flags
+type headerFlags http.Header
+
+func (h headerFlags) String() string { return "" }
+
+func (h headerFlags) Set(v string) error {
+	name, value, ok := strings.Cut(v, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("want \"Name: value\", got %q", v)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gqlbench: ")
+
+	var opts Options
+	headers := headerFlags{}
+	flag.StringVar(&opts.URL, "url", "http://localhost:8080/query", "GraphQL endpoint to benchmark")
+	inProcess := flag.Bool("inprocess", false, "benchmark an in-process server over synthetic data instead of -url")
+	users := flag.Int("users", 100, "synthetic users for -inprocess")
+	posts := flag.Int("posts", 5, "synthetic posts per user for -inprocess")
+	workload := flag.String("workload", "", "YAML or JSON file with the operation mix (default: built-in read mix)")
+	flag.IntVar(&opts.Concurrency, "c", 16, "concurrent workers")
+	flag.Float64Var(&opts.Rate, "rate", 0, "target requests per second (default: as fast as -c workers allow)")
+	flag.DurationVar(&opts.Warmup, "warmup", 5*time.Second, "warm-up period excluded from the results")
+	flag.DurationVar(&opts.Duration, "d", 30*time.Second, "measured duration")
+	flag.DurationVar(&opts.Timeout, "timeout", 10*time.Second, "per-request timeout")
+	flag.Uint64Var(&opts.Seed, "seed", 1, "seed for the operation mix and synthetic data")
+	flag.Var(headers, "H", `header sent with every request, e.g. "X-API-Key: k1" (repeatable)`)
+	out := flag.String("o", "", `also write a report to this .json or .csv file, or "-" to print only JSON`)
+	maxErrorRate := flag.Float64("max-error-rate", 1, "exit 1 when more than this fraction of requests fail")
+	flag.Parse()
+	if flag.NArg() > 0 || opts.Concurrency < 1 || opts.Rate < 0 || opts.Duration <= 0 || opts.Warmup < 0 || *users < 1 || *posts < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts.Headers = http.Header(headers)
+
+	w, err := loadWorkload(*workload)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	target := opts.URL
+	if *inProcess {
+		srv, err := startInProcess(*users, *posts, opts.Seed)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer srv.Close()
+		opts.URL = srv.URL + "/query"
+		target = fmt.Sprintf("in-process (%d users, %d posts each)", *users, *posts)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	b := newBench(w, opts)
+	samples := b.run(ctx)
+	if ctx.Err() != nil {
+		log.Print("interrupted; reporting the requests made so far")
+	}
+
+	r := newReport(target, opts, w, samples, b.dropped.Load())
+	if *out != "-" {
+		printText(os.Stdout, r)
+	}
+	if *out != "" {
+		if err := writeReport(*out, r); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if r.Total.Requests > 0 && r.Total.ErrorRate > *maxErrorRate {
+		log.Printf("error rate %.2f%% is above -max-error-rate %.2f%%", r.Total.ErrorRate*100, *maxErrorRate*100)
+		os.Exit(1)
+	}
+}
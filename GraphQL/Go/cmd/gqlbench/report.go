@@ -0,0 +1,200 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the result of a run. Latencies are in milliseconds.
+type Report struct {
+	Target      string  `json:"target"`
+	Mode        string  `json:"mode"` // "rate" or "concurrency"
+	Rate        float64 `json:"rate,omitempty"`
+	Concurrency int     `json:"concurrency"`
+	Warmup      string  `json:"warmup"`
+	Duration    string  `json:"duration"`
+	// Dropped counts requests skipped because every worker was busy (rate mode)
+	Dropped    int64   `json:"dropped"`
+	Total      Stats   `json:"total"`
+	Operations []Stats `json:"operations"`
+}
+
+// Stats summarise the requests of one operation, or of all of them
+type Stats struct {
+	Operation string  `json:"operation"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	// Throughput is requests per second over the measured duration
+	Throughput float64        `json:"throughput"`
+	Latency    Latency        `json:"latency"`
+	ErrorCodes map[string]int `json:"errorCodes"`
+}
+
+type Latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+func newReport(target string, opts Options, w *Workload, samples []sample, dropped int64) Report {
+	r := Report{
+		Target:      target,
+		Mode:        "concurrency",
+		Concurrency: opts.Concurrency,
+		Warmup:      opts.Warmup.String(),
+		Duration:    opts.Duration.String(),
+		Dropped:     dropped,
+		Total:       summarise("total", samples, opts.Duration),
+	}
+	if opts.Rate > 0 {
+		r.Mode, r.Rate = "rate", opts.Rate
+	}
+	byOp := make(map[*Operation][]sample)
+	for _, s := range samples {
+		byOp[s.op] = append(byOp[s.op], s)
+	}
+	for i := range w.Operations {
+		op := &w.Operations[i]
+		if op.weight > 0 {
+			r.Operations = append(r.Operations, summarise(op.Name, byOp[op], opts.Duration))
+		}
+	}
+	return r
+}
+
+func summarise(name string, samples []sample, window time.Duration) Stats {
+	st := Stats{Operation: name, Requests: len(samples), ErrorCodes: map[string]int{}}
+	if len(samples) == 0 {
+		return st
+	}
+	latencies := make([]time.Duration, len(samples))
+	var sum time.Duration
+	for i, s := range samples {
+		latencies[i] = s.latency
+		sum += s.latency
+		if s.code != "" {
+			st.Errors++
+			st.ErrorCodes[s.code]++
+		}
+	}
+	slices.Sort(latencies)
+
+	st.ErrorRate = float64(st.Errors) / float64(st.Requests)
+	st.Throughput = float64(st.Requests) / window.Seconds()
+	st.Latency = Latency{
+		Min:  ms(latencies[0]),
+		Mean: ms(sum / time.Duration(len(latencies))),
+		P50:  ms(percentile(latencies, 50)),
+		P90:  ms(percentile(latencies, 90)),
+		P95:  ms(percentile(latencies, 95)),
+		P99:  ms(percentile(latencies, 99)),
+		Max:  ms(latencies[len(latencies)-1]),
+	}
+	return st
+}
+
+// percentile uses the nearest-rank method on sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func printText(w io.Writer, r Report) {
+	load := fmt.Sprintf("%d workers", r.Concurrency)
+	if r.Mode == "rate" {
+		load = fmt.Sprintf("%g req/s with up to %d workers", r.Rate, r.Concurrency)
+	}
+	fmt.Fprintf(w, "Target %s: %s for %s after a %s warm-up\n\n", r.Target, load, r.Duration, r.Warmup)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\terrors\treq/s\tmean\tp50\tp90\tp95\tp99\tmax\t")
+	for _, st := range slices.Concat(r.Operations, []Stats{r.Total}) {
+		l := st.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			st.Operation, st.Requests, st.Errors, st.Throughput, l.Mean, l.P50, l.P90, l.P95, l.P99, l.Max)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nLatencies in milliseconds.")
+
+	if r.Dropped > 0 {
+		fmt.Fprintf(w, "%d requests were dropped because every worker was busy; raise -c or lower -rate.\n", r.Dropped)
+	}
+	if len(r.Total.ErrorCodes) > 0 {
+		fmt.Fprintln(w, "\nErrors by code")
+		for _, code := range sortedCodes(r.Total.ErrorCodes) {
+			fmt.Fprintf(w, "  %-28s %d\n", code, r.Total.ErrorCodes[code])
+		}
+	}
+}
+
+// sortedCodes orders codes by count, most frequent first
+func sortedCodes(codes map[string]int) []string {
+	return slices.SortedFunc(maps.Keys(codes), func(a, b string) int {
+		return cmp.Or(codes[b]-codes[a], strings.Compare(a, b))
+	})
+}
+
+// writeReport writes r as JSON or CSV, chosen by the file extension; "-"
+// prints JSON to stdout
+func writeReport(path string, r Report) error {
+	w := io.Writer(os.Stdout)
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return writeCSV(w, r)
+	case ".json", "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	default:
+		return fmt.Errorf("unsupported report format %q (want .json or .csv)", filepath.Ext(path))
+	}
+}
+
+// writeCSV writes one row per operation and a final total row. Error codes
+// share a column as code=count pairs.
+func writeCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"operation", "requests", "errors", "error_rate", "throughput",
+		"min_ms", "mean_ms", "p50_ms", "p90_ms", "p95_ms", "p99_ms", "max_ms", "error_codes"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, st := range slices.Concat(r.Operations, []Stats{r.Total}) {
+		var codes []string
+		for _, code := range sortedCodes(st.ErrorCodes) {
+			codes = append(codes, fmt.Sprintf("%s=%d", code, st.ErrorCodes[code]))
+		}
+		l := st.Latency
+		cw.Write([]string{st.Operation, strconv.Itoa(st.Requests), strconv.Itoa(st.Errors), f(st.ErrorRate), f(st.Throughput),
+			f(l.Min), f(l.Mean), f(l.P50), f(l.P90), f(l.P95), f(l.P99), f(l.Max), strings.Join(codes, ";")})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]time.Duration, 100)
+	for i := range hundred {
+		hundred[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		{"median of 1..100", hundred, 50, 50 * time.Millisecond},
+		{"p90 of 1..100", hundred, 90, 90 * time.Millisecond},
+		{"p99 of 1..100", hundred, 99, 99 * time.Millisecond},
+		{"p100 is the max", hundred, 100, 100 * time.Millisecond},
+		{"p0 is the min", hundred, 0, time.Millisecond},
+		{"single sample", []time.Duration{7 * time.Millisecond}, 99, 7 * time.Millisecond},
+		// Nearest rank rounds up: p50 of four is the 2nd, p51 the 3rd
+		{"p50 of four", []time.Duration{1, 2, 3, 4}, 50, 2},
+		{"p51 of four", []time.Duration{1, 2, 3, 4}, 51, 3},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.sorted, tt.p); got != tt.want {
+			t.Errorf("%s: percentile(%d) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	millis := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	t.Run("no samples", func(t *testing.T) {
+		st := summarise("empty", nil, time.Second)
+		if st.Requests != 0 || st.Errors != 0 || st.ErrorRate != 0 || st.Throughput != 0 || st.Latency != (Latency{}) {
+			t.Errorf("got %+v, want an empty summary", st)
+		}
+	})
+
+	t.Run("single sample", func(t *testing.T) {
+		st := summarise("one", []sample{{latency: millis(12)}}, 2*time.Second)
+		want := Latency{Min: 12, Mean: 12, P50: 12, P90: 12, P95: 12, P99: 12, Max: 12}
+		if st.Latency != want {
+			t.Errorf("latency = %+v, want %+v", st.Latency, want)
+		}
+		if st.Requests != 1 || st.ErrorRate != 0 || st.Throughput != 0.5 {
+			t.Errorf("got %+v", st)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		samples := []sample{
+			{latency: millis(40)},
+			{latency: millis(10), code: "HTTP_500"},
+			{latency: millis(30), code: "GRAPHQL_ERROR"},
+			{latency: millis(20), code: "HTTP_500"},
+		}
+		st := summarise("mixed", samples, time.Second)
+		if st.Requests != 4 || st.Errors != 3 || st.ErrorRate != 0.75 {
+			t.Errorf("requests %d, errors %d, rate %v; want 4, 3, 0.75", st.Requests, st.Errors, st.ErrorRate)
+		}
+		if want := map[string]int{"HTTP_500": 2, "GRAPHQL_ERROR": 1}; !maps.Equal(st.ErrorCodes, want) {
+			t.Errorf("error codes = %v, want %v", st.ErrorCodes, want)
+		}
+		// Failed requests still count towards latency
+		if st.Latency.Min != 10 || st.Latency.Max != 40 || st.Latency.Mean != 25 || st.Latency.P50 != 20 {
+			t.Errorf("latency = %+v", st.Latency)
+		}
+	})
+}
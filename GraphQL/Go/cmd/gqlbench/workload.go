@@ -0,0 +1,141 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultWorkload []byte
+
+// Workload is the mix of operations to replay
+type Workload struct {
+	Operations []Operation `json:"operations" yaml:"operations"`
+}
+
+// Operation is one GraphQL request in the mix, picked in proportion to its weight
+type Operation struct {
+	Name string `json:"name" yaml:"name"`
+	// Weight defaults to 1; 0 disables the operation
+	Weight *int `json:"weight" yaml:"weight"`
+	// Query is the operation text; File names a .graphql file holding it
+	// instead, relative to the workload file
+	Query         string         `json:"query" yaml:"query"`
+	File          string         `json:"file" yaml:"file"`
+	OperationName string         `json:"operationName" yaml:"operationName"`
+	Variables     map[string]any `json:"variables" yaml:"variables"`
+	// As sends the X-User-ID header, which only the in-process server and
+	// servers run with TRUST_USER_HEADER=true act on
+	As string `json:"as" yaml:"as"`
+	// Headers are sent with this operation on top of the -H headers
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	body   []byte
+	weight int
+}
+
+// loadWorkload reads a YAML (.yaml/.yml) or JSON (.json) workload, or the
+// built-in one when path is empty
+func loadWorkload(path string) (*Workload, error) {
+	data, ext, dir := defaultWorkload, ".yaml", "."
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read workload: %w", err)
+		}
+		ext, dir = strings.ToLower(filepath.Ext(path)), filepath.Dir(path)
+	}
+
+	var w Workload
+	switch ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(strings.NewReader(string(data)))
+		dec.KnownFields(true)
+		if err := dec.Decode(&w); err != nil {
+			return nil, fmt.Errorf("workload %s: %w", path, err)
+		}
+	case ".json":
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&w); err != nil {
+			return nil, fmt.Errorf("workload %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported workload format %q (want .yaml, .yml or .json)", ext)
+	}
+	if err := w.prepare(dir); err != nil {
+		return nil, fmt.Errorf("workload %s: %w", path, err)
+	}
+	return &w, nil
+}
+
+// prepare checks the operations, reads query files and encodes each request body once
+func (w *Workload) prepare(dir string) error {
+	names := make(map[string]bool, len(w.Operations))
+	total := 0
+	for i := range w.Operations {
+		op := &w.Operations[i]
+		switch {
+		case op.Name == "":
+			return fmt.Errorf("operations[%d]: name is required", i)
+		case names[op.Name]:
+			return fmt.Errorf("operations[%d]: duplicate name %q", i, op.Name)
+		case (op.Query == "") == (op.File == ""):
+			return fmt.Errorf("operation %s: set exactly one of query and file", op.Name)
+		}
+		names[op.Name] = true
+
+		op.weight = 1
+		if op.Weight != nil {
+			if *op.Weight < 0 {
+				return fmt.Errorf("operation %s: weight must not be negative", op.Name)
+			}
+			op.weight = *op.Weight
+		}
+		total += op.weight
+
+		if op.File != "" {
+			query, err := os.ReadFile(filepath.Join(dir, op.File))
+			if err != nil {
+				return fmt.Errorf("operation %s: %w", op.Name, err)
+			}
+			op.Query = string(query)
+		}
+		body, err := json.Marshal(map[string]any{
+			"query":         op.Query,
+			"operationName": op.OperationName,
+			"variables":     op.Variables,
+		})
+		if err != nil {
+			return fmt.Errorf("operation %s: %w", op.Name, err)
+		}
+		op.body = body
+	}
+	if total == 0 {
+		return fmt.Errorf("no operation has a positive weight")
+	}
+	return nil
+}
+
+// pick chooses an operation in proportion to the weights
+func (w *Workload) pick(rng *rand.Rand) *Operation {
+	total := 0
+	for i := range w.Operations {
+		total += w.Operations[i].weight
+	}
+	n := rng.IntN(total)
+	for i := range w.Operations {
+		if n < w.Operations[i].weight {
+			return &w.Operations[i]
+		}
+		n -= w.Operations[i].weight
+	}
+	panic("unreachable")
+}
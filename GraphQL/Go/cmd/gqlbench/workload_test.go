@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestWorkload_Pick(t *testing.T) {
+	weight := func(n int) *int { return &n }
+	w := &Workload{Operations: []Operation{
+		{Name: "rare", Query: "{ a }", Weight: weight(1)},
+		{Name: "common", Query: "{ b }", Weight: weight(3)},
+		{Name: "off", Query: "{ c }", Weight: weight(0)},
+		{Name: "default", Query: "{ d }"},
+	}}
+	if err := w.prepare("."); err != nil {
+		t.Fatal(err)
+	}
+
+	const picks = 60000
+	rng := rand.New(rand.NewPCG(1, 2))
+	counts := map[string]int{}
+	for range picks {
+		counts[w.pick(rng).Name]++
+	}
+
+	if counts["off"] != 0 {
+		t.Errorf("weight 0 was picked %d times", counts["off"])
+	}
+	// Weights 1, 3 and the default 1 share five parts
+	for name, parts := range map[string]float64{"rare": 1, "common": 3, "default": 1} {
+		want := picks * parts / 5
+		if got := float64(counts[name]); math.Abs(got-want) > want*0.05 {
+			t.Errorf("%s picked %.0f times, want about %.0f", name, got, want)
+		}
+	}
+}
+
+func TestWorkload_PrepareRejects(t *testing.T) {
+	weight := func(n int) *int { return &n }
+	tests := map[string][]Operation{
+		"no positive weight": {{Name: "a", Query: "{ a }", Weight: weight(0)}},
+		"negative weight":    {{Name: "a", Query: "{ a }", Weight: weight(-1)}},
+		"duplicate name":     {{Name: "a", Query: "{ a }"}, {Name: "a", Query: "{ b }"}},
+		"no query":           {{Name: "a"}},
+		"query and file":     {{Name: "a", Query: "{ a }", File: "a.graphql"}},
+	}
+	for name, ops := range tests {
+		if err := (&Workload{Operations: ops}).prepare("."); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
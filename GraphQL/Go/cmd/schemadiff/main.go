@@ -0,0 +1,165 @@
+// Command schemadiff checks the GraphQL schema for changes that would break
+// existing clients.
+//
+//	schemadiff -baseline old.graphqls [-schema file,...] [-operations dir] [-json file|-]
+//
+// It compares the schema the server is built from (or the -schema files)
+// against a baseline SDL file, e.g. the previous release's schema:
+//
+//	git show origin/main:GraphQL/Go/graph/schema.graphqls > /tmp/baseline.graphqls
+//	go run ./cmd/schemadiff -baseline /tmp/baseline.graphqls -operations ./clients
+//
+// Each change is classified as breaking, dangerous or safe. With -operations,
+// every .graphql or .gql file in the directory is also validated against the
+// new schema. A report is printed, and with -json a JSON report is written too
+// ("-" prints it instead of the text report). The exit status is 1 when a
+// change is breaking or an operation fails to validate, and 2 on usage errors
+// or when a schema or the operations cannot be read.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/schemadiff"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type report struct {
+	Baseline   string              `json:"baseline"`
+	Breaking   bool                `json:"breaking"`
+	Changes    []schemadiff.Change `json:"changes"`
+	Operations *operationsReport   `json:"operations,omitempty"`
+}
+
+type operationsReport struct {
+	Dir      string                        `json:"dir"`
+	Checked  int                           `json:"checked"`
+	Failures []schemadiff.OperationFailure `json:"failures"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is the command without the process around it; it returns the exit status
+func run(args []string, stdout, stderr io.Writer) int {
+	logger := log.New(stderr, "schemadiff: ", 0)
+	flags := flag.NewFlagSet("schemadiff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	baseline := flags.String("baseline", "", "SDL file with the schema to compare against (required)")
+	schemaFiles := flags.String("schema", "", "comma-separated SDL files of the new schema (default: the schema compiled into the server)")
+	operations := flags.String("operations", "", "directory of stored client operations to validate against the new schema")
+	jsonOut := flags.String("json", "", `also write a JSON report to this file, or "-" to print only JSON`)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *baseline == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	old, err := schemadiff.LoadSchema(*baseline)
+	if err != nil {
+		logger.Printf("baseline: %v", err)
+		return 2
+	}
+	current := graph.NewExecutableSchema(graph.Config{}).Schema()
+	if *schemaFiles != "" {
+		if current, err = schemadiff.LoadSchema(strings.Split(*schemaFiles, ",")...); err != nil {
+			logger.Print(err)
+			return 2
+		}
+	}
+
+	r := report{Baseline: *baseline, Changes: schemadiff.Compare(old, current)}
+	if r.Changes == nil {
+		r.Changes = []schemadiff.Change{}
+	}
+	r.Breaking = schemadiff.HasBreaking(r.Changes)
+	if *operations != "" {
+		r.Operations, err = checkOperations(current, *operations)
+		if err != nil {
+			logger.Print(err)
+			return 2
+		}
+	}
+
+	if *jsonOut != "-" {
+		printText(stdout, r)
+	}
+	if *jsonOut != "" {
+		if err := writeJSON(stdout, *jsonOut, r); err != nil {
+			logger.Print(err)
+			return 2
+		}
+	}
+
+	if r.Breaking || (r.Operations != nil && len(r.Operations.Failures) > 0) {
+		return 1
+	}
+	return 0
+}
+
+func checkOperations(schema *ast.Schema, dir string) (*operationsReport, error) {
+	checked, failures, err := schemadiff.CheckOperations(schema, dir)
+	if err != nil {
+		return nil, fmt.Errorf("check operations: %w", err)
+	}
+	if failures == nil {
+		failures = []schemadiff.OperationFailure{}
+	}
+	return &operationsReport{Dir: dir, Checked: checked, Failures: failures}, nil
+}
+
+func printText(w io.Writer, r report) {
+	counts := map[schemadiff.Severity]int{}
+	for _, c := range r.Changes {
+		counts[c.Severity]++
+	}
+	fmt.Fprintf(w, "Compared with %s: %d breaking, %d dangerous, %d safe\n",
+		r.Baseline, counts[schemadiff.Breaking], counts[schemadiff.Dangerous], counts[schemadiff.Safe])
+
+	for _, severity := range []schemadiff.Severity{schemadiff.Breaking, schemadiff.Dangerous, schemadiff.Safe} {
+		if counts[severity] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", severity)
+		for _, c := range r.Changes {
+			if c.Severity == severity {
+				fmt.Fprintf(w, "  %-28s %s\n", c.Kind, c.Message)
+			}
+		}
+	}
+
+	if ops := r.Operations; ops != nil {
+		fmt.Fprintf(w, "\nOperations in %s: %d checked, %d failing\n", ops.Dir, ops.Checked, len(ops.Failures))
+		for _, f := range ops.Failures {
+			fmt.Fprintf(w, "  %s\n", f.File)
+			for _, msg := range f.Errors {
+				fmt.Fprintf(w, "    %s\n", msg)
+			}
+		}
+	}
+}
+
+func writeJSON(stdout io.Writer, path string, r report) error {
+	w := stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
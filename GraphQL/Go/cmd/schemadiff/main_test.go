@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ExitStatus(t *testing.T) {
+	dir := t.TempDir()
+	baseline := writeFile(t, dir, "baseline.graphqls", `type Query { user(id: ID!): User } type User { id: ID! name: String }`)
+	removed := writeFile(t, dir, "removed.graphqls", `type Query { user(id: ID!): User } type User { id: ID! }`)
+	added := writeFile(t, dir, "added.graphqls", `type Query { user(id: ID!): User } type User { id: ID! name: String email: String }`)
+
+	ops := filepath.Join(dir, "ops")
+	if err := os.Mkdir(ops, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, ops, "user.graphql", `query User { user(id: "1") { id name } }`)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"compiled schema against its own SDL", []string{"-baseline", "../../graph/schema.graphqls"}, 0},
+		{"safe change", []string{"-baseline", baseline, "-schema", added}, 0},
+		{"breaking change", []string{"-baseline", baseline, "-schema", removed}, 1},
+		{"breaking change with JSON only", []string{"-baseline", baseline, "-schema", removed, "-json", "-"}, 1},
+		{"operations still valid", []string{"-baseline", baseline, "-schema", added, "-operations", ops}, 0},
+		{"operation no longer valid", []string{"-baseline", added, "-schema", removed, "-operations", ops}, 1},
+		{"no baseline", nil, 2},
+		{"unreadable baseline", []string{"-baseline", filepath.Join(dir, "missing.graphqls")}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("exit status = %d, want %d\nstdout:\n%s\nstderr:\n%s", got, tt.want, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_ReportsBreakingChange(t *testing.T) {
+	dir := t.TempDir()
+	baseline := writeFile(t, dir, "baseline.graphqls", `type Query { a: String b: String }`)
+	current := writeFile(t, dir, "current.graphqls", `type Query { a: String }`)
+
+	var stdout, stderr bytes.Buffer
+	run([]string{"-baseline", baseline, "-schema", current}, &stdout, &stderr)
+
+	out := stdout.String()
+	for _, want := range []string{"1 breaking, 0 dangerous, 0 safe", "FIELD_REMOVED", "field Query.b was removed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+}
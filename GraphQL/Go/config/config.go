@@ -0,0 +1,509 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the server settings. Values are read from an optional JSON file
+// named by CONFIG_FILE and then overridden by individual environment variables.
+type Config struct {
+	Port string `json:"port"`
+
+	// Production flips the defaults for Playground and Introspection to off
+	Production    bool `json:"production"`
+	Playground    bool `json:"playground"`
+	Introspection bool `json:"introspection"`
+
+	// AllowedOrigins lists the origins allowed by CORS and websocket upgrades; "*" allows any
+	AllowedOrigins []string `json:"allowedOrigins"`
+
+	ReadTimeout     Duration `json:"readTimeout"`
+	WriteTimeout    Duration `json:"writeTimeout"`
+	IdleTimeout     Duration `json:"idleTimeout"`
+	ShutdownTimeout Duration `json:"shutdownTimeout"`
+	MaxBodyBytes    int64    `json:"maxBodyBytes"`
+
+	// TLS is served when both files are set
+	TLSCertFile string `json:"tlsCertFile"`
+	TLSKeyFile  string `json:"tlsKeyFile"`
+
+	RequireTenant bool   `json:"requireTenant"`
+	JWTSecret     string `json:"jwtSecret"`
+	// TrustUserHeader accepts an unverified X-User-ID header as the caller, so
+	// local tools can act as any user without a token. Never in production.
+	TrustUserHeader bool `json:"trustUserHeader"`
+	// AdminUsers are user IDs given the admin role, e.g. to manage webhooks
+	AdminUsers []string `json:"adminUsers"`
+
+	// SeedFile is a YAML or JSON fixture loaded at startup; empty loads the
+	// built-in sample users and "none" starts with empty repositories
+	SeedFile string `json:"seedFile"`
+	// SeedSynthetic generates this many extra users (with posts) for load testing
+	SeedSynthetic int `json:"seedSynthetic"`
+
+	// SchedulerInterval is how often scheduled posts are checked for publishing
+	SchedulerInterval Duration `json:"schedulerInterval"`
+
+	// TracesExporter selects where spans go: "none", "stdout" or "otlp"
+	TracesExporter string `json:"tracesExporter"`
+
+	// PersistedQueryStore holds APQ registrations: "memory" keeps an LRU of
+	// PersistedQueryCacheSize queries, "file" writes up to that many to
+	// PersistedQueryDir and refuses more
+	PersistedQueryStore     string `json:"persistedQueryStore"`
+	PersistedQueryDir       string `json:"persistedQueryDir"`
+	PersistedQueryCacheSize int    `json:"persistedQueryCacheSize"`
+	// QueryAllowlist is a persisted query manifest; when set, strict mode is on
+	// and only the operations it lists may run
+	QueryAllowlist string `json:"queryAllowlist"`
+
+	// WebhookMaxAttempts is how many times a delivery is tried before it is
+	// dead-lettered; retries back off exponentially from WebhookRetryDelay
+	WebhookMaxAttempts int      `json:"webhookMaxAttempts"`
+	WebhookRetryDelay  Duration `json:"webhookRetryDelay"`
+
+	// EmailVerificationTTL is how long the token sent for an email change
+	// stays valid
+	EmailVerificationTTL Duration `json:"emailVerificationTtl"`
+
+	// Events are always published in process (to webhooks); EventPublisher adds
+	// "http" (POST to EventHTTPURL) or "mqtt" (EventMQTTBroker under EventMQTTTopic)
+	EventPublisher      string   `json:"eventPublisher"`
+	EventHTTPURL        string   `json:"eventHttpUrl"`
+	EventMQTTBroker     string   `json:"eventMqttBroker"`
+	EventMQTTTopic      string   `json:"eventMqttTopic"`
+	OutboxRelayInterval Duration `json:"outboxRelayInterval"`
+
+	// Each caller may spend RateLimitQueryPoints of query complexity and
+	// RateLimitMutationPoints of mutation complexity per RateLimitPeriod;
+	// zero points turns that limit off
+	RateLimitQueryPoints    int      `json:"rateLimitQueryPoints"`
+	RateLimitMutationPoints int      `json:"rateLimitMutationPoints"`
+	RateLimitPeriod         Duration `json:"rateLimitPeriod"`
+	// RateLimitStore keeps budgets "memory" (per server) or in "redis" at
+	// RateLimitRedisURL, shared by every server using it
+	RateLimitStore    string `json:"rateLimitStore"`
+	RateLimitRedisURL string `json:"rateLimitRedisUrl"`
+	// APIKeys are the X-API-Key values rate limited as one caller each
+	APIKeys []string `json:"apiKeys"`
+	// TrustForwardedFor identifies anonymous callers by X-Forwarded-For;
+	// enable it only behind a proxy that sets the header
+	TrustForwardedFor bool `json:"trustForwardedFor"`
+
+	// RequestLog writes a JSON line per GraphQL operation to stdout, with the
+	// values of variables named in LogRedactFields (at any depth) masked
+	RequestLog      bool     `json:"requestLog"`
+	LogRedactFields []string `json:"logRedactFields"`
+
+	// Mock answers every GraphQL field with data generated from the schema
+	// and MockSeed; MockFields mocks only the listed resolver fields (e.g.
+	// "User.followers"). MockFixtures is a YAML or JSON file of per-type values.
+	Mock         bool     `json:"mock"`
+	MockFields   []string `json:"mockFields"`
+	MockSeed     int64    `json:"mockSeed"`
+	MockListSize int      `json:"mockListSize"`
+	MockFixtures string   `json:"mockFixtures"`
+}
+
+// Duration is a time.Duration that reads as "15s" style strings in JSON
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Development returns the settings used when nothing is configured
+func Development() Config {
+	return Config{
+		Port:            "8080",
+		Playground:      true,
+		Introspection:   true,
+		AllowedOrigins:  []string{"*"},
+		ReadTimeout:     Duration(15 * time.Second),
+		WriteTimeout:    Duration(30 * time.Second),
+		IdleTimeout:     Duration(60 * time.Second),
+		ShutdownTimeout: Duration(20 * time.Second),
+		MaxBodyBytes:    1 << 20,
+		TracesExporter:  "none",
+
+		PersistedQueryStore:     "memory",
+		PersistedQueryCacheSize: 1000,
+
+		SchedulerInterval: Duration(15 * time.Second),
+
+		WebhookMaxAttempts: 6,
+		WebhookRetryDelay:  Duration(time.Second),
+
+		EmailVerificationTTL: Duration(24 * time.Hour),
+
+		EventMQTTTopic:      "api-hub/events",
+		OutboxRelayInterval: Duration(time.Second),
+
+		RateLimitQueryPoints:    10000,
+		RateLimitMutationPoints: 300,
+		RateLimitPeriod:         Duration(time.Minute),
+		RateLimitStore:          "memory",
+
+		RequestLog:      true,
+		LogRedactFields: []string{"email", "password", "token", "secret"},
+
+		MockSeed:     1,
+		MockListSize: 3,
+	}
+}
+
+// Load builds the configuration from CONFIG_FILE and the environment
+func Load() (Config, error) {
+	cfg := Development()
+
+	// Production mode has to be known before the file is applied so that
+	// explicit playground/introspection settings in the file still win
+	production, err := envBool("PRODUCTION", false)
+	if err != nil {
+		return cfg, err
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read config file: %w", err)
+		}
+		var probe struct {
+			Production bool `json:"production"`
+		}
+		if err := json.Unmarshal(data, &probe); err != nil {
+			return cfg, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		if probe.Production || production {
+			cfg.applyProduction()
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	} else if production {
+		cfg.applyProduction()
+	}
+	if production {
+		cfg.Production = true
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) applyProduction() {
+	c.Production = true
+	c.Playground = false
+	c.Introspection = false
+	c.AllowedOrigins = nil
+}
+
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("PORT"); v != "" {
+		c.Port = v
+	}
+	if v := os.Getenv("ALLOWED_ORIGINS"); v != "" {
+		c.AllowedOrigins = splitList(v)
+	}
+	if v := os.Getenv("TLS_CERT_FILE"); v != "" {
+		c.TLSCertFile = v
+	}
+	if v := os.Getenv("TLS_KEY_FILE"); v != "" {
+		c.TLSKeyFile = v
+	}
+	if v := os.Getenv("JWT_SECRET"); v != "" {
+		c.JWTSecret = v
+	}
+	if v := os.Getenv("ADMIN_USERS"); v != "" {
+		c.AdminUsers = splitList(v)
+	}
+	if v := os.Getenv("SEED_FILE"); v != "" {
+		c.SeedFile = v
+	}
+	if v := os.Getenv("SEED_SYNTHETIC"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("SEED_SYNTHETIC: %w", err)
+		}
+		c.SeedSynthetic = n
+	}
+	if v := os.Getenv("OTEL_TRACES_EXPORTER"); v != "" {
+		c.TracesExporter = v
+	}
+	if v := os.Getenv("PERSISTED_QUERY_STORE"); v != "" {
+		c.PersistedQueryStore = v
+	}
+	if v := os.Getenv("PERSISTED_QUERY_DIR"); v != "" {
+		c.PersistedQueryDir = v
+	}
+	if v := os.Getenv("PERSISTED_QUERY_CACHE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("PERSISTED_QUERY_CACHE_SIZE: %w", err)
+		}
+		c.PersistedQueryCacheSize = n
+	}
+	if v := os.Getenv("QUERY_ALLOWLIST"); v != "" {
+		c.QueryAllowlist = v
+	}
+	if v := os.Getenv("EVENT_PUBLISHER"); v != "" {
+		c.EventPublisher = v
+	}
+	if v := os.Getenv("EVENT_HTTP_URL"); v != "" {
+		c.EventHTTPURL = v
+	}
+	if v := os.Getenv("EVENT_MQTT_BROKER"); v != "" {
+		c.EventMQTTBroker = v
+	}
+	if v := os.Getenv("EVENT_MQTT_TOPIC"); v != "" {
+		c.EventMQTTTopic = v
+	}
+	if v := os.Getenv("RATE_LIMIT_QUERY_POINTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_QUERY_POINTS: %w", err)
+		}
+		c.RateLimitQueryPoints = n
+	}
+	if v := os.Getenv("RATE_LIMIT_MUTATION_POINTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_MUTATION_POINTS: %w", err)
+		}
+		c.RateLimitMutationPoints = n
+	}
+	if v := os.Getenv("RATE_LIMIT_STORE"); v != "" {
+		c.RateLimitStore = v
+	}
+	if v := os.Getenv("RATE_LIMIT_REDIS_URL"); v != "" {
+		c.RateLimitRedisURL = v
+	}
+	if v := os.Getenv("LOG_REDACT_FIELDS"); v != "" {
+		c.LogRedactFields = splitList(v)
+	}
+	if v := os.Getenv("MOCK_FIELDS"); v != "" {
+		c.MockFields = splitList(v)
+	}
+	if v := os.Getenv("MOCK_SEED"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("MOCK_SEED: %w", err)
+		}
+		c.MockSeed = n
+	}
+	if v := os.Getenv("MOCK_LIST_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("MOCK_LIST_SIZE: %w", err)
+		}
+		c.MockListSize = n
+	}
+	if v := os.Getenv("MOCK_FIXTURES"); v != "" {
+		c.MockFixtures = v
+	}
+	if v := os.Getenv("API_KEYS"); v != "" {
+		c.APIKeys = splitList(v)
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: %w", err)
+		}
+		c.WebhookMaxAttempts = n
+	}
+
+	var err error
+	if c.Playground, err = envBool("PLAYGROUND", c.Playground); err != nil {
+		return err
+	}
+	if c.Introspection, err = envBool("INTROSPECTION", c.Introspection); err != nil {
+		return err
+	}
+	if c.RequireTenant, err = envBool("REQUIRE_TENANT", c.RequireTenant); err != nil {
+		return err
+	}
+	if c.TrustForwardedFor, err = envBool("TRUST_FORWARDED_FOR", c.TrustForwardedFor); err != nil {
+		return err
+	}
+	if c.TrustUserHeader, err = envBool("TRUST_USER_HEADER", c.TrustUserHeader); err != nil {
+		return err
+	}
+	if c.RequestLog, err = envBool("REQUEST_LOG", c.RequestLog); err != nil {
+		return err
+	}
+	if c.Mock, err = envBool("MOCK", c.Mock); err != nil {
+		return err
+	}
+	if c.ReadTimeout, err = envDuration("READ_TIMEOUT", c.ReadTimeout); err != nil {
+		return err
+	}
+	if c.WriteTimeout, err = envDuration("WRITE_TIMEOUT", c.WriteTimeout); err != nil {
+		return err
+	}
+	if c.IdleTimeout, err = envDuration("IDLE_TIMEOUT", c.IdleTimeout); err != nil {
+		return err
+	}
+	if c.ShutdownTimeout, err = envDuration("SHUTDOWN_TIMEOUT", c.ShutdownTimeout); err != nil {
+		return err
+	}
+	if c.SchedulerInterval, err = envDuration("SCHEDULER_INTERVAL", c.SchedulerInterval); err != nil {
+		return err
+	}
+	if c.WebhookRetryDelay, err = envDuration("WEBHOOK_RETRY_DELAY", c.WebhookRetryDelay); err != nil {
+		return err
+	}
+	if c.EmailVerificationTTL, err = envDuration("EMAIL_VERIFICATION_TTL", c.EmailVerificationTTL); err != nil {
+		return err
+	}
+	if c.OutboxRelayInterval, err = envDuration("OUTBOX_RELAY_INTERVAL", c.OutboxRelayInterval); err != nil {
+		return err
+	}
+	if c.RateLimitPeriod, err = envDuration("RATE_LIMIT_PERIOD", c.RateLimitPeriod); err != nil {
+		return err
+	}
+	if v := os.Getenv("MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("MAX_BODY_BYTES: %w", err)
+		}
+		c.MaxBodyBytes = n
+	}
+	return nil
+}
+
+// Validate reports settings that cannot work together
+func (c Config) Validate() error {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	if c.TrustUserHeader && c.Production {
+		return fmt.Errorf("the X-User-ID header must not be trusted in production")
+	}
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body bytes must not be negative")
+	}
+	if c.SchedulerInterval <= 0 {
+		return fmt.Errorf("scheduler interval must be positive")
+	}
+	if c.WebhookMaxAttempts < 1 {
+		return fmt.Errorf("webhook max attempts must be at least 1")
+	}
+	if c.WebhookRetryDelay <= 0 {
+		return fmt.Errorf("webhook retry delay must be positive")
+	}
+	if c.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("email verification TTL must be positive")
+	}
+	if c.OutboxRelayInterval <= 0 {
+		return fmt.Errorf("outbox relay interval must be positive")
+	}
+	switch c.EventPublisher {
+	case "":
+	case "http":
+		if c.EventHTTPURL == "" {
+			return fmt.Errorf("the http event publisher needs a URL")
+		}
+	case "mqtt":
+		if c.EventMQTTBroker == "" || c.EventMQTTTopic == "" {
+			return fmt.Errorf("the mqtt event publisher needs a broker and a topic")
+		}
+	default:
+		return fmt.Errorf("unknown event publisher %q", c.EventPublisher)
+	}
+	if c.RateLimitQueryPoints < 0 || c.RateLimitMutationPoints < 0 {
+		return fmt.Errorf("rate limit points must not be negative")
+	}
+	if c.RateLimitPeriod <= 0 {
+		return fmt.Errorf("rate limit period must be positive")
+	}
+	switch c.RateLimitStore {
+	case "memory":
+	case "redis":
+		if c.RateLimitRedisURL == "" {
+			return fmt.Errorf("the redis rate limit store needs a URL")
+		}
+	default:
+		return fmt.Errorf("unknown rate limit store %q", c.RateLimitStore)
+	}
+	if (c.Mock || len(c.MockFields) > 0) && c.Production {
+		return fmt.Errorf("mock data must not be served in production")
+	}
+	if c.MockListSize < 1 {
+		return fmt.Errorf("mock list size must be at least 1")
+	}
+	if c.SeedSynthetic < 0 {
+		return fmt.Errorf("synthetic seed count must not be negative")
+	}
+	switch c.PersistedQueryStore {
+	case "memory":
+	case "file":
+		if c.PersistedQueryDir == "" {
+			return fmt.Errorf("the file persisted query store needs a directory")
+		}
+	default:
+		return fmt.Errorf("unknown persisted query store %q", c.PersistedQueryStore)
+	}
+	if c.PersistedQueryCacheSize <= 0 {
+		return fmt.Errorf("persisted query cache size must be positive")
+	}
+	return nil
+}
+
+// TLSEnabled reports whether the server should serve HTTPS
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+func envBool(name string, fallback bool) (bool, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback, fmt.Errorf("%s: %w", name, err)
+	}
+	return b, nil
+}
+
+func envDuration(name string, fallback Duration) (Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback, fmt.Errorf("%s: %w", name, err)
+	}
+	return Duration(d), nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
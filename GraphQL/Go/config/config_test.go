@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		check   func(t *testing.T, cfg Config)
+		wantErr string
+	}{
+		{
+			name: "development defaults",
+			check: func(t *testing.T, cfg Config) {
+				if !reflect.DeepEqual(cfg, Development()) {
+					t.Errorf("got %+v, want the development defaults", cfg)
+				}
+			},
+		},
+		{
+			name: "production turns off the playground, introspection and CORS",
+			env:  map[string]string{"PRODUCTION": "true"},
+			check: func(t *testing.T, cfg Config) {
+				if !cfg.Production || cfg.Playground || cfg.Introspection || cfg.AllowedOrigins != nil {
+					t.Errorf("got %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "production in the file keeps the file's explicit settings",
+			file: `{"production": true, "introspection": true, "allowedOrigins": ["https://app.example.com"]}`,
+			check: func(t *testing.T, cfg Config) {
+				if !cfg.Production || cfg.Playground || !cfg.Introspection {
+					t.Errorf("got production=%v playground=%v introspection=%v", cfg.Production, cfg.Playground, cfg.Introspection)
+				}
+				if !reflect.DeepEqual(cfg.AllowedOrigins, []string{"https://app.example.com"}) {
+					t.Errorf("allowed origins = %v", cfg.AllowedOrigins)
+				}
+			},
+		},
+		{
+			name: "environment overrides the file",
+			env:  map[string]string{"PORT": "9090", "ALLOWED_ORIGINS": " https://a.example.com, ,https://b.example.com ", "READ_TIMEOUT": "5s"},
+			file: `{"port": "7070", "readTimeout": "1m", "maxBodyBytes": 2048}`,
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Port != "9090" || cfg.ReadTimeout != Duration(5*time.Second) || cfg.MaxBodyBytes != 2048 {
+					t.Errorf("port=%s readTimeout=%v maxBodyBytes=%d", cfg.Port, time.Duration(cfg.ReadTimeout), cfg.MaxBodyBytes)
+				}
+				if !reflect.DeepEqual(cfg.AllowedOrigins, []string{"https://a.example.com", "https://b.example.com"}) {
+					t.Errorf("allowed origins = %q", cfg.AllowedOrigins)
+				}
+			},
+		},
+		{
+			name: "trusting the user header is opt in",
+			env:  map[string]string{"TRUST_USER_HEADER": "true"},
+			check: func(t *testing.T, cfg Config) {
+				if !cfg.TrustUserHeader {
+					t.Error("TRUST_USER_HEADER=true was ignored")
+				}
+			},
+		},
+		{name: "user header in production", env: map[string]string{"PRODUCTION": "true", "TRUST_USER_HEADER": "true"}, wantErr: "X-User-ID"},
+		{name: "bad bool", env: map[string]string{"PLAYGROUND": "maybe"}, wantErr: "PLAYGROUND"},
+		{name: "bad production bool", env: map[string]string{"PRODUCTION": "yes please"}, wantErr: "PRODUCTION"},
+		{name: "bad int", env: map[string]string{"MAX_BODY_BYTES": "1MB"}, wantErr: "MAX_BODY_BYTES"},
+		{name: "bad duration", env: map[string]string{"SCHEDULER_INTERVAL": "15"}, wantErr: "SCHEDULER_INTERVAL"},
+		{name: "bad duration in file", file: `{"readTimeout": 15}`, wantErr: "duration must be a string"},
+		{name: "malformed file", file: `{"port": `, wantErr: "parse config file"},
+		{name: "invalid result", env: map[string]string{"MAX_BODY_BYTES": "-1"}, wantErr: "max body bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clear the variables any case sets so the host cannot leak in
+			for _, other := range tests {
+				for name := range other.env {
+					t.Setenv(name, "")
+				}
+			}
+			t.Setenv("CONFIG_FILE", "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CONFIG_FILE", path)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "read config file") {
+		t.Errorf("error = %v, want a read error", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string
+	}{
+		{"development defaults", func(c *Config) {}, ""},
+		{"TLS with both files", func(c *Config) { c.TLSCertFile, c.TLSKeyFile = "cert.pem", "key.pem" }, ""},
+		{"TLS without a key", func(c *Config) { c.TLSCertFile = "cert.pem" }, "TLS needs both"},
+		{"user header outside production", func(c *Config) { c.TrustUserHeader = true }, ""},
+		{"user header in production", func(c *Config) { c.Production, c.TrustUserHeader = true, true }, "X-User-ID"},
+		{"negative body limit", func(c *Config) { c.MaxBodyBytes = -1 }, "max body bytes"},
+		{"zero scheduler interval", func(c *Config) { c.SchedulerInterval = 0 }, "scheduler interval"},
+		{"no webhook attempts", func(c *Config) { c.WebhookMaxAttempts = 0 }, "webhook max attempts"},
+		{"http publisher without a URL", func(c *Config) { c.EventPublisher = "http" }, "needs a URL"},
+		{"http publisher", func(c *Config) { c.EventPublisher, c.EventHTTPURL = "http", "https://events.example.com" }, ""},
+		{"mqtt publisher without a broker", func(c *Config) { c.EventPublisher = "mqtt" }, "broker and a topic"},
+		{"unknown publisher", func(c *Config) { c.EventPublisher = "kafka" }, "unknown event publisher"},
+		{"negative rate limit", func(c *Config) { c.RateLimitQueryPoints = -1 }, "rate limit points"},
+		{"redis store without a URL", func(c *Config) { c.RateLimitStore = "redis" }, "redis rate limit store"},
+		{"unknown rate limit store", func(c *Config) { c.RateLimitStore = "disk" }, "unknown rate limit store"},
+		{"mock in production", func(c *Config) { c.Production, c.Mock = true, true }, "mock data"},
+		{"mocked fields in production", func(c *Config) { c.Production, c.MockFields = true, []string{"User.followers"} }, "mock data"},
+		{"zero mock list size", func(c *Config) { c.MockListSize = 0 }, "mock list size"},
+		{"negative synthetic seed", func(c *Config) { c.SeedSynthetic = -1 }, "synthetic seed"},
+		{"file query store without a dir", func(c *Config) { c.PersistedQueryStore = "file" }, "needs a directory"},
+		{"unknown query store", func(c *Config) { c.PersistedQueryStore = "s3" }, "unknown persisted query store"},
+		{"zero query cache", func(c *Config) { c.PersistedQueryCacheSize = 0 }, "cache size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Development()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package events describes the domain changes services record alongside
+// their writes, for the outbox relay to publish to consumers such as webhooks.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Event types
+const (
+	UserCreated   = "user.created"
+	PostCreated   = "post.created"
+	PostPublished = "post.published"
+	PostDeleted   = "post.deleted"
+)
+
+// Event is one stored change. ID is unique per event so consumers can drop
+// repeated deliveries.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Tenant     string    `json:"tenant"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+// Sink receives events in process. Emit is called from the publishing loop,
+// so it must hand slow work off rather than block.
+type Sink interface {
+	Emit(ctx context.Context, event Event)
+}
@@ -0,0 +1,66 @@
+package graph
+
+import "math"
+
+// unboundedListSize is the number of items assumed for lists that are not
+// paginated, so selecting fields under them still costs more than once
+const unboundedListSize = 10
+
+// Complexity returns cost functions for fields whose result size depends on
+// their arguments. Every other field costs 1 plus its selections, gqlgen's
+// default. The total is what ratelimit charges an operation.
+func Complexity() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.Users = listOf(unboundedListSize)
+	c.Query.Posts = listOf(unboundedListSize)
+	c.Query.Webhooks = listOf(unboundedListSize)
+	c.Query.WebhookDeadLetters = listOf(unboundedListSize)
+	c.Query.Feed = func(child int, first *int32, after *string) int {
+		return page(child, first, 20)
+	}
+	c.Query.WebhookDeliveries = func(child int, subscriptionID string, first *int32) int {
+		return page(child, first, 50)
+	}
+
+	c.User.Posts = listOf(unboundedListSize)
+	c.User.Followers = func(child int, first *int32, after *string) int {
+		return page(child, first, 20)
+	}
+	c.User.Following = func(child int, first *int32, after *string) int {
+		return page(child, first, 20)
+	}
+	c.Post.Revisions = func(child int, first *int32, after *string) int {
+		return page(child, first, 10)
+	}
+	c.Stats.TopAuthors = func(child int, limit *int32) int {
+		return page(child, limit, 5)
+	}
+
+	return c
+}
+
+func listOf(size int) func(child int) int {
+	return func(child int) int {
+		return 1 + times(size, child)
+	}
+}
+
+// page charges a paginated field for the number of items it asked for. The
+// selections sit under edges, so child is already the cost of one item plus
+// the connection's own fields, which slightly overcounts.
+func page(child int, first *int32, fallback int) int {
+	n := fallback
+	if first != nil && *first > 0 {
+		n = int(*first)
+	}
+	return 1 + times(n, child)
+}
+
+// times multiplies without overflowing, since nested pages multiply quickly
+func times(n, child int) int {
+	if child > 0 && n > (math.MaxInt-1)/child {
+		return math.MaxInt - 1
+	}
+	return n * child
+}
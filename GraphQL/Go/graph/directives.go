@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Directives returns the implementations of the schema's executable directives
+func Directives() DirectiveRoot {
+	return DirectiveRoot{
+		Stream: stream,
+	}
+}
+
+// stream checks that @stream is used the way the incremental delivery RFC
+// allows and then resolves the list in full. Splitting it after initialCount
+// items is up to the transport; see the stream package.
+func stream(ctx context.Context, obj any, next graphql.Resolver, ifArg bool, label *string, initialCount *int32) (any, error) {
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Field.Definition.Type.Elem == nil {
+		return nil, fmt.Errorf("@stream can only be used on list fields, not %s", fc.Field.Name)
+	}
+	if initialCount != nil && *initialCount < 0 {
+		return nil, fmt.Errorf("@stream initialCount must not be negative")
+	}
+	return next(ctx)
+}
@@ -0,0 +1,265 @@
+// Package graphtest runs GraphQL operations against the executable schema in
+// tests. A Server serves the app package's services over whatever
+// repositories the test injects; Golden runs a directory of .graphql
+// operation files and compares each response with a golden file.
+//
+// Run the tests with -update to rewrite the golden files from the actual
+// responses, then review the diff.
+package graphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Repositories back a Server. Nil fields get empty in-memory repositories.
+type Repositories = app.Repositories
+
+// NewRepositories returns empty in-memory repositories for a test to fill
+func NewRepositories() Repositories {
+	return app.NewRepositories()
+}
+
+// Options configure a Server
+type Options struct {
+	// Admins are user IDs given the admin role
+	Admins []string
+	// Mail receives email verification tokens; nil discards them
+	Mail service.EmailSender
+	// Extensions are added to the gqlgen server, e.g. a ratelimit.Limiter
+	Extensions []graphql.HandlerExtension
+	// Middleware wraps the gqlgen server inside auth and tenant resolution,
+	// e.g. ratelimit.Middleware
+	Middleware func(http.Handler) http.Handler
+	// Traced wraps the services in tracing spans
+	Traced bool
+}
+
+// VerificationTTL is how long email verification tokens last. It is under an
+// hour, so expiry times normalize to <now>.
+const VerificationTTL = 30 * time.Minute
+
+type discardMail struct{}
+
+func (discardMail) SendVerification(context.Context, string, string, time.Time) error {
+	return nil
+}
+
+// Server serves /query the way server.go does, minus infrastructure such as
+// caching, rate limits and metrics unless Options add them. Callers are named with the X-User-ID
+// header and requests without a tenant use tenant.Default.
+type Server struct {
+	handler http.Handler
+}
+
+// NewServer builds a Server over repos
+func NewServer(repos Repositories, opts Options) *Server {
+	if opts.Mail == nil {
+		opts.Mail = discardMail{}
+	}
+	services := app.NewServices(repos, app.Options{
+		Mail:            opts.Mail,
+		VerificationTTL: VerificationTTL,
+		RenderCacheSize: 64,
+		Traced:          opts.Traced,
+	})
+	return &Server{handler: app.NewHandler(services.Schema(), app.HandlerOptions{
+		Admins:     opts.Admins,
+		Extensions: opts.Extensions,
+		Middleware: opts.Middleware,
+	})}
+}
+
+// Handler returns the http.Handler serving /query, for tests that need the
+// whole HTTP response rather than the body Do returns
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// Request is one GraphQL operation
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	// As sends the operation as this user; empty is anonymous
+	As string `json:"-"`
+	// Tenant sends an X-Tenant-ID header
+	Tenant string `json:"-"`
+}
+
+// Do runs req and returns the response body. A status other than 200 fails the test.
+func (s *Server) Do(t testing.TB, req Request) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("encode request: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if req.As != "" {
+		r.Header.Set(auth.UserHeader, req.As)
+	}
+	if req.Tenant != "" {
+		r.Header.Set(tenant.DefaultHeader, req.Tenant)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	return rec.Body.Bytes()
+}
+
+var update = flag.Bool("update", false, "rewrite golden files with the actual responses")
+
+// Case is the optional JSON file that runs an operation: <op>.json, or
+// <op>@<case>.json to run the same operation more than once
+type Case struct {
+	// As runs the operation as this user
+	As        string         `json:"as"`
+	Tenant    string         `json:"tenant"`
+	Variables map[string]any `json:"variables"`
+	// Before runs other operations first, on the same server, for cases
+	// that need more than the seed; their responses are not compared
+	Before []Step `json:"before"`
+}
+
+// Step is one operation a case runs before its own
+type Step struct {
+	// Op names the <op>.graphql file in the same directory
+	Op        string         `json:"op"`
+	As        string         `json:"as"`
+	Tenant    string         `json:"tenant"`
+	Variables map[string]any `json:"variables"`
+}
+
+// Golden runs every <op>.graphql in dir against a fresh server from
+// newServer, once per case file (or once without variables when it has
+// none), and compares the indented response with <op>[@case].golden.json.
+// A case's Before steps run on that server first.
+func Golden(t *testing.T, dir string, newServer func(testing.TB) *Server) {
+	t.Helper()
+
+	ops, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatalf("no .graphql files in %s", dir)
+	}
+	for _, op := range ops {
+		query, err := os.ReadFile(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := strings.TrimSuffix(op, ".graphql")
+		matches, err := filepath.Glob(base + "@*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cases []string
+		for _, m := range matches {
+			if !strings.HasSuffix(m, ".golden.json") {
+				cases = append(cases, m)
+			}
+		}
+		if _, err := os.Stat(base + ".json"); err == nil {
+			cases = append(cases, base+".json")
+		}
+		if len(cases) == 0 {
+			cases = []string{""}
+		}
+
+		for _, file := range cases {
+			name := filepath.Base(base)
+			if file != "" {
+				name = filepath.Base(strings.TrimSuffix(file, ".json"))
+			}
+			t.Run(name, func(t *testing.T) {
+				var c Case
+				if file != "" {
+					data, err := os.ReadFile(file)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := json.Unmarshal(data, &c); err != nil {
+						t.Fatalf("%s: %v", file, err)
+					}
+				}
+				srv := newServer(t)
+				for _, step := range c.Before {
+					before, err := os.ReadFile(filepath.Join(dir, step.Op+".graphql"))
+					if err != nil {
+						t.Fatalf("%s: %v", file, err)
+					}
+					srv.Do(t, Request{Query: string(before), Variables: step.Variables, As: step.As, Tenant: step.Tenant})
+				}
+				got := srv.Do(t, Request{Query: string(query), Variables: c.Variables, As: c.As, Tenant: c.Tenant})
+				compare(t, filepath.Join(dir, name+".golden.json"), Normalize(got))
+			})
+		}
+	}
+}
+
+func compare(t *testing.T, golden string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response differs from %s (run with -update to accept it)\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+var (
+	// generatedID matches the nanosecond IDs repositories assign
+	generatedID = regexp.MustCompile(`\b\d{19}\b`)
+	timestamp   = regexp.MustCompile(`"\d{4}-\d\d-\d\dT[^"]+"`)
+)
+
+// Normalize indents a JSON response and replaces the values that change from
+// run to run: generated IDs become <id> and times within an hour of now
+// become <now>. Fixtures with fixed IDs and times are left as they are.
+func Normalize(body []byte) []byte {
+	body = generatedID.ReplaceAll(body, []byte("<id>"))
+	body = timestamp.ReplaceAllFunc(body, func(s []byte) []byte {
+		ts, err := time.Parse(time.RFC3339Nano, string(s[1:len(s)-1]))
+		if err != nil || time.Since(ts).Abs() > time.Hour {
+			return s
+		}
+		return []byte(`"<now>"`)
+	})
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return body
+	}
+	out.WriteByte('\n')
+	return out.Bytes()
+}
@@ -2,6 +2,39 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AuthorPostCount struct {
+	Author    *User `json:"author"`
+	PostCount int32 `json:"postCount"`
+}
+
+type CreatedWebhook struct {
+	Subscription *WebhookSubscription `json:"subscription"`
+	Secret       string               `json:"secret"`
+}
+
+type DailyPostCount struct {
+	Date  string `json:"date"`
+	Count int32  `json:"count"`
+}
+
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+type EmailChangeRequest struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
 type Mutation struct {
 }
 
@@ -16,19 +49,350 @@ type NewUser struct {
 	Email string `json:"email"`
 }
 
+type NewWebhook struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	Secret *string        `json:"secret,omitempty"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
 type Post struct {
-	ID      string  `json:"id"`
-	Title   string  `json:"title"`
-	Content *string `json:"content,omitempty"`
-	Author  *User   `json:"author"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Content     *string    `json:"content,omitempty"`
+	Status      PostStatus `json:"status"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	AuthorID    string     `json:"authorId"`
+}
+
+type PostConnection struct {
+	Edges    []*PostEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type PostEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Post  `json:"node"`
+}
+
+type PostRevision struct {
+	Number       int32     `json:"number"`
+	Title        string    `json:"title"`
+	Content      *string   `json:"content,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	RevertedFrom *int32    `json:"revertedFrom,omitempty"`
+	EditorID     string    `json:"editorId"`
+}
+
+type PostRevisionConnection struct {
+	Edges      []*PostRevisionEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int32               `json:"totalCount"`
+}
+
+type PostRevisionEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *PostRevision `json:"node"`
 }
 
 type Query struct {
 }
 
+type RevisionDiff struct {
+	PostID  string      `json:"postId"`
+	From    int32       `json:"from"`
+	To      int32       `json:"to"`
+	Title   []*DiffLine `json:"title"`
+	Content []*DiffLine `json:"content"`
+}
+
+type Stats struct {
+}
+
+type UpdatePost struct {
+	Title   *string `json:"title,omitempty"`
+	Content *string `json:"content,omitempty"`
+}
+
 type User struct {
-	ID    string  `json:"id"`
-	Name  string  `json:"name"`
-	Email string  `json:"email"`
-	Posts []*Post `json:"posts"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int32       `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
+type WebhookDeadLetter struct {
+	ID             string       `json:"id"`
+	SubscriptionID string       `json:"subscriptionId"`
+	EventID        string       `json:"eventId"`
+	Event          WebhookEvent `json:"event"`
+	Payload        string       `json:"payload"`
+	Attempts       int32        `json:"attempts"`
+	LastError      string       `json:"lastError"`
+	FailedAt       time.Time    `json:"failedAt"`
+}
+
+type WebhookDelivery struct {
+	ID             string       `json:"id"`
+	SubscriptionID string       `json:"subscriptionId"`
+	EventID        string       `json:"eventId"`
+	Event          WebhookEvent `json:"event"`
+	Attempt        int32        `json:"attempt"`
+	StatusCode     *int32       `json:"statusCode,omitempty"`
+	Error          *string      `json:"error,omitempty"`
+	DurationMs     int32        `json:"durationMs"`
+	DeliveredAt    time.Time    `json:"deliveredAt"`
+}
+
+type WebhookSubscription struct {
+	ID        string         `json:"id"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type CacheControlScope string
+
+const (
+	CacheControlScopePublic  CacheControlScope = "PUBLIC"
+	CacheControlScopePrivate CacheControlScope = "PRIVATE"
+)
+
+var AllCacheControlScope = []CacheControlScope{
+	CacheControlScopePublic,
+	CacheControlScopePrivate,
+}
+
+func (e CacheControlScope) IsValid() bool {
+	switch e {
+	case CacheControlScopePublic, CacheControlScopePrivate:
+		return true
+	}
+	return false
+}
+
+func (e CacheControlScope) String() string {
+	return string(e)
+}
+
+func (e *CacheControlScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CacheControlScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CacheControlScope", str)
+	}
+	return nil
+}
+
+func (e CacheControlScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CacheControlScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CacheControlScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DiffOp string
+
+const (
+	DiffOpEqual  DiffOp = "EQUAL"
+	DiffOpInsert DiffOp = "INSERT"
+	DiffOpDelete DiffOp = "DELETE"
+)
+
+var AllDiffOp = []DiffOp{
+	DiffOpEqual,
+	DiffOpInsert,
+	DiffOpDelete,
+}
+
+func (e DiffOp) IsValid() bool {
+	switch e {
+	case DiffOpEqual, DiffOpInsert, DiffOpDelete:
+		return true
+	}
+	return false
+}
+
+func (e DiffOp) String() string {
+	return string(e)
+}
+
+func (e *DiffOp) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DiffOp(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DiffOp", str)
+	}
+	return nil
+}
+
+func (e DiffOp) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DiffOp) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DiffOp) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "DRAFT"
+	PostStatusScheduled PostStatus = "SCHEDULED"
+	PostStatusPublished PostStatus = "PUBLISHED"
+	PostStatusArchived  PostStatus = "ARCHIVED"
+)
+
+var AllPostStatus = []PostStatus{
+	PostStatusDraft,
+	PostStatusScheduled,
+	PostStatusPublished,
+	PostStatusArchived,
+}
+
+func (e PostStatus) IsValid() bool {
+	switch e {
+	case PostStatusDraft, PostStatusScheduled, PostStatusPublished, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
+func (e PostStatus) String() string {
+	return string(e)
+}
+
+func (e *PostStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PostStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PostStatus", str)
+	}
+	return nil
+}
+
+func (e PostStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PostStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PostStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventUserCreated   WebhookEvent = "USER_CREATED"
+	WebhookEventPostCreated   WebhookEvent = "POST_CREATED"
+	WebhookEventPostPublished WebhookEvent = "POST_PUBLISHED"
+	WebhookEventPostDeleted   WebhookEvent = "POST_DELETED"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventUserCreated,
+	WebhookEventPostCreated,
+	WebhookEventPostPublished,
+	WebhookEventPostDeleted,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventUserCreated, WebhookEventPostCreated, WebhookEventPostPublished, WebhookEventPostDeleted:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEvent) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEvent) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
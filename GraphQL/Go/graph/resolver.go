@@ -1,20 +1,31 @@
 package graph
 
-import "github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+import (
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/render"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+)
 
 //go:generate go run github.com/99designs/gqlgen generate
 
 // This file will not be regenerated automatically.
 // It serves as dependency injection for your app.
 type Resolver struct {
-	userService service.UserService
-	postService service.PostService
+	userService    service.UserService
+	postService    service.PostService
+	statsService   service.StatsService
+	webhookService service.WebhookService
+	emailService   service.EmailService
+	renderer       *render.Renderer
 }
 
 // NewResolver creates a new resolver with injected dependencies
-func NewResolver(userService service.UserService, postService service.PostService) *Resolver {
+func NewResolver(userService service.UserService, postService service.PostService, statsService service.StatsService, webhookService service.WebhookService, emailService service.EmailService, renderer *render.Renderer) *Resolver {
 	return &Resolver{
-		userService: userService,
-		postService: postService,
+		userService:    userService,
+		postService:    postService,
+		statsService:   statsService,
+		webhookService: webhookService,
+		emailService:   emailService,
+		renderer:       renderer,
 	}
 }
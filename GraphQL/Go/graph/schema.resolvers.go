@@ -2,8 +2,10 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
 )
 
 // Query Resolvers - Thin layer that delegates to services
@@ -16,10 +18,119 @@ func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error
 	return r.userService.GetUserByID(ctx, id)
 }
 
+func (r *queryResolver) UserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.userService.GetUserByEmail(ctx, email)
+}
+
 func (r *queryResolver) Posts(ctx context.Context) ([]*model.Post, error) {
 	return r.postService.GetAllPosts(ctx)
 }
 
+func (r *queryResolver) RevisionDiff(ctx context.Context, postID string, from int32, to int32) (*model.RevisionDiff, error) {
+	return r.postService.DiffRevisions(ctx, postID, from, to)
+}
+
+func (r *queryResolver) Stats(ctx context.Context) (*model.Stats, error) {
+	return r.statsService.Stats(ctx)
+}
+
+func (r *queryResolver) Feed(ctx context.Context, first *int32, after *string) (*model.PostConnection, error) {
+	return r.postService.Feed(ctx, first, after)
+}
+
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	return r.webhookService.ListWebhooks(ctx)
+}
+
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, subscriptionID string, first *int32) ([]*model.WebhookDelivery, error) {
+	return r.webhookService.ListDeliveries(ctx, subscriptionID, first)
+}
+
+func (r *queryResolver) WebhookDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error) {
+	return r.webhookService.ListDeadLetters(ctx)
+}
+
+// Field Resolvers - Resolved on demand so clients only pay for what they select
+
+func (r *userResolver) Posts(ctx context.Context, obj *model.User) ([]*model.Post, error) {
+	return r.postService.GetPostsByUser(ctx, obj.ID)
+}
+
+func (r *userResolver) PostCount(ctx context.Context, obj *model.User) (int32, error) {
+	n, err := r.postService.CountPostsByUser(ctx, obj.ID)
+	return int32(n), err
+}
+
+func (r *userResolver) Followers(ctx context.Context, obj *model.User, first *int32, after *string) (*model.UserConnection, error) {
+	return r.userService.ListFollowers(ctx, obj.ID, first, after)
+}
+
+func (r *userResolver) Following(ctx context.Context, obj *model.User, first *int32, after *string) (*model.UserConnection, error) {
+	return r.userService.ListFollowing(ctx, obj.ID, first, after)
+}
+
+func (r *postResolver) ContentHTML(ctx context.Context, obj *model.Post) (*string, error) {
+	if obj.Content == nil {
+		return nil, nil
+	}
+	html := r.renderer.Render(*obj.Content).HTML
+	return &html, nil
+}
+
+func (r *postResolver) Excerpt(ctx context.Context, obj *model.Post, length *int32) (*string, error) {
+	if obj.Content == nil {
+		return nil, nil
+	}
+	n := 160
+	if length != nil {
+		if *length < 1 {
+			return nil, &service.ValidationError{Field: "length", Message: "length must be positive"}
+		}
+		n = int(*length)
+	}
+	excerpt := r.renderer.Excerpt(*obj.Content, n)
+	return &excerpt, nil
+}
+
+func (r *postResolver) ReadingTimeMinutes(ctx context.Context, obj *model.Post) (int32, error) {
+	if obj.Content == nil {
+		return 0, nil
+	}
+	return int32(r.renderer.ReadingTimeMinutes(*obj.Content)), nil
+}
+
+// Author and Editor load the user each time, so edits such as a verified
+// email change show up
+func (r *postResolver) Author(ctx context.Context, obj *model.Post) (*model.User, error) {
+	return r.userService.GetUserByID(ctx, obj.AuthorID)
+}
+
+func (r *postResolver) Revisions(ctx context.Context, obj *model.Post, first *int32, after *string) (*model.PostRevisionConnection, error) {
+	return r.postService.ListRevisions(ctx, obj.ID, first, after)
+}
+
+func (r *postRevisionResolver) Editor(ctx context.Context, obj *model.PostRevision) (*model.User, error) {
+	return r.userService.GetUserByID(ctx, obj.EditorID)
+}
+
+func (r *statsResolver) TotalUsers(ctx context.Context, obj *model.Stats) (int32, error) {
+	n, err := r.statsService.CountUsers(ctx)
+	return int32(n), err
+}
+
+func (r *statsResolver) TotalPosts(ctx context.Context, obj *model.Stats) (int32, error) {
+	n, err := r.statsService.CountPosts(ctx)
+	return int32(n), err
+}
+
+func (r *statsResolver) PostsPerDay(ctx context.Context, obj *model.Stats, from time.Time, to time.Time) ([]*model.DailyPostCount, error) {
+	return r.statsService.PostsPerDay(ctx, from, to)
+}
+
+func (r *statsResolver) TopAuthors(ctx context.Context, obj *model.Stats, limit *int32) ([]*model.AuthorPostCount, error) {
+	return r.statsService.TopAuthors(ctx, limit)
+}
+
 // Mutation Resolvers - Thin layer that delegates to services
 
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
@@ -34,9 +145,76 @@ func (r *mutationResolver) DeletePost(ctx context.Context, id string) (*model.Po
 	return r.postService.DeletePost(ctx, id)
 }
 
+func (r *mutationResolver) PublishPost(ctx context.Context, id string) (*model.Post, error) {
+	return r.postService.PublishPost(ctx, id)
+}
+
+func (r *mutationResolver) SchedulePost(ctx context.Context, id string, publishAt time.Time) (*model.Post, error) {
+	return r.postService.SchedulePost(ctx, id, publishAt)
+}
+
+func (r *mutationResolver) ArchivePost(ctx context.Context, id string) (*model.Post, error) {
+	return r.postService.ArchivePost(ctx, id)
+}
+
+func (r *mutationResolver) UpdatePost(ctx context.Context, id string, input model.UpdatePost) (*model.Post, error) {
+	return r.postService.UpdatePost(ctx, id, input)
+}
+
+func (r *mutationResolver) RevertPost(ctx context.Context, postID string, revision int32) (*model.Post, error) {
+	return r.postService.RevertPost(ctx, postID, revision)
+}
+
+func (r *mutationResolver) Follow(ctx context.Context, userID string) (*model.User, error) {
+	return r.userService.Follow(ctx, userID)
+}
+
+func (r *mutationResolver) Unfollow(ctx context.Context, userID string) (*model.User, error) {
+	return r.userService.Unfollow(ctx, userID)
+}
+
+func (r *mutationResolver) RequestEmailChange(ctx context.Context, email string) (*model.EmailChangeRequest, error) {
+	return r.emailService.RequestEmailChange(ctx, email)
+}
+
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	return r.emailService.VerifyEmail(ctx, token)
+}
+
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (*model.User, error) {
+	return r.userService.DeleteUser(ctx, id)
+}
+
+func (r *mutationResolver) ReassignPost(ctx context.Context, id string, authorID string) (*model.Post, error) {
+	return r.postService.ReassignPost(ctx, id, authorID)
+}
+
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.CreatedWebhook, error) {
+	return r.webhookService.CreateWebhook(ctx, input)
+}
+
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	return r.webhookService.DeleteWebhook(ctx, id)
+}
+
+func (r *mutationResolver) RedeliverWebhook(ctx context.Context, deadLetterID string) (bool, error) {
+	if err := r.webhookService.RedeliverDeadLetter(ctx, deadLetterID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Auto-generated resolver types (DON'T DELETE)
-func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
-func (r *Resolver) Query() QueryResolver       { return &queryResolver{r} }
+func (r *Resolver) Mutation() MutationResolver         { return &mutationResolver{r} }
+func (r *Resolver) Query() QueryResolver               { return &queryResolver{r} }
+func (r *Resolver) User() UserResolver                 { return &userResolver{r} }
+func (r *Resolver) Post() PostResolver                 { return &postResolver{r} }
+func (r *Resolver) PostRevision() PostRevisionResolver { return &postRevisionResolver{r} }
+func (r *Resolver) Stats() StatsResolver               { return &statsResolver{r} }
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type postResolver struct{ *Resolver }
+type postRevisionResolver struct{ *Resolver }
+type statsResolver struct{ *Resolver }
@@ -0,0 +1,157 @@
+package graph_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/graphtest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// TestSchema runs the operations in testdata against seeded repositories.
+// Run with -update to rewrite the golden files.
+func TestSchema(t *testing.T) {
+	graphtest.Golden(t, "testdata", func(t testing.TB) *graphtest.Server {
+		return graphtest.NewServer(seed(t), graphtest.Options{Admins: []string{"admin"}})
+	})
+}
+
+// seed fills in-memory repositories with fixed IDs and times so responses are stable:
+// Alice (u1) has a published post with two revisions and a draft, Bob (u2) has a
+// published post, Bob and Carol (u3) follow Alice, Alice follows Bob, and a
+// webhook has one delivery and one dead letter. Pending email changes have the
+// tokens verify_alice, verify_expired (Bob's) and verify_taken (Carol's, to
+// Bob's address).
+func seed(t testing.TB) graphtest.Repositories {
+	t.Helper()
+
+	repos := graphtest.NewRepositories()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 9, 0, 0, 0, time.UTC) }
+	text := func(s string) *string { return &s }
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	alice := &model.User{ID: "u1", Name: "Alice Johnson", Email: "alice@example.com"}
+	bob := &model.User{ID: "u2", Name: "Bob Smith", Email: "bob@example.com"}
+	carol := &model.User{ID: "u3", Name: "Carol White", Email: "carol@example.com"}
+	for _, u := range []*model.User{alice, bob, carol, {ID: "admin", Name: "Admin", Email: "admin@example.com"}} {
+		check(repos.Users.Create(ctx, u))
+	}
+
+	posts := []*model.Post{
+		{ID: "p1", Title: "Hello GraphQL", Content: text("# Hello\n\nClients ask for **exactly** what they need."), AuthorID: alice.ID, Status: model.PostStatusPublished, PublishedAt: ptr(day(2)), CreatedAt: day(1)},
+		{ID: "p2", Title: "Draft notes", Content: text("Not ready yet."), AuthorID: alice.ID, Status: model.PostStatusDraft, CreatedAt: day(3)},
+		{ID: "p3", Title: "Resolvers", AuthorID: bob.ID, Status: model.PostStatusPublished, PublishedAt: ptr(day(4)), CreatedAt: day(4)},
+	}
+	for _, p := range posts {
+		check(repos.Posts.Create(ctx, p))
+	}
+	revisions := []struct {
+		post string
+		rev  *model.PostRevision
+	}{
+		{"p1", &model.PostRevision{Number: 1, Title: "Hello GraphQL", Content: text("# Hello\n\nQueries ask for **exactly** what they need."), EditorID: alice.ID, CreatedAt: day(1)}},
+		{"p1", &model.PostRevision{Number: 2, Title: "Hello GraphQL", Content: posts[0].Content, EditorID: alice.ID, CreatedAt: day(2)}},
+		{"p2", &model.PostRevision{Number: 1, Title: posts[1].Title, Content: posts[1].Content, EditorID: alice.ID, CreatedAt: day(3)}},
+		{"p3", &model.PostRevision{Number: 1, Title: posts[2].Title, EditorID: bob.ID, CreatedAt: day(4)}},
+	}
+	for _, r := range revisions {
+		check(repos.Revisions.Append(ctx, r.post, r.rev))
+	}
+
+	for _, f := range [][2]string{{"u2", "u1"}, {"u3", "u1"}, {"u1", "u2"}} {
+		check(repos.Follows.Follow(ctx, f[0], f[1]))
+	}
+
+	check(repos.Webhooks.CreateWebhook(ctx, &repository.Webhook{
+		Subscription: &model.WebhookSubscription{ID: "w1", URL: "https://hooks.example.com/posts", Events: []model.WebhookEvent{model.WebhookEventPostCreated}, CreatedAt: day(1)},
+		Secret:       "whsec_fixture",
+	}))
+	check(repos.Webhooks.RecordDelivery(ctx, &model.WebhookDelivery{SubscriptionID: "w1", EventID: "e1", Event: model.WebhookEventPostCreated, Attempt: 1, StatusCode: ptr(int32(500)), DurationMs: 120, DeliveredAt: day(2)}))
+	check(repos.Webhooks.AddDeadLetter(ctx, &model.WebhookDeadLetter{SubscriptionID: "w1", EventID: "e1", Event: model.WebhookEventPostCreated, Payload: `{"id":"e1"}`, Attempts: 6, LastError: "status 500", FailedAt: day(2)}))
+
+	// The email service stores the SHA-256 of each token
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	future := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []*repository.EmailVerification{
+		{UserID: "u1", Email: "alice@work.example.com", TokenHash: hash("verify_alice"), ExpiresAt: future},
+		{UserID: "u2", Email: "bob@work.example.com", TokenHash: hash("verify_expired"), ExpiresAt: day(5)},
+		{UserID: "u3", Email: "bob@example.com", TokenHash: hash("verify_taken"), ExpiresAt: future},
+	} {
+		check(repos.Verifications.Create(ctx, v))
+	}
+
+	return repos
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// mailbox keeps the last verification token sent
+type mailbox struct {
+	to, token string
+}
+
+func (m *mailbox) SendVerification(_ context.Context, to, token string, _ time.Time) error {
+	m.to, m.token = to, token
+	return nil
+}
+
+func TestEmailChange(t *testing.T) {
+	mail := &mailbox{}
+	srv := graphtest.NewServer(seed(t), graphtest.Options{Mail: mail})
+
+	srv.Do(t, graphtest.Request{
+		Query: `mutation { requestEmailChange(email: "Alice@New.example.com") { email } }`,
+		As:    "u1",
+	})
+	if mail.to != "Alice@New.example.com" || mail.token == "" {
+		t.Fatalf("sent %q to %q, want a token for Alice@New.example.com", mail.token, mail.to)
+	}
+
+	// The address only changes once the token comes back
+	lookup := graphtest.Request{
+		Query:     `query($email: String!) { userByEmail(email: $email) { id } }`,
+		Variables: map[string]any{"email": "alice@new.example.com"},
+	}
+	if got := string(srv.Do(t, lookup)); got != `{"data":{"userByEmail":null}}` {
+		t.Fatalf("before verifying: %s", got)
+	}
+
+	verify := graphtest.Request{
+		Query:     `mutation($token: String!) { verifyEmail(token: $token) { email } }`,
+		Variables: map[string]any{"token": mail.token},
+	}
+	if got := string(srv.Do(t, verify)); got != `{"data":{"verifyEmail":{"email":"Alice@New.example.com"}}}` {
+		t.Fatalf("verify: %s", got)
+	}
+	if got := string(srv.Do(t, lookup)); got != `{"data":{"userByEmail":{"id":"u1"}}}` {
+		t.Fatalf("after verifying: %s", got)
+	}
+
+	// Posts and revisions load their user, so they see the new address too
+	authored := graphtest.Request{Query: `{ user(id: "u1") { posts { author { email } revisions { edges { node { editor { email } } } } } } }`}
+	want := `{"data":{"user":{"posts":[{"author":{"email":"Alice@New.example.com"},"revisions":{"edges":[` +
+		`{"node":{"editor":{"email":"Alice@New.example.com"}}},{"node":{"editor":{"email":"Alice@New.example.com"}}}]}}]}}}`
+	if got := string(srv.Do(t, authored)); got != want {
+		t.Fatalf("post author after verifying: %s", got)
+	}
+
+	// Tokens work once
+	if got := string(srv.Do(t, verify)); !strings.Contains(got, "verification token is invalid") {
+		t.Fatalf("reused token: %s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Checker is implemented by backends that can report whether they are reachable
+type Checker interface {
+	Ping(ctx context.Context) error
+}
+
+// Handler serves liveness and readiness probes
+type Handler struct {
+	checks   map[string]Checker
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHandler creates probes that consult the named checkers for readiness
+func NewHandler(checks map[string]Checker) *Handler {
+	return &Handler{
+		checks:  checks,
+		timeout: 2 * time.Second,
+	}
+}
+
+// SetDraining makes readiness fail so the orchestrator stops routing traffic during shutdown
+func (h *Handler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is up; it never checks dependencies
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Ready reports whether every backend answers within the timeout
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeStatus(w, http.StatusServiceUnavailable, map[string]any{"status": "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	status := http.StatusOK
+	results := make(map[string]string, len(h.checks))
+	for name, check := range h.checks {
+		if err := check.Ping(ctx); err != nil {
+			status = http.StatusServiceUnavailable
+			results[name] = err.Error()
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	body := map[string]any{"status": "ok", "checks": results}
+	if status != http.StatusOK {
+		body["status"] = "unavailable"
+	}
+	writeStatus(w, status, body)
+}
+
+func writeStatus(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
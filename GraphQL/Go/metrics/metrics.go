@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// maxOperationNames bounds the operation label. Clients choose operation
+// names, so without a bound every new name would add series to each bucket.
+const maxOperationNames = 200
+
+// otherOperations labels operations whose name arrived after the bound was reached
+const otherOperations = "other"
+
+// Extension records Prometheus metrics for every operation and resolver.
+// Register it with srv.Use on the gqlgen handler.
+type Extension struct {
+	operationDuration *prometheus.HistogramVec
+	resolverDuration  *prometheus.HistogramVec
+	errors            *prometheus.CounterVec
+	inFlight          *prometheus.GaugeVec
+
+	names   map[string]bool // operation names already used as labels
+	namesMu sync.Mutex
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = (*Extension)(nil)
+
+// New creates the collectors and registers them with reg
+func New(reg prometheus.Registerer) *Extension {
+	e := &Extension{
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "graphql",
+			Name:      "operation_duration_seconds",
+			Help:      "Time taken to execute a GraphQL operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "type"}),
+		resolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "graphql",
+			Name:      "resolver_duration_seconds",
+			Help:      "Time taken by field resolvers, excluding trivial struct field reads.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"object", "field"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "graphql",
+			Name:      "errors_total",
+			Help:      "GraphQL errors returned to clients, by extensions.code.",
+		}, []string{"code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "graphql",
+			Name:      "operations_in_flight",
+			Help:      "Operations currently executing; subscriptions count until they end.",
+		}, []string{"type"}),
+		names: map[string]bool{},
+	}
+	reg.MustRegister(e.operationDuration, e.resolverDuration, e.errors, e.inFlight)
+	return e
+}
+
+func (e *Extension) ExtensionName() string {
+	return "PrometheusMetrics"
+}
+
+func (e *Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e *Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	name, opType := operationLabels(oc)
+	name = e.operationName(name)
+
+	inFlight := e.inFlight.WithLabelValues(opType)
+	inFlight.Inc()
+
+	var once sync.Once
+	finish := func() {
+		once.Do(func() {
+			inFlight.Dec()
+			e.operationDuration.WithLabelValues(name, opType).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+		})
+	}
+
+	responses := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		// Queries and mutations produce one response; a subscription ends with nil
+		if resp == nil || opType != string(ast.Subscription) {
+			finish()
+		}
+		return resp
+	}
+}
+
+func (e *Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if resp != nil {
+		for _, err := range resp.Errors {
+			code, _ := err.Extensions["code"].(string)
+			if code == "" {
+				code = "UNKNOWN"
+			}
+			e.errors.WithLabelValues(code).Inc()
+		}
+	}
+	return resp
+}
+
+func (e *Extension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+	e.resolverDuration.WithLabelValues(fc.Object, fc.Field.Name).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+// operationName passes name through until maxOperationNames distinct names
+// have been seen; names after that are reported as "other"
+func (e *Extension) operationName(name string) string {
+	e.namesMu.Lock()
+	defer e.namesMu.Unlock()
+
+	if e.names[name] {
+		return name
+	}
+	if len(e.names) >= maxOperationNames {
+		return otherOperations
+	}
+	e.names[name] = true
+	return name
+}
+
+func operationLabels(oc *graphql.OperationContext) (name, opType string) {
+	name = oc.OperationName
+	if name == "" && oc.Operation != nil {
+		name = oc.Operation.Name
+	}
+	if name == "" {
+		name = "anonymous"
+	}
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+	return name, opType
+}
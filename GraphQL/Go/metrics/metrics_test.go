@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestOperationName_BoundsDistinctNames(t *testing.T) {
+	e := New(prometheus.NewRegistry())
+
+	for i := range maxOperationNames {
+		name := fmt.Sprintf("Op%d", i)
+		if got := e.operationName(name); got != name {
+			t.Fatalf("operation %d: got label %q, want %q", i, got, name)
+		}
+	}
+	if got := e.operationName("OneTooMany"); got != otherOperations {
+		t.Fatalf("name past the bound: got label %q, want %q", got, otherOperations)
+	}
+	// Names seen before the bound keep their label
+	if got := e.operationName("Op0"); got != "Op0" {
+		t.Fatalf("known name: got label %q, want Op0", got)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodyBytes(t *testing.T) {
+	// echo reports how much of the body it could read
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, string(body))
+	})
+	tests := []struct {
+		name          string
+		limit         int64
+		body          string
+		unknownLength bool
+		status        int
+	}{
+		{"under the limit", 8, "1234", false, http.StatusOK},
+		{"at the limit", 8, "12345678", false, http.StatusOK},
+		{"declared length over the limit", 8, "123456789", false, http.StatusRequestEntityTooLarge},
+		// Chunked bodies have no Content-Length; the reader stops at the limit
+		{"streamed body over the limit", 8, "123456789", true, http.StatusBadRequest},
+		{"no limit", 0, strings.Repeat("x", 1<<16), false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			MaxBodyBytes(tt.limit)(echo).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("handler read %d bytes, want %d", rec.Body.Len(), len(tt.body))
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Origins is an allowlist of browser origins; "*" allows any origin
+type Origins []string
+
+// Allowed reports whether a request from origin may be served
+func (o Origins) Allowed(origin string) bool {
+	return slices.Contains(o, "*") || slices.Contains(o, origin)
+}
+
+// CheckOrigin matches the websocket.Upgrader hook so subscriptions honour the same policy.
+// Non-browser clients send no Origin header and are always accepted.
+func (o Origins) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || o.Allowed(origin)
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins.
+// Requests from other origins are served without CORS headers, so browsers block them.
+// Only origins listed by name may send credentials; "*" lets any page read
+// responses but never with the visitor's cookies.
+func CORS(origins Origins) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" && origins.Allowed(origin) {
+				h := w.Header()
+				if slices.Contains(origins, origin) {
+					h.Set("Access-Control-Allow-Origin", origin)
+					h.Set("Access-Control-Allow-Credentials", "true")
+					h.Add("Vary", "Origin")
+				} else {
+					h.Set("Access-Control-Allow-Origin", "*")
+				}
+
+				if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+					h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+					h.Set("Access-Control-Allow-Headers", allowHeaders(r))
+					h.Set("Access-Control-Max-Age", "600")
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func allowHeaders(r *http.Request) string {
+	if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		return requested
+	}
+	return strings.Join([]string{"Content-Type", "Authorization"}, ", ")
+}
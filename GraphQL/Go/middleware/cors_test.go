@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		name        string
+		origins     Origins
+		method      string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+		credentials string
+	}{
+		{"listed origin", Origins{"https://app.example.com"}, http.MethodPost, "https://app.example.com", false, http.StatusOK, "https://app.example.com", "true"},
+		{"unlisted origin", Origins{"https://app.example.com"}, http.MethodPost, "https://evil.example.com", false, http.StatusOK, "", ""},
+		{"no origin", Origins{"https://app.example.com"}, http.MethodPost, "", false, http.StatusOK, "", ""},
+		{"wildcard sends no credentials", Origins{"*"}, http.MethodPost, "https://any.example.com", false, http.StatusOK, "*", ""},
+		{"listed origin next to a wildcard", Origins{"*", "https://app.example.com"}, http.MethodPost, "https://app.example.com", false, http.StatusOK, "https://app.example.com", "true"},
+		{"preflight", Origins{"https://app.example.com"}, http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, "https://app.example.com", "true"},
+		{"preflight from unlisted origin", Origins{"https://app.example.com"}, http.MethodOptions, "https://evil.example.com", true, http.StatusOK, "", ""},
+		{"OPTIONS without a requested method", Origins{"*"}, http.MethodOptions, "https://any.example.com", false, http.StatusOK, "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/query", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-Tenant-ID")
+			}
+			rec := httptest.NewRecorder()
+			CORS(tt.origins)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+			if tt.status == http.StatusNoContent {
+				if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, X-Tenant-ID" {
+					t.Errorf("Allow-Headers = %q, want the requested headers", got)
+				}
+				if rec.Header().Get("Access-Control-Allow-Methods") == "" {
+					t.Error("preflight has no Allow-Methods")
+				}
+			}
+		})
+	}
+}
+
+func TestOrigins_CheckOrigin(t *testing.T) {
+	origins := Origins{"https://app.example.com"}
+	for origin, want := range map[string]bool{
+		"":                         true, // not a browser
+		"https://app.example.com":  true,
+		"https://evil.example.com": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/query", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if got := origins.CheckOrigin(req); got != want {
+			t.Errorf("CheckOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// Drainer tracks long-lived websocket subscriptions, which http.Server.Shutdown
+// does not wait for because their connections are hijacked
+type Drainer struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+	draining bool
+}
+
+func NewDrainer() *Drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Drainer{ctx: ctx, cancel: cancel}
+}
+
+// Middleware ties every websocket upgrade to the drainer. Once draining starts
+// new upgrades are refused and open ones are closed with a close reason.
+func (d *Drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isWebsocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		d.wg.Add(1)
+		d.mu.Unlock()
+		defer d.wg.Done()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(d.ctx, func() {
+			cancel()
+		})
+		defer stop()
+
+		next.ServeHTTP(w, r.WithContext(transport.AppendCloseReason(ctx, "server shutting down")))
+	})
+}
+
+// Drain closes open subscriptions and waits for them to finish or for ctx to expire
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
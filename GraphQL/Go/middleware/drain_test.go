@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func upgradeRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/query", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	return req
+}
+
+func TestDrainer_ClosesSubscriptions(t *testing.T) {
+	d := NewDrainer()
+	started := make(chan struct{})
+	// subscription stands in for a websocket that stays open until its context ends
+	subscription := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	finished := make(chan struct{})
+	go func() {
+		subscription.ServeHTTP(httptest.NewRecorder(), upgradeRequest())
+		close(finished)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Drain(ctx); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("drain returned before the subscription finished")
+	}
+
+	// New upgrades are refused; ordinary requests are still served
+	rec := httptest.NewRecorder()
+	subscription.ServeHTTP(rec, upgradeRequest())
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("upgrade while draining: status %d, want 503", rec.Code)
+	}
+	plain := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec = httptest.NewRecorder()
+	plain.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("plain request while draining: status %d, want 200", rec.Code)
+	}
+}
+
+func TestDrainer_GivesUpAtDeadline(t *testing.T) {
+	d := NewDrainer()
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	// stuck ignores its context, like a handler blocked on a write
+	stuck := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	go stuck.ServeHTTP(httptest.NewRecorder(), upgradeRequest())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("drain = %v, want the deadline", err)
+	}
+}
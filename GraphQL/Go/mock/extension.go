@@ -0,0 +1,128 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// ErrNotImplemented lets a resolver hand its field to the mocks
+var ErrNotImplemented = errors.New("not implemented")
+
+// Extension answers resolver fields with generated data. Fields stored on
+// model structs need nothing: they read the structs the mocks generated.
+// Register it with srv.Use.
+type Extension struct {
+	gen *Generator
+	// types maps object.field to the Go type its resolver returns
+	types map[string]reflect.Type
+	all   bool
+	// fields are mocked even when all is false
+	fields map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = (*Extension)(nil)
+
+// NewExtension mocks every resolver field when all is set. Otherwise the real
+// resolvers run, except for the listed fields ("User.followers") and any
+// resolver that returns ErrNotImplemented or panics the way gqlgen's
+// generated stubs do, which fall back to mocks.
+//
+// resolverRoot is the generated ResolverRoot interface type; its methods tell
+// the mocks what Go type each resolver returns.
+func NewExtension(gen *Generator, resolverRoot reflect.Type, all bool, fields []string) (*Extension, error) {
+	e := &Extension{gen: gen, types: make(map[string]reflect.Type), all: all, fields: make(map[string]bool)}
+	for i := range resolverRoot.NumMethod() {
+		object := resolverRoot.Method(i)
+		resolver := object.Type.Out(0)
+		for j := range resolver.NumMethod() {
+			field := resolver.Method(j)
+			e.types[object.Name+"."+strings.ToLower(field.Name)] = field.Type.Out(0)
+		}
+	}
+	for _, field := range fields {
+		if _, ok := e.resultType(field); !ok {
+			return nil, fmt.Errorf("mock: %s is not a field with a resolver", field)
+		}
+		e.fields[field] = true
+	}
+	return e, nil
+}
+
+func (e *Extension) ExtensionName() string {
+	return "Mock"
+}
+
+func (e *Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e *Extension) InterceptField(ctx context.Context, next graphql.Resolver) (res any, err error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	field := fc.Object + "." + fc.Field.Name
+	if e.all || e.fields[field] {
+		return e.mock(fc, field)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if stub, ok := r.(error); !ok || !strings.HasPrefix(stub.Error(), "not implemented") {
+				panic(r)
+			}
+			res, err = e.mock(fc, field)
+		}
+	}()
+	res, err = next(ctx)
+	if errors.Is(err, ErrNotImplemented) {
+		return e.mock(fc, field)
+	}
+	return res, err
+}
+
+func (e *Extension) mock(fc *graphql.FieldContext, field string) (any, error) {
+	goType, ok := e.resultType(field)
+	if !ok {
+		return nil, fmt.Errorf("no mock for %s", field)
+	}
+	path := fieldPath(fc)
+	parent := path[:max(strings.LastIndex(path, "."), 0)]
+	if fixed, ok := e.gen.Fixed(fc.Object, parent, fc.Field.Name, goType); ok {
+		return fixed.Interface(), nil
+	}
+	return e.gen.Value(path, fc.Field.Definition.Type, goType, e.gen.ListSize(field, fc.Args)).Interface(), nil
+}
+
+func (e *Extension) resultType(field string) (reflect.Type, bool) {
+	object, name, _ := strings.Cut(field, ".")
+	t, ok := e.types[object+"."+strings.ToLower(name)]
+	return t, ok
+}
+
+// fieldPath is the field's position in the response by field name, so
+// aliasing a field does not change its data
+func fieldPath(fc *graphql.FieldContext) string {
+	var parts []string
+	for ; fc != nil; fc = fc.Parent {
+		switch {
+		case fc.Index != nil:
+			parts = append(parts, strconv.Itoa(*fc.Index))
+		case fc.Field.Field != nil:
+			parts = append(parts, fc.Field.Name)
+		}
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, ".")
+}
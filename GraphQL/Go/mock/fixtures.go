@@ -0,0 +1,146 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures override generated values per object type. Each type has a list of
+// partial objects; every generated object of that type takes one of them
+// (chosen by its path) and uses its values for the fields it sets. Only scalar
+// and enum fields can be set, e.g.
+//
+//	User:
+//	  - name: Ada Lovelace
+//	    email: ada@example.com
+//	  - name: Grace Hopper
+//	Post:
+//	  - status: PUBLISHED
+type Fixtures map[string][]map[string]any
+
+// LoadFixtures reads fixtures from a YAML or JSON file
+func LoadFixtures(path string) (Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read mock fixtures: %w", err)
+	}
+	var f Fixtures
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &f)
+	case ".json":
+		err = json.Unmarshal(data, &f)
+	default:
+		return nil, fmt.Errorf("mock fixtures %s: unsupported file type", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mock fixtures %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (f Fixtures) pick(typeName string, h uint64) map[string]any {
+	objects := f[typeName]
+	if len(objects) == 0 {
+		return nil
+	}
+	return objects[h%uint64(len(objects))]
+}
+
+// check reports fixtures that do not fit the schema
+func (f Fixtures) check(schema *ast.Schema) error {
+	for typeName, objects := range f {
+		def := schema.Types[typeName]
+		if def == nil || def.Kind != ast.Object {
+			return fmt.Errorf("mock fixtures: %s is not an object type", typeName)
+		}
+		for _, object := range objects {
+			for name, value := range object {
+				field := def.Fields.ForName(name)
+				if field == nil {
+					return fmt.Errorf("mock fixtures: %s has no field %s", typeName, name)
+				}
+				if err := checkValue(schema, field.Type, value); err != nil {
+					return fmt.Errorf("mock fixtures: %s.%s: %w", typeName, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkValue(schema *ast.Schema, t *ast.Type, value any) error {
+	if value == nil {
+		if t.NonNull {
+			return fmt.Errorf("must not be null")
+		}
+		return nil
+	}
+	if t.Elem != nil {
+		list, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("must be a list")
+		}
+		for _, item := range list {
+			if err := checkValue(schema, t.Elem, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	def := schema.Types[t.NamedType]
+	switch {
+	case def.Kind == ast.Enum:
+		s, _ := value.(string)
+		if def.EnumValues.ForName(s) == nil {
+			return fmt.Errorf("%v is not a %s value", value, def.Name)
+		}
+	case def.Kind != ast.Scalar:
+		return fmt.Errorf("only scalar and enum fields can be fixed")
+	case def.Name == "Int" || def.Name == "Float":
+		n, ok := number(value)
+		if !ok || (def.Name == "Int" && (n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32)) {
+			return fmt.Errorf("%v is not a valid %s", value, def.Name)
+		}
+	case def.Name == "Boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%v is not a Boolean", value)
+		}
+	case def.Name == "Time":
+		if _, ok := value.(time.Time); ok {
+			return nil
+		}
+		s, _ := value.(string)
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("%v is not an RFC 3339 time", value)
+		}
+	default:
+		if !slices.Contains([]string{"String", "ID"}, def.Name) {
+			return fmt.Errorf("scalar %s cannot be fixed", def.Name)
+		}
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%v is not a string", value)
+		}
+	}
+	return nil
+}
+
+func number(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
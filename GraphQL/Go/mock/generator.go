@@ -0,0 +1,273 @@
+// Package mock generates GraphQL responses from the schema alone, so clients
+// can be built against fields before their resolvers exist.
+//
+// Values are derived from a seed and the field's path in the response (field
+// names and list indexes, not aliases), so the same operation always gets the
+// same data, whatever order fields are resolved in. Non-null fields always get
+// a value; nullable ones are sometimes null. Lists have a fixed length unless
+// the field takes a first or limit argument, which is honoured up to the
+// field's page size limit.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Options tune the generated data. Zero values fall back to DefaultOptions.
+type Options struct {
+	Seed int64
+	// ListSize is the length of lists whose field has no first or limit argument
+	ListSize int
+	// MaxListSize caps lists sized by an argument
+	MaxListSize int
+	// PageSizes caps lists sized by an argument per Object.field, as the
+	// services do; they take precedence over MaxListSize
+	PageSizes map[string]int
+	// NullEvery makes roughly one in NullEvery nullable fields null; 1 never does
+	NullEvery int
+	Fixtures  Fixtures
+}
+
+func DefaultOptions() Options {
+	return Options{Seed: 1, ListSize: 3, MaxListSize: 100, NullEvery: 5}
+}
+
+// Generator builds Go values of the types gqlgen's generated code expects
+type Generator struct {
+	schema *ast.Schema
+	opts   Options
+}
+
+// NewGenerator checks the fixtures against schema
+func NewGenerator(schema *ast.Schema, opts Options) (*Generator, error) {
+	defaults := DefaultOptions()
+	if opts.ListSize <= 0 {
+		opts.ListSize = defaults.ListSize
+	}
+	if opts.MaxListSize <= 0 {
+		opts.MaxListSize = defaults.MaxListSize
+	}
+	if opts.NullEvery <= 0 {
+		opts.NullEvery = defaults.NullEvery
+	}
+	if err := opts.Fixtures.check(schema); err != nil {
+		return nil, err
+	}
+	return &Generator{schema: schema, opts: opts}, nil
+}
+
+// depthLimit stops runaway recursion through object fields stored on structs
+const depthLimit = 16
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Value generates a value of Go type goType for a field of GraphQL type t at
+// path. size, when positive, is the length for lists directly under it; see
+// ListSize.
+func (g *Generator) Value(path string, t *ast.Type, goType reflect.Type, size int) reflect.Value {
+	return g.value(path, t, goType, size, 0)
+}
+
+func (g *Generator) value(path string, t *ast.Type, goType reflect.Type, size, depth int) reflect.Value {
+	if goType.Kind() == reflect.Pointer {
+		if (!t.NonNull && g.null(path)) || depth > depthLimit {
+			return reflect.Zero(goType)
+		}
+		v := reflect.New(goType.Elem())
+		v.Elem().Set(g.value(path, nonNull(t), goType.Elem(), size, depth))
+		return v
+	}
+
+	if t.Elem != nil {
+		if goType.Kind() != reflect.Slice {
+			return reflect.Zero(goType)
+		}
+		n := g.opts.ListSize
+		if size > 0 {
+			n = size
+		}
+		list := reflect.MakeSlice(goType, n, n)
+		for i := range n {
+			list.Index(i).Set(g.value(path+"."+strconv.Itoa(i), t.Elem, goType.Elem(), 0, depth+1))
+		}
+		return list
+	}
+
+	def := g.schema.Types[t.NamedType]
+	if def == nil {
+		return reflect.Zero(goType)
+	}
+	switch def.Kind {
+	case ast.Scalar:
+		return g.scalar(path, def.Name, goType)
+	case ast.Enum:
+		v := reflect.New(goType).Elem()
+		if goType.Kind() == reflect.String && len(def.EnumValues) > 0 {
+			v.SetString(def.EnumValues[g.hash(path)%uint64(len(def.EnumValues))].Name)
+		}
+		return v
+	case ast.Object:
+		return g.object(path, def, goType, size, depth)
+	default:
+		// Interfaces and unions would need the concrete type picked here
+		return reflect.Zero(goType)
+	}
+}
+
+// ListSize is the list length field (Object.field) asks for with its first or
+// limit argument, capped at its page size limit; 0 when it asks for none
+func (g *Generator) ListSize(field string, args map[string]any) int {
+	limit, ok := g.opts.PageSizes[field]
+	if !ok {
+		limit = g.opts.MaxListSize
+	}
+	for _, name := range []string{"first", "limit"} {
+		switch n := args[name].(type) {
+		case *int32:
+			if n != nil {
+				return min(max(int(*n), 0), limit)
+			}
+		case int32:
+			return min(max(int(n), 0), limit)
+		}
+	}
+	return 0
+}
+
+// object fills the struct fields gqlgen generated for def; fields with
+// resolvers have none and are generated when they are resolved
+func (g *Generator) object(path string, def *ast.Definition, goType reflect.Type, size, depth int) reflect.Value {
+	v := reflect.New(goType).Elem()
+	if goType.Kind() != reflect.Struct {
+		return v
+	}
+	fixture := g.opts.Fixtures.pick(def.Name, g.hash(path))
+	for i := range goType.NumField() {
+		sf := goType.Field(i)
+		name := jsonName(sf)
+		field := def.Fields.ForName(name)
+		if field == nil || !sf.IsExported() {
+			continue
+		}
+		if fixed, ok := fixture[name]; ok {
+			v.Field(i).Set(convert(fixed, sf.Type))
+			continue
+		}
+		v.Field(i).Set(g.value(path+"."+name, field.Type, sf.Type, size, depth+1))
+	}
+	return v
+}
+
+// Fixed returns the fixture value for field of an object of type typeName at
+// objectPath, if the fixture picked for that object sets it
+func (g *Generator) Fixed(typeName, objectPath, field string, goType reflect.Type) (reflect.Value, bool) {
+	fixed, ok := g.opts.Fixtures.pick(typeName, g.hash(objectPath))[field]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return convert(fixed, goType), true
+}
+
+func (g *Generator) scalar(path, name string, goType reflect.Type) reflect.Value {
+	h := g.hash(path)
+	v := reflect.New(goType).Elem()
+	switch {
+	case goType == timeType:
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		v.Set(reflect.ValueOf(base.Add(time.Duration(h%(365*24*3600)) * time.Second)))
+	case goType.Kind() == reflect.String:
+		v.SetString(g.text(path, name, h))
+	case goType.Kind() >= reflect.Int && goType.Kind() <= reflect.Int64:
+		v.SetInt(int64(h % 1000))
+	case goType.Kind() >= reflect.Uint && goType.Kind() <= reflect.Uint64:
+		v.SetUint(h % 1000)
+	case goType.Kind() == reflect.Float32 || goType.Kind() == reflect.Float64:
+		v.SetFloat(float64(h%100000) / 100)
+	case goType.Kind() == reflect.Bool:
+		v.SetBool(h%2 == 0)
+	}
+	return v
+}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Margaret", "Radia"}
+	lastNames  = []string{"Lovelace", "Turing", "Liskov", "Ritchie", "Dijkstra", "Allen", "Hopper", "Thompson", "Hamilton", "Perlman"}
+	words      = []string{"graph", "query", "schema", "resolver", "field", "cursor", "edge", "node", "mutation", "fragment", "type", "list"}
+)
+
+// text picks a string that suits the field name, so mock data reads sensibly
+func (g *Generator) text(path, scalar string, h uint64) string {
+	field := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	if _, err := strconv.Atoi(field); err == nil {
+		// A list item; use the list's name
+		parent := path[:strings.LastIndex(path, ".")]
+		field = strings.ToLower(parent[strings.LastIndex(parent, ".")+1:])
+	}
+	first, last := firstNames[h%uint64(len(firstNames))], lastNames[(h/16)%uint64(len(lastNames))]
+
+	switch {
+	case scalar == "ID" || strings.HasSuffix(field, "id"):
+		return strconv.FormatUint(h%1_000_000_000, 36)
+	case strings.Contains(field, "email"):
+		return strings.ToLower(first+"."+last) + "@example.com"
+	case strings.Contains(field, "url"):
+		return "https://example.com/" + strconv.FormatUint(h%100000, 36)
+	case strings.Contains(field, "name"):
+		return first + " " + last
+	case strings.Contains(field, "cursor"):
+		return strconv.FormatUint(h, 36)
+	case field == "date":
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(h%365)).Format(time.DateOnly)
+	}
+	n := 3 + int(h%6)
+	out := make([]string, n)
+	for i := range n {
+		out[i] = words[(h>>(i*5))%uint64(len(words))]
+	}
+	return strings.Join(out, " ")
+}
+
+func (g *Generator) null(path string) bool {
+	return g.opts.NullEvery > 1 && g.hash(path+"?")%uint64(g.opts.NullEvery) == 0
+}
+
+func (g *Generator) hash(path string) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", g.opts.Seed, path)
+	return h.Sum64()
+}
+
+func nonNull(t *ast.Type) *ast.Type {
+	copied := *t
+	copied.NonNull = true
+	return &copied
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// convert turns a fixture value (already checked against the schema) into goType
+func convert(value any, goType reflect.Type) reflect.Value {
+	v := reflect.New(goType)
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, v.Interface())
+	}
+	if err != nil {
+		return reflect.Zero(goType)
+	}
+	return v.Elem()
+}
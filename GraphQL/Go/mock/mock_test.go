@@ -0,0 +1,159 @@
+package mock_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/graphtest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/mock"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+)
+
+// newServer serves mocks for every field, as mock mode does
+func newServer(t *testing.T, opts mock.Options) *graphtest.Server {
+	t.Helper()
+	opts.PageSizes = service.MaxPageSizes
+	gen, err := mock.NewGenerator(graph.NewExecutableSchema(graph.Config{}).Schema(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext, err := mock.NewExtension(gen, reflect.TypeFor[graph.ResolverRoot](), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return graphtest.NewServer(graphtest.Repositories{}, graphtest.Options{Extensions: []graphql.HandlerExtension{ext}})
+}
+
+const userQuery = `{
+	user(id: "1") { id name email postCount followers(first: 2) { edges { node { name } } } }
+	alias: user(id: "1") { name }
+}`
+
+func TestMock_SameSeedSameData(t *testing.T) {
+	first := newServer(t, mock.Options{Seed: 7}).Do(t, graphtest.Request{Query: userQuery})
+	second := newServer(t, mock.Options{Seed: 7}).Do(t, graphtest.Request{Query: userQuery})
+	if !bytes.Equal(first, second) {
+		t.Errorf("seed 7 gave different data:\n%s\n%s", first, second)
+	}
+	if other := newServer(t, mock.Options{Seed: 8}).Do(t, graphtest.Request{Query: userQuery}); bytes.Equal(first, other) {
+		t.Errorf("seeds 7 and 8 gave the same data: %s", first)
+	}
+
+	// Aliases do not change the data a field gets
+	var resp struct {
+		Data struct {
+			User  struct{ Name string }
+			Alias struct{ Name string }
+		}
+	}
+	if err := json.Unmarshal(first, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.User.Name != resp.Data.Alias.Name {
+		t.Errorf("alias got %q, want %q", resp.Data.Alias.Name, resp.Data.User.Name)
+	}
+}
+
+func TestMock_FixturesOverrideFields(t *testing.T) {
+	srv := newServer(t, mock.Options{Fixtures: mock.Fixtures{
+		// name and email are stored on the struct; postCount has a resolver
+		"User": {{"name": "Ada Lovelace", "email": "ada@example.com", "postCount": 7}},
+	}})
+	body := srv.Do(t, graphtest.Request{Query: userQuery})
+
+	var resp struct {
+		Data struct {
+			User struct {
+				Name, Email string
+				PostCount   int
+				Followers   struct {
+					Edges []struct{ Node struct{ Name string } }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	user := resp.Data.User
+	if user.Name != "Ada Lovelace" || user.Email != "ada@example.com" || user.PostCount != 7 {
+		t.Errorf("user = %+v, want the fixture's values", user)
+	}
+	for _, edge := range user.Followers.Edges {
+		if edge.Node.Name != "Ada Lovelace" {
+			t.Errorf("follower name = %q, want the fixture's", edge.Node.Name)
+		}
+	}
+}
+
+func TestMock_InvalidFixturesAreRejected(t *testing.T) {
+	schema := graph.NewExecutableSchema(graph.Config{}).Schema()
+	for name, fixtures := range map[string]mock.Fixtures{
+		"unknown type":   {"Nobody": {{"name": "x"}}},
+		"unknown field":  {"User": {{"nickname": "x"}}},
+		"wrong type":     {"User": {{"postCount": "seven"}}},
+		"bad enum value": {"Post": {{"status": "DELETED"}}},
+		"object field":   {"Post": {{"author": "1"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := mock.NewGenerator(schema, mock.Options{Fixtures: fixtures}); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
+
+func TestMock_ListSizeIsCappedAtPageSize(t *testing.T) {
+	srv := newServer(t, mock.Options{ListSize: 3})
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{`{ user(id: "1") { posts { id } } }`, 3},
+		{`{ feed { edges { cursor } } }`, 20},
+		{`{ feed(first: 2) { edges { cursor } } }`, 2},
+		{`{ feed(first: 100000) { edges { cursor } } }`, 100},
+		{`{ stats { topAuthors(limit: 100000) { postCount } } }`, 100},
+		{`{ webhookDeliveries(subscriptionId: "1", first: 100000) { id } }`, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var resp struct {
+				Data map[string]json.RawMessage
+			}
+			if err := json.Unmarshal(srv.Do(t, graphtest.Request{Query: tt.query}), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if got := listLength(t, resp.Data); got != tt.want {
+				t.Errorf("got %d items, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// listLength finds the one list in a response with a single root field,
+// directly under it or one object down (posts, edges, topAuthors)
+func listLength(t *testing.T, data map[string]json.RawMessage) int {
+	t.Helper()
+	for _, root := range data {
+		var list []json.RawMessage
+		if json.Unmarshal(root, &list) == nil {
+			return len(list)
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(root, &object); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range object {
+			if json.Unmarshal(field, &list) == nil {
+				return len(list)
+			}
+		}
+	}
+	t.Fatal("no list in the response")
+	return 0
+}
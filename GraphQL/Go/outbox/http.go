@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+)
+
+// HTTP posts each event as JSON to a collector endpoint. The event ID is sent
+// as the Idempotency-Key header for the collector to deduplicate on; any 2xx
+// response acknowledges the event.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTP(url string) *HTTP {
+	return &HTTP{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *HTTP) Publish(ctx context.Context, event events.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.ID)
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event collector answered %d", resp.StatusCode)
+	}
+	return nil
+}
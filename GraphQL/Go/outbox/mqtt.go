@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// publishTimeout bounds the wait for a PUBACK. While disconnected the client
+// queues publishes until it reconnects, so without it the relay would stall.
+const publishTimeout = 10 * time.Second
+
+// MQTT publishes each event as JSON at QoS 1 to <prefix>/<tenant>/<type>,
+// e.g. "api-hub/events/default/post.created". QoS 1 is itself at least once;
+// subscribers deduplicate on the event's id field.
+type MQTT struct {
+	client mqtt.Client
+	prefix string
+}
+
+// NewMQTT connects to broker (e.g. "tcp://localhost:1883") in the background
+// and keeps reconnecting; publishes fail, and are retried by the relay, until
+// the connection is up
+func NewMQTT(broker, clientID, prefix string) *MQTT {
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetAutoReconnect(true)
+	client := mqtt.NewClient(opts)
+	client.Connect()
+	return &MQTT{client: client, prefix: prefix}
+}
+
+func (p *MQTT) Publish(ctx context.Context, event events.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	token := p.client.Publish(p.prefix+"/"+event.Tenant+"/"+event.Type, 1, false, body)
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return fmt.Errorf("publish to MQTT: %w", ctx.Err())
+	}
+}
+
+// Close waits briefly for in-flight publishes and disconnects
+func (p *MQTT) Close() {
+	p.client.Disconnect(250)
+}
@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+)
+
+// EventPublisher hands one event to consumers. A nil error acknowledges it;
+// anything else leaves the entry in the outbox to be retried.
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event) error
+}
+
+// InProcess passes events to sinks in this process, such as the webhook service.
+// Sinks return nothing, so an event is acknowledged once every sink has taken
+// it; from then on the sink owns redelivery, as webhook.Dispatcher does with
+// its dead-letter list.
+type InProcess []events.Sink
+
+func (p InProcess) Publish(ctx context.Context, event events.Event) error {
+	for _, sink := range p {
+		sink.Emit(ctx, event)
+	}
+	return nil
+}
+
+// Multi publishes to every publisher. It remembers which publishers accepted
+// each event until all have, so when one fails the retry only goes to the
+// ones that have not, and webhooks are not queued again because a broker was
+// down. The memory is per process: after a restart a retried event reaches
+// every publisher again, which consumers absorb by event ID.
+type Multi struct {
+	publishers []EventPublisher
+
+	mu       sync.Mutex
+	accepted map[string][]bool // event ID -> which publishers accepted it
+}
+
+func NewMulti(publishers ...EventPublisher) *Multi {
+	return &Multi{publishers: publishers, accepted: map[string][]bool{}}
+}
+
+func (m *Multi) Publish(ctx context.Context, event events.Event) error {
+	m.mu.Lock()
+	accepted := m.accepted[event.ID]
+	if accepted == nil {
+		accepted = make([]bool, len(m.publishers))
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for i, p := range m.publishers {
+		if accepted[i] {
+			continue
+		}
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		accepted[i] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(errs) == 0 {
+		delete(m.accepted, event.ID)
+		return nil
+	}
+	m.accepted[event.ID] = accepted
+	return errors.Join(errs...)
+}
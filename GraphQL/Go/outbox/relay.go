@@ -0,0 +1,115 @@
+// Package outbox publishes the events repositories record with their writes.
+//
+// Delivery is at least once: an entry is removed only after the publisher
+// accepts it, so a crash or a failed acknowledgement means it is published
+// again. Every event carries a unique ID that consumers use to drop repeats.
+// Entries are attempted oldest first, but one that keeps failing is held back
+// while later ones go out, so consumers must not rely on strict ordering.
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+)
+
+// RelayOptions tune the relay. Zero values fall back to DefaultRelayOptions.
+type RelayOptions struct {
+	// Interval is how often the outbox is polled when it was found empty
+	Interval  time.Duration
+	BatchSize int
+	// A failed entry waits RetryDelay, doubling per attempt up to MaxRetryDelay
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+func DefaultRelayOptions() RelayOptions {
+	return RelayOptions{
+		Interval:      time.Second,
+		BatchSize:     100,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: 10 * time.Minute,
+	}
+}
+
+// Relay moves entries from the outbox to a publisher
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher EventPublisher
+	opts      RelayOptions
+}
+
+func NewRelay(repo repository.OutboxRepository, publisher EventPublisher, opts RelayOptions) *Relay {
+	defaults := DefaultRelayOptions()
+	if opts.Interval <= 0 {
+		opts.Interval = defaults.Interval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaults.RetryDelay
+	}
+	if opts.MaxRetryDelay <= 0 {
+		opts.MaxRetryDelay = defaults.MaxRetryDelay
+	}
+	return &Relay{repo: repo, publisher: publisher, opts: opts}
+}
+
+// Run publishes until ctx is cancelled. Whatever is still pending is picked up
+// by the next run.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch means more may be waiting
+		if r.drain(ctx) == r.opts.BatchSize && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes one batch and returns how many entries it held
+func (r *Relay) drain(ctx context.Context) int {
+	entries, err := r.repo.Pending(ctx, time.Now(), r.opts.BatchSize)
+	if err != nil {
+		log.Printf("outbox: read pending: %v", err)
+		return 0
+	}
+
+	for _, entry := range entries {
+		if err := r.publisher.Publish(ctx, entry.Event); err != nil {
+			next := time.Now().Add(r.backoff(entry.Attempts))
+			if markErr := r.repo.MarkFailed(ctx, entry.Seq, err.Error(), next); markErr != nil {
+				log.Printf("outbox: mark %s failed: %v", entry.Event.ID, markErr)
+			}
+			log.Printf("outbox: publish %s %s (attempt %d): %v", entry.Event.Type, entry.Event.ID, entry.Attempts+1, err)
+			continue
+		}
+		// If this fails the entry is published again; consumers dedupe on the event ID
+		if err := r.repo.MarkPublished(ctx, entry.Seq); err != nil {
+			log.Printf("outbox: mark %s published: %v", entry.Event.ID, err)
+		}
+	}
+	return len(entries)
+}
+
+// backoff is the wait after a failure when the entry had already failed attempts times
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.opts.RetryDelay
+	for range attempts {
+		if delay >= r.opts.MaxRetryDelay {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, r.opts.MaxRetryDelay)
+}
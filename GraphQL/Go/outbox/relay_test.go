@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// flakyPublisher fails while err is set and records what it accepted
+type flakyPublisher struct {
+	err       error
+	published []string
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event events.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+// newOutbox returns an outbox holding one user.created entry
+func newOutbox(t *testing.T) *repository.InMemoryOutboxRepository {
+	t.Helper()
+	outbox := repository.NewInMemoryOutboxRepository()
+	users := repository.NewInMemoryUserRepository()
+	users.UseOutbox(outbox)
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	event := events.Event{ID: "e1", Type: events.UserCreated, Tenant: tenant.Default}
+	if err := users.Create(ctx, &model.User{ID: "1", Email: "a@example.com"}, event); err != nil {
+		t.Fatal(err)
+	}
+	return outbox
+}
+
+func pending(t *testing.T, repo repository.OutboxRepository, now time.Time) []*repository.OutboxEntry {
+	t.Helper()
+	entries, err := repo.Pending(context.Background(), now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestRelay_FailedPublishKeepsEntry(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutbox(t)
+	publisher := &flakyPublisher{err: errors.New("broker down")}
+	relay := NewRelay(repo, publisher, RelayOptions{RetryDelay: time.Minute, MaxRetryDelay: time.Hour})
+
+	if n := relay.drain(ctx); n != 1 {
+		t.Fatalf("drain handled %d entries, want 1", n)
+	}
+	// Held back for the retry delay, then due again
+	if entries := pending(t, repo, time.Now()); len(entries) != 0 {
+		t.Errorf("entry is due again right after failing: %+v", entries[0])
+	}
+	entries := pending(t, repo, time.Now().Add(time.Minute+time.Second))
+	if len(entries) != 1 || entries[0].Attempts != 1 || entries[0].LastError != "broker down" {
+		t.Fatalf("pending = %+v, want the entry with one failed attempt", entries)
+	}
+	if relay.drain(ctx) != 0 {
+		t.Error("drain published an entry still in backoff")
+	}
+
+	// Fail it again so it is due now, as if the minute had passed; with two
+	// failed attempts behind it, the relay's failure then waits four minutes
+	repo.MarkFailed(ctx, entries[0].Seq, "broker down", time.Now())
+	relay.drain(ctx)
+	if entries := pending(t, repo, time.Now().Add(3*time.Minute)); len(entries) != 0 {
+		t.Error("entry is due before its doubled retry delay")
+	}
+	if entries := pending(t, repo, time.Now().Add(5*time.Minute)); len(entries) != 1 || entries[0].Attempts != 3 {
+		t.Errorf("pending = %+v, want the entry after three failed attempts", entries)
+	}
+	if len(publisher.published) != 0 {
+		t.Errorf("published %v while the publisher was failing", publisher.published)
+	}
+}
+
+func TestRelay_SuccessfulPublishRemovesEntry(t *testing.T) {
+	ctx := context.Background()
+	repo := newOutbox(t)
+	publisher := &flakyPublisher{}
+	relay := NewRelay(repo, publisher, RelayOptions{})
+
+	relay.drain(ctx)
+
+	if len(publisher.published) != 1 || publisher.published[0] != "e1" {
+		t.Errorf("published %v, want e1", publisher.published)
+	}
+	if entries := pending(t, repo, time.Now().Add(time.Hour)); len(entries) != 0 {
+		t.Errorf("outbox still holds %d entries", len(entries))
+	}
+	if relay.drain(ctx) != 0 || len(publisher.published) != 1 {
+		t.Error("entry was published twice")
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(repository.NewInMemoryOutboxRepository(), &flakyPublisher{}, RelayOptions{RetryDelay: time.Second, MaxRetryDelay: 10 * time.Second})
+
+	for attempts, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if got := relay.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestMulti_RetriesOnlyFailedPublishers(t *testing.T) {
+	ok, failing := &flakyPublisher{}, &flakyPublisher{err: errors.New("down")}
+	multi := NewMulti(ok, failing)
+	event := events.Event{ID: "e1"}
+
+	if err := multi.Publish(context.Background(), event); err == nil {
+		t.Fatal("got no error")
+	}
+	if err := multi.Publish(context.Background(), event); err == nil {
+		t.Fatal("got no error while a publisher is still down")
+	}
+	failing.err = nil
+	if err := multi.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	// The healthy publisher saw the event once; the failing one once it recovered
+	if len(ok.published) != 1 || len(failing.published) != 1 {
+		t.Errorf("published to %v and %v, want e1 once each", ok.published, failing.published)
+	}
+	if len(multi.accepted) != 0 {
+		t.Errorf("still tracking %d events", len(multi.accepted))
+	}
+
+	// A later event goes to every publisher again
+	if err := multi.Publish(context.Background(), events.Event{ID: "e2"}); err != nil || len(ok.published) != 2 {
+		t.Errorf("e2: %v, healthy publisher got %v", err, ok.published)
+	}
+}
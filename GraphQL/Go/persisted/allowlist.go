@@ -0,0 +1,134 @@
+package persisted
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ManifestFormat identifies Apollo's persisted query manifest, the format
+// produced by client build tooling such as generate-persisted-query-manifest
+const ManifestFormat = "apollo-persisted-query-manifest"
+
+func init() {
+	// Refusals are protocol errors (HTTP 422); PERSISTED_QUERY_NOT_FOUND stays a
+	// user error because APQ clients expect it with a 200 before retrying
+	errcode.RegisterErrorType("OPERATION_NOT_ALLOWED", errcode.KindProtocol)
+	errcode.RegisterErrorType("PERSISTED_QUERY_HASH_MISMATCH", errcode.KindProtocol)
+}
+
+// Manifest is a read-only Store holding the operations a client shipped with
+type Manifest struct {
+	queries map[string]string
+}
+
+// LoadManifest reads a manifest file and checks every id is the hash of its body
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read query manifest: %w", err)
+	}
+
+	var file struct {
+		Format     string `json:"format"`
+		Version    int    `json:"version"`
+		Operations []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Body string `json:"body"`
+		} `json:"operations"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse query manifest %s: %w", path, err)
+	}
+	if file.Format != ManifestFormat || file.Version != 1 {
+		return nil, fmt.Errorf("query manifest %s: want format %q version 1", path, ManifestFormat)
+	}
+
+	m := &Manifest{queries: make(map[string]string, len(file.Operations))}
+	for _, op := range file.Operations {
+		if Hash(op.Body) != op.ID {
+			return nil, fmt.Errorf("query manifest %s: id of operation %q is not the SHA-256 of its body", path, op.Name)
+		}
+		m.queries[op.ID] = op.Body
+	}
+	return m, nil
+}
+
+func (m *Manifest) Get(ctx context.Context, hash string) (string, bool) {
+	query, ok := m.queries[hash]
+	return query, ok
+}
+
+// Add does nothing: in strict mode clients cannot register new operations
+func (m *Manifest) Add(ctx context.Context, hash, query string) {}
+
+// Len returns the number of operations in the manifest
+func (m *Manifest) Len() int {
+	return len(m.queries)
+}
+
+// Allowlist is the strict mode extension: only operations found in Store may
+// run. Clients may send the APQ hash alone or the full text of a listed
+// operation; anything else is rejected before parsing. Use it instead of
+// extension.AutomaticPersistedQuery, never alongside it.
+type Allowlist struct {
+	Store Store
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = Allowlist{}
+
+func (a Allowlist) ExtensionName() string {
+	return "OperationAllowlist"
+}
+
+func (a Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	if a.Store == nil {
+		return errors.New("Allowlist.Store can not be nil")
+	}
+	return nil
+}
+
+func (a Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := ""
+	if ext, ok := rawParams.Extensions["persistedQuery"].(map[string]any); ok {
+		hash, _ = ext["sha256Hash"].(string)
+	}
+
+	if rawParams.Query == "" {
+		if hash == "" {
+			return protocolError("PERSISTED_QUERY_NOT_FOUND", "a persisted query hash is required")
+		}
+		query, ok := a.Store.Get(ctx, hash)
+		if !ok {
+			// Same message and code as APQ so clients behave alike, but a retry with the text is refused below
+			return protocolError("PERSISTED_QUERY_NOT_FOUND", "PersistedQueryNotFound")
+		}
+		rawParams.Query = query
+		return nil
+	}
+
+	sum := Hash(rawParams.Query)
+	if hash != "" && hash != sum {
+		return protocolError("PERSISTED_QUERY_HASH_MISMATCH", "provided APQ hash does not match query")
+	}
+	if _, ok := a.Store.Get(ctx, sum); !ok {
+		return protocolError("OPERATION_NOT_ALLOWED", "operation is not in the allowlist")
+	}
+	return nil
+}
+
+func protocolError(code, message string) *gqlerror.Error {
+	err := gqlerror.Errorf("%s", message)
+	errcode.Set(err, code)
+	return err
+}
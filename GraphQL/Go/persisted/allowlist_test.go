@@ -0,0 +1,84 @@
+package persisted
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestAllowlist_MutateOperationParameters(t *testing.T) {
+	const listed = "{ viewer { id } }"
+	const unlisted = "{ users { id } }"
+	allowlist := Allowlist{Store: &Manifest{queries: map[string]string{Hash(listed): listed}}}
+
+	persistedQuery := func(hash string) map[string]any {
+		return map[string]any{"persistedQuery": map[string]any{"version": 1, "sha256Hash": hash}}
+	}
+
+	tests := []struct {
+		name      string
+		params    graphql.RawParams
+		wantCode  string
+		wantQuery string
+	}{
+		{
+			name:      "hash only",
+			params:    graphql.RawParams{Extensions: persistedQuery(Hash(listed))},
+			wantQuery: listed,
+		},
+		{
+			name:      "text only",
+			params:    graphql.RawParams{Query: listed},
+			wantQuery: listed,
+		},
+		{
+			name:      "text and matching hash",
+			params:    graphql.RawParams{Query: listed, Extensions: persistedQuery(Hash(listed))},
+			wantQuery: listed,
+		},
+		{
+			name:     "hash mismatch",
+			params:   graphql.RawParams{Query: listed, Extensions: persistedQuery(Hash(unlisted))},
+			wantCode: "PERSISTED_QUERY_HASH_MISMATCH",
+		},
+		{
+			name:     "text not listed",
+			params:   graphql.RawParams{Query: unlisted},
+			wantCode: "OPERATION_NOT_ALLOWED",
+		},
+		{
+			name:     "hash not listed",
+			params:   graphql.RawParams{Extensions: persistedQuery(Hash(unlisted))},
+			wantCode: "PERSISTED_QUERY_NOT_FOUND",
+		},
+		{
+			name:     "neither text nor hash",
+			params:   graphql.RawParams{},
+			wantCode: "PERSISTED_QUERY_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			err := allowlist.MutateOperationParameters(context.Background(), &params)
+
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("got no error, want %s", tt.wantCode)
+				}
+				if code := err.Extensions["code"]; code != tt.wantCode {
+					t.Errorf("code = %v, want %s", code, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.Query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", params.Query, tt.wantQuery)
+			}
+		})
+	}
+}
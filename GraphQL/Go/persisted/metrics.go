@@ -0,0 +1,53 @@
+package persisted
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics counts store lookups so cache sizing and allowlist coverage can be tuned
+type Metrics struct {
+	lookups *prometheus.CounterVec
+}
+
+// NewMetrics creates the collectors and registers them with reg
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "graphql",
+			Name:      "persisted_query_lookups_total",
+			Help:      "Persisted query lookups by hash, by store and hit or miss.",
+		}, []string{"store", "result"}),
+	}
+	reg.MustRegister(m.lookups)
+	return m
+}
+
+// Instrument wraps store so every Get is counted under the given store label
+func (m *Metrics) Instrument(name string, store Store) Store {
+	return &instrumentedStore{
+		next: store,
+		hit:  m.lookups.WithLabelValues(name, "hit"),
+		miss: m.lookups.WithLabelValues(name, "miss"),
+	}
+}
+
+type instrumentedStore struct {
+	next      Store
+	hit, miss prometheus.Counter
+}
+
+func (s *instrumentedStore) Get(ctx context.Context, hash string) (string, bool) {
+	query, ok := s.next.Get(ctx, hash)
+	if ok {
+		s.hit.Inc()
+	} else {
+		s.miss.Inc()
+	}
+	return query, ok
+}
+
+func (s *instrumentedStore) Add(ctx context.Context, hash, query string) {
+	s.next.Add(ctx, hash, query)
+}
@@ -0,0 +1,127 @@
+// Package persisted stores GraphQL queries by the SHA-256 hash of their text.
+// It backs Automatic Persisted Queries and the strict operation allowlist.
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+)
+
+// Store looks up query text by hash. It is gqlgen's APQ cache interface, so any
+// Store can be handed to extension.AutomaticPersistedQuery.
+type Store = graphql.Cache[string]
+
+// Hash returns the hex SHA-256 of a query, the key used by APQ clients
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash reports whether key looks like a hex SHA-256; FileStore relies on it
+// to keep client-supplied keys from naming paths outside its directory
+func validHash(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
+
+// NewLRUStore keeps the size most recently used queries in memory
+func NewLRUStore(size int) Store {
+	return lru.New[string](size)
+}
+
+// FileStore keeps one <hash>.graphql file per query in a directory, so
+// registrations survive restarts and can be shared by replicas on a common volume.
+// Any client may register a query over APQ, so the store holds at most
+// maxEntries of them and refuses new ones once full; files are never evicted
+// since other replicas may still be serving them. The count is per process, so
+// replicas sharing a directory can together write up to maxEntries each.
+type FileStore struct {
+	dir        string
+	maxEntries int
+
+	mu    sync.Mutex
+	count int
+}
+
+func NewFileStore(dir string, maxEntries int) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create persisted query directory: %w", err)
+	}
+	existing, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return nil, fmt.Errorf("list persisted queries: %w", err)
+	}
+	return &FileStore{dir: dir, maxEntries: maxEntries, count: len(existing)}, nil
+}
+
+func (s *FileStore) Get(ctx context.Context, hash string) (string, bool) {
+	if !validHash(hash) {
+		return "", false
+	}
+	data, err := os.ReadFile(s.path(hash))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Add writes through a temporary file so readers never see a partial query.
+// Queries already stored are left alone and new ones are dropped once the
+// store is full, so clients keep sending the full text of those.
+func (s *FileStore) Add(ctx context.Context, hash, query string) {
+	if !validHash(hash) {
+		return
+	}
+	if _, err := os.Stat(s.path(hash)); err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count >= s.maxEntries {
+		log.Printf("persisted query %s not stored: store is full (%d queries)", hash, s.maxEntries)
+		return
+	}
+
+	tmp, err := os.CreateTemp(s.dir, hash+".*.tmp")
+	if err != nil {
+		log.Printf("persisted query %s not stored: %v", hash, err)
+		return
+	}
+	_, err = tmp.WriteString(query)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(hash))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("persisted query %s not stored: %v", hash, err)
+		return
+	}
+	s.count++
+}
+
+// Len returns the number of queries this process counts as stored
+func (s *FileStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (s *FileStore) path(hash string) string {
+	return filepath.Join(s.dir, hash+".graphql")
+}
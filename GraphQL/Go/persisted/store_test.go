@@ -0,0 +1,73 @@
+package persisted
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore_RefusesQueriesOnceFull(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := []string{"{ a }", "{ b }", "{ c }"}
+	for _, q := range queries {
+		store.Add(ctx, Hash(q), q)
+	}
+
+	for i, q := range queries {
+		_, ok := store.Get(ctx, Hash(q))
+		if want := i < 2; ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", q, ok, want)
+		}
+	}
+	// Storing a query again does not count against the limit
+	store.Add(ctx, Hash(queries[0]), queries[0])
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+}
+
+func TestFileStore_CountsExistingFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, Hash("{ a }")+".graphql"), []byte("{ a }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewFileStore(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(ctx, Hash("{ b }"), "{ b }")
+
+	if _, ok := store.Get(ctx, Hash("{ a }")); !ok {
+		t.Error("query written before the store opened is missing")
+	}
+	if _, ok := store.Get(ctx, Hash("{ b }")); ok {
+		t.Error("store accepted a query past its limit")
+	}
+}
+
+func TestFileStore_IgnoresInvalidHashes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.Add(ctx, "../escape", "{ a }")
+
+	if store.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", store.Len())
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.graphql")); err == nil {
+		t.Error("store wrote outside its directory")
+	}
+}
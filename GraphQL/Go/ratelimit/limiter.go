@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrCode is the extensions.code of rejected operations
+const ErrCode = "RATE_LIMITED"
+
+// Limiter charges each operation its complexity against the caller's budget.
+// Register it with srv.Use; callers are identified by Middleware, and
+// operations that did not pass through it are not limited. A Limit with no
+// points leaves that kind of operation unlimited.
+type Limiter struct {
+	Store Store
+	// Queries also covers subscriptions, charged once when they start
+	Queries   Limit
+	Mutations Limit
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = (*Limiter)(nil)
+
+const extensionName = "RateLimit"
+
+// Stats describe the charge for an operation
+type Stats struct {
+	Cost      int
+	Remaining int
+}
+
+func (l *Limiter) ExtensionName() string {
+	return extensionName
+}
+
+func (l *Limiter) Validate(schema graphql.ExecutableSchema) error {
+	if l.Store == nil {
+		return errors.New("rate limiter needs a store")
+	}
+	l.es = schema
+	return nil
+}
+
+func (l *Limiter) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	req, ok := ctx.Value(requestKey{}).(*request)
+	if !ok {
+		return nil
+	}
+
+	kind, limit := "query", l.Queries
+	if oc.Operation.Operation == ast.Mutation {
+		kind, limit = "mutation", l.Mutations
+	}
+	if limit.Points <= 0 || limit.Period <= 0 {
+		return nil
+	}
+
+	cost := max(complexity.Calculate(ctx, l.es, oc.Operation, oc.Variables), 1)
+	if cost > limit.Points {
+		err := gqlerror.Errorf("operation costs %d points, more than the %s budget of %d per %s", cost, kind, limit.Points, limit.Period)
+		err.Extensions = map[string]any{"cost": cost, "limit": limit.Points}
+		errcode.Set(err, ErrCode)
+		return err
+	}
+
+	res, err := l.Store.Take(ctx, req.caller+"/"+kind, cost, limit)
+	if err != nil {
+		// A broken store should not take the API down with it
+		log.Printf("ratelimit: %v", err)
+		return nil
+	}
+	oc.Stats.SetExtension(extensionName, &Stats{Cost: cost, Remaining: res.Remaining})
+	if res.Allowed {
+		return nil
+	}
+
+	req.reject(res.RetryAfter)
+	gqlErr := gqlerror.Errorf("rate limit exceeded: this %s costs %d points and %d are left, retry in %ds", kind, cost, res.Remaining, seconds(res.RetryAfter))
+	gqlErr.Extensions = map[string]any{
+		"cost":       cost,
+		"remaining":  res.Remaining,
+		"retryAfter": seconds(res.RetryAfter),
+	}
+	errcode.Set(gqlErr, ErrCode)
+	return gqlErr
+}
+
+// GetStats returns the charge Limiter made for the current operation, if any
+func GetStats(ctx context.Context) *Stats {
+	if !graphql.HasOperationContext(ctx) {
+		return nil
+	}
+	stats, _ := graphql.GetOperationContext(ctx).Stats.GetExtension(extensionName).(*Stats)
+	return stats
+}
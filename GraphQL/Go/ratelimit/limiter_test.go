@@ -0,0 +1,104 @@
+package ratelimit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/graphtest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/ratelimit"
+)
+
+// newServer limits queries to points per minute
+func newServer(points int) http.Handler {
+	limiter := &ratelimit.Limiter{
+		Store:   ratelimit.NewMemoryStore(),
+		Queries: ratelimit.Limit{Points: points, Period: time.Minute},
+	}
+	srv := graphtest.NewServer(graphtest.NewRepositories(), graphtest.Options{
+		Extensions: []graphql.HandlerExtension{limiter},
+		Middleware: ratelimit.Middleware(ratelimit.Options{}),
+	})
+	return srv.Handler()
+}
+
+type response struct {
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query, as string) (*httptest.ResponseRecorder, response) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if as != "" {
+		req.Header.Set(auth.UserHeader, as)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res response
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	return rec, res
+}
+
+func TestLimiter_RejectsWith429(t *testing.T) {
+	h := newServer(2)
+
+	for i := range 2 {
+		if rec, res := post(t, h, `{ __typename }`, "u1"); rec.Code != http.StatusOK || len(res.Errors) > 0 {
+			t.Fatalf("request %d: status %d, errors %+v", i+1, rec.Code, res.Errors)
+		}
+	}
+
+	rec, res := post(t, h, `{ __typename }`, "u1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", rec.Code)
+	}
+	// One point comes back every 30 seconds
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != ratelimit.ErrCode {
+		t.Fatalf("errors = %+v, want one %s", res.Errors, ratelimit.ErrCode)
+	}
+	if got := res.Errors[0].Extensions["retryAfter"]; got != float64(30) {
+		t.Errorf("extensions.retryAfter = %v, want 30", got)
+	}
+
+	// Another caller has a budget of its own
+	if rec, _ := post(t, h, `{ __typename }`, "u2"); rec.Code != http.StatusOK {
+		t.Errorf("other user: status %d, want 200", rec.Code)
+	}
+}
+
+func TestLimiter_RejectsOperationsOverBudget(t *testing.T) {
+	h := newServer(2)
+
+	rec, res := post(t, h, `{ users { id name email } }`, "u1")
+
+	if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != ratelimit.ErrCode {
+		t.Fatalf("errors = %+v, want one %s", res.Errors, ratelimit.ErrCode)
+	}
+	if res.Errors[0].Extensions["limit"] != float64(2) {
+		t.Errorf("extensions = %v, want the limit of 2", res.Errors[0].Extensions)
+	}
+	// Waiting would not help, so this is not a 429
+	if rec.Code == http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("status %d with Retry-After %q for an operation that can never fit", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	// Nothing was charged
+	if rec, _ := post(t, h, `{ __typename }`, "u1"); rec.Code != http.StatusOK {
+		t.Errorf("follow-up: status %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// APIKeyHeader identifies an integration; only configured keys are honoured
+const APIKeyHeader = "X-API-Key"
+
+// Options control how Middleware identifies callers
+type Options struct {
+	// APIKeys are the keys that get a budget of their own. Unknown keys are
+	// ignored so that clients cannot reset their budget by inventing keys.
+	APIKeys []string
+	// TrustForwardedFor takes the client address from the last X-Forwarded-For
+	// entry, the one added by a reverse proxy in front of the server
+	TrustForwardedFor bool
+}
+
+type requestKey struct{}
+
+// request carries the caller to Limiter and its verdict back to Middleware
+type request struct {
+	caller     string
+	retryAfter time.Duration
+	limited    bool
+	mu         sync.Mutex
+}
+
+func (r *request) reject(retryAfter time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limited = true
+	r.retryAfter = retryAfter
+}
+
+// Middleware identifies the caller for Limiter: by API key, else by the
+// authenticated user, else by IP address, within the request's tenant. It must
+// run after auth and tenant resolution. Rejected HTTP requests are answered
+// with 429 and a Retry-After header.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := &request{caller: caller(r, opts)}
+			r = r.WithContext(context.WithValue(r.Context(), requestKey{}, req))
+
+			// Websocket upgrades need the original writer; errors reach them as messages
+			if r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&limitedWriter{ResponseWriter: w, req: req}, r)
+		})
+	}
+}
+
+func caller(r *http.Request, opts Options) string {
+	id, _ := tenant.FromContext(r.Context())
+	id += "/"
+
+	if key := r.Header.Get(APIKeyHeader); key != "" && slices.Contains(opts.APIKeys, key) {
+		// Keys are hashed so they never end up in a shared store
+		sum := sha256.Sum256([]byte(key))
+		return id + "key:" + hex.EncodeToString(sum[:8])
+	}
+	if user, ok := auth.UserID(r.Context()); ok {
+		return id + "user:" + user
+	}
+	return id + "ip:" + clientIP(r, opts.TrustForwardedFor)
+}
+
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" && trustForwardedFor {
+		hops := strings.Split(forwarded, ",")
+		if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limitedWriter turns the response into a 429 once Limiter has rejected the request
+type limitedWriter struct {
+	http.ResponseWriter
+	req         *request
+	wroteHeader bool
+}
+
+func (w *limitedWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	w.req.mu.Lock()
+	limited, retryAfter := w.req.limited, w.req.retryAfter
+	w.req.mu.Unlock()
+	if limited {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+		}
+		status = http.StatusTooManyRequests
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps multipart (@defer) responses streaming
+func (w *limitedWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *limitedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// seconds rounds up, so clients that wait that long are let through
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
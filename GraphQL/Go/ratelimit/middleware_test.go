@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestCaller(t *testing.T) {
+	opts := Options{APIKeys: []string{"key-1"}}
+
+	tests := []struct {
+		name      string
+		tenant    string
+		user      string
+		apiKey    string
+		remote    string
+		forwarded string
+		trust     bool
+		want      string
+	}{
+		{name: "anonymous", tenant: "acme", remote: "10.0.0.1:5000", want: "acme/ip:10.0.0.1"},
+		{name: "user", tenant: "acme", user: "u1", remote: "10.0.0.1:5000", want: "acme/user:u1"},
+		{name: "same user in another tenant", tenant: "globex", user: "u1", want: "globex/user:u1"},
+		{name: "configured key wins over user", tenant: "acme", user: "u1", apiKey: "key-1", want: "acme/key:"},
+		{name: "unknown key is ignored", tenant: "acme", user: "u1", apiKey: "made-up", want: "acme/user:u1"},
+		{name: "forwarded for, untrusted", tenant: "acme", remote: "10.0.0.1:5000", forwarded: "1.2.3.4", want: "acme/ip:10.0.0.1"},
+		{name: "forwarded for, trusted", tenant: "acme", remote: "10.0.0.1:5000", forwarded: "6.6.6.6, 1.2.3.4", trust: true, want: "acme/ip:1.2.3.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/query", nil)
+			if tt.remote != "" {
+				r.RemoteAddr = tt.remote
+			}
+			if tt.apiKey != "" {
+				r.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			ctx := tenant.WithID(r.Context(), tt.tenant)
+			if tt.user != "" {
+				ctx = auth.WithClaims(ctx, &auth.Claims{Subject: tt.user})
+			}
+			opts := opts
+			opts.TrustForwardedFor = tt.trust
+
+			got := caller(r.WithContext(ctx), opts)
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("caller = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaller_HashesAPIKeys(t *testing.T) {
+	r := httptest.NewRequest("POST", "/query", nil)
+	r.Header.Set(APIKeyHeader, "key-1")
+
+	got := caller(r, Options{APIKeys: []string{"key-1", "key-2"}})
+
+	if strings.Contains(got, "key-1") {
+		t.Errorf("caller %q contains the raw key", got)
+	}
+	r.Header.Set(APIKeyHeader, "key-2")
+	if other := caller(r, Options{APIKeys: []string{"key-1", "key-2"}}); other == got {
+		t.Error("two keys share a budget")
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is gcra run inside Redis, on Redis's clock, so every server
+// sharing the store sees the same budgets. Times are in microseconds.
+var takeScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local period = tonumber(ARGV[1])
+local increment = tonumber(ARGV[2])
+local interval = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+  tat = now
+end
+local next = tat + increment
+if next - period > now then
+  return {0, math.floor((period - (tat - now)) / interval), next - period - now}
+end
+redis.call('SET', KEYS[1], next, 'PX', math.ceil((next - now) / 1000))
+return {1, math.floor((period - (next - now)) / interval), 0}
+`)
+
+// RedisStore keeps budgets in Redis so that replicas share them
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore connects to url (e.g. "redis://localhost:6379/0") and stores
+// budgets under keys starting with prefix
+func NewRedisStore(url, prefix string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis URL: %w", err)
+	}
+	return &RedisStore{client: redis.NewClient(opts), prefix: prefix}, nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, cost int, limit Limit) (Result, error) {
+	interval := limit.interval()
+	out, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Period.Microseconds(),
+		max((time.Duration(cost)*interval).Microseconds(), 1),
+		max(interval.Microseconds(), 1),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit store: %w", err)
+	}
+	return Result{
+		Allowed:    out[0] == 1,
+		Remaining:  int(out[1]),
+		RetryAfter: time.Duration(out[2]) * time.Microsecond,
+	}, nil
+}
+
+// Ping reports whether Redis is reachable, for the readiness probe
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,90 @@
+// Package ratelimit budgets GraphQL operations per caller. Each operation is
+// charged its query complexity against a budget that refills continuously
+// (GCRA), with separate budgets for queries and mutations.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limit is a budget of Points per Period. Spent points come back evenly over
+// the period, and up to Points may be spent at once.
+type Limit struct {
+	Points int
+	Period time.Duration
+}
+
+// interval is how long one point takes to come back
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Points)
+}
+
+// Result is the outcome of charging a caller
+type Result struct {
+	Allowed bool
+	// Remaining is the points left after the charge, or before it when denied
+	Remaining int
+	// RetryAfter is how long until the charge would succeed; zero when allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the budgets. Take must check and charge a key atomically; a
+// store shared by several servers gives each caller one budget across them.
+type Store interface {
+	// Take charges cost points to key under limit. cost must be between 1 and
+	// limit.Points.
+	Take(ctx context.Context, key string, cost int, limit Limit) (Result, error)
+}
+
+// gcra works out a charge from the key's theoretical arrival time, the moment
+// its budget would be full again. It returns the new one to store.
+func gcra(tat, now time.Time, cost int, limit Limit) (time.Time, Result) {
+	if tat.Before(now) {
+		tat = now
+	}
+	interval := limit.interval()
+	next := tat.Add(time.Duration(cost) * interval)
+	if allowAt := next.Add(-limit.Period); allowAt.After(now) {
+		return tat, Result{
+			Remaining:  int((limit.Period - tat.Sub(now)) / interval),
+			RetryAfter: allowAt.Sub(now),
+		}
+	}
+	return next, Result{Allowed: true, Remaining: int((limit.Period - next.Sub(now)) / interval)}
+}
+
+// sweepEvery is how many charges MemoryStore takes between removing keys
+// whose budgets are full again
+const sweepEvery = 1024
+
+// MemoryStore keeps budgets in this process only
+type MemoryStore struct {
+	tats  map[string]time.Time
+	takes int
+	mu    sync.Mutex
+	now   func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tats: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, cost int, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	tat, res := gcra(s.tats[key], now, cost, limit)
+	s.tats[key] = tat
+
+	if s.takes++; s.takes%sweepEvery == 0 {
+		for k, t := range s.tats {
+			if !t.After(now) {
+				delete(s.tats, k)
+			}
+		}
+	}
+	return res, nil
+}
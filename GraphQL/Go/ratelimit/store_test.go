@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a MemoryStore clock the test moves by hand
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = func() time.Time { return clock.now }
+	return s, clock
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	// One point comes back every second
+	limit := Limit{Points: 10, Period: 10 * time.Second}
+	store, clock := newTestStore()
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		cost    int
+		want    Result
+	}{
+		{"fresh budget", 0, 3, Result{Allowed: true, Remaining: 7}},
+		{"spend the rest", 0, 7, Result{Allowed: true, Remaining: 0}},
+		{"empty", 0, 1, Result{Remaining: 0, RetryAfter: time.Second}},
+		{"partly refilled", 500 * time.Millisecond, 1, Result{Remaining: 0, RetryAfter: 500 * time.Millisecond}},
+		{"one point back", 500 * time.Millisecond, 1, Result{Allowed: true, Remaining: 0}},
+		{"too expensive for what is left", 3 * time.Second, 4, Result{Remaining: 3, RetryAfter: time.Second}},
+		{"denials are not charged", 0, 3, Result{Allowed: true, Remaining: 0}},
+		{"full again after a period", time.Minute, 10, Result{Allowed: true, Remaining: 0}},
+		{"refill never exceeds the budget", time.Hour, 1, Result{Allowed: true, Remaining: 9}},
+	}
+	for _, step := range steps {
+		clock.advance(step.advance)
+		got, err := store.Take(context.Background(), "k", step.cost, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != step.want {
+			t.Errorf("%s: Take(%d) = %+v, want %+v", step.name, step.cost, got, step.want)
+		}
+	}
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	limit := Limit{Points: 1, Period: time.Minute}
+	store, _ := newTestStore()
+	ctx := context.Background()
+
+	if res, _ := store.Take(ctx, "a", 1, limit); !res.Allowed {
+		t.Fatal("first charge to a was denied")
+	}
+	if res, _ := store.Take(ctx, "a", 1, limit); res.Allowed {
+		t.Error("second charge to a was allowed")
+	}
+	if res, _ := store.Take(ctx, "b", 1, limit); !res.Allowed {
+		t.Error("b was charged for a's spending")
+	}
+}
+
+func TestGCRA_CostOverBudget(t *testing.T) {
+	// Take's callers must reject such operations first: they can never fit
+	limit := Limit{Points: 10, Period: 10 * time.Second}
+	now := time.Now()
+
+	tat, res := gcra(time.Time{}, now, 11, limit)
+
+	if res.Allowed || res.Remaining != 10 || res.RetryAfter != time.Second {
+		t.Errorf("gcra = %+v, want denied with the full budget remaining", res)
+	}
+	if !tat.Equal(now) {
+		t.Errorf("denied charge moved the arrival time to %v", tat)
+	}
+}
+
+func TestMemoryStore_SweepsFullBudgets(t *testing.T) {
+	limit := Limit{Points: 10, Period: time.Second}
+	store, clock := newTestStore()
+	ctx := context.Background()
+
+	store.Take(ctx, "idle", 1, limit)
+	clock.advance(time.Minute)
+	for range sweepEvery - 1 {
+		store.Take(ctx, "busy", 1, limit)
+	}
+
+	if _, ok := store.tats["idle"]; ok {
+		t.Error("idle key with a full budget was kept")
+	}
+	if _, ok := store.tats["busy"]; !ok {
+		t.Error("busy key was swept while its budget was spent")
+	}
+}
+
+func TestSeconds_RoundsUp(t *testing.T) {
+	for d, want := range map[time.Duration]int{0: 0, time.Millisecond: 1, time.Second: 1, 1500 * time.Millisecond: 2} {
+		if got := seconds(d); got != want {
+			t.Errorf("seconds(%v) = %d, want %d", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// wordsPerMinute is the average adult silent reading speed
+const wordsPerMinute = 200
+
+// Rendered holds everything derived from one piece of Markdown content
+type Rendered struct {
+	HTML  string // sanitized HTML
+	Text  string // plain text with whitespace collapsed
+	Words int
+}
+
+// Renderer turns post Markdown into sanitized HTML, excerpts and reading times.
+// Results are cached by content hash so list queries stay cheap.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+	strip    *bluemonday.Policy
+	cache    *lru.Cache[[sha256.Size]byte, *Rendered]
+}
+
+// NewRenderer creates a renderer that caches up to cacheSize distinct contents
+func NewRenderer(cacheSize int) *Renderer {
+	// CommonMark (including fenced code) plus GFM tables; raw HTML in the
+	// source is dropped by goldmark and the output is sanitized again below
+	md := goldmark.New(goldmark.WithExtensions(extension.Table))
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	policy.AllowAttrs("style").Matching(regexp.MustCompile(`^text-align:\s*(left|right|center)$`)).OnElements("th", "td")
+
+	cache, err := lru.New[[sha256.Size]byte, *Rendered](cacheSize)
+	if err != nil {
+		panic(err) // only fails for a non-positive size, which is a programming error
+	}
+
+	return &Renderer{
+		markdown: md,
+		policy:   policy,
+		strip:    bluemonday.StrictPolicy(),
+		cache:    cache,
+	}
+}
+
+// Render returns the cached rendering of content, computing it on a miss
+func (r *Renderer) Render(content string) *Rendered {
+	key := sha256.Sum256([]byte(content))
+	if cached, ok := r.cache.Get(key); ok {
+		return cached
+	}
+
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(content), &buf); err != nil {
+		// goldmark only fails on writer errors; fall back to escaped text
+		buf.Reset()
+		buf.WriteString("<p>" + html.EscapeString(content) + "</p>")
+	}
+	safe := r.policy.SanitizeBytes(buf.Bytes())
+
+	text := strings.Join(strings.Fields(html.UnescapeString(string(r.strip.SanitizeBytes(spaceBlocks(safe))))), " ")
+	rendered := &Rendered{
+		HTML:  string(safe),
+		Text:  text,
+		Words: len(strings.Fields(text)),
+	}
+	r.cache.Add(key, rendered)
+	return rendered
+}
+
+// Excerpt returns at most length characters of plain text, cut at a word
+// boundary and marked with an ellipsis when shortened
+func (r *Renderer) Excerpt(content string, length int) string {
+	text := r.Render(content).Text
+	if length <= 0 || utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	runes := []rune(text)[:length]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
+
+// ReadingTimeMinutes estimates reading time, rounded up; any text takes at least a minute
+func (r *Renderer) ReadingTimeMinutes(content string) int {
+	words := r.Render(content).Words
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / wordsPerMinute))
+}
+
+var blockEnd = regexp.MustCompile(`</(p|h[1-6]|li|pre|blockquote|tr|td|th)>|<br\s*/?>`)
+
+// spaceBlocks keeps words in adjacent blocks apart once tags are stripped
+func spaceBlocks(b []byte) []byte {
+	return blockEnd.ReplaceAll(b, []byte("$0 "))
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender_Sanitizes(t *testing.T) {
+	r := NewRenderer(8)
+	tests := []struct {
+		name    string
+		content string
+		want    string // a substring of the HTML
+		absent  string // must not appear in the HTML
+	}{
+		{"script block", "<script>alert(1)</script>", "", "alert"},
+		{"inline event handler", `<img src=x onerror=alert(1)>`, "", "onerror"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>", "javascript:"},
+		{"image keeps safe attributes", `![alt](x.png "title")`, `<img src="x.png" alt="alt" title="title">`, ""},
+		{"code language class", "```go\nx\n```", `<code class="language-go">`, ""},
+		{"table alignment", "| a |\n|:-:|\n| 1 |", `<th style="text-align:center">a</th>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Render(tt.content).HTML
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("Render(%q) = %q, want it to contain %q", tt.content, got, tt.want)
+			}
+			if tt.absent != "" && strings.Contains(got, tt.absent) {
+				t.Fatalf("Render(%q) = %q, want no %q", tt.content, got, tt.absent)
+			}
+		})
+	}
+}
+
+// The policy is the last line of defence should the Markdown renderer ever
+// pass raw HTML through, so it is checked on HTML directly
+func TestRender_Policy(t *testing.T) {
+	policy := NewRenderer(8).policy
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"script", `<script>alert(1)</script>`, ""},
+		{"onerror", `<img src="x.png" onerror="alert(1)">`, `<img src="x.png">`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, "x"},
+		{"iframe", `<iframe src="https://example.com"></iframe>`, ""},
+		{"language class", `<code class="language-c++">x</code>`, `<code class="language-c++">x</code>`},
+		{"other class", `<code class="evil">x</code>`, `<code>x</code>`},
+		{"class with extra names", `<code class="language-go evil">x</code>`, `<code>x</code>`},
+		{"class on other elements", `<p class="language-go">x</p>`, `<p>x</p>`},
+		{"cell alignment", `<td style="text-align: center">x</td>`, `<td style="text-align: center">x</td>`},
+		{"other cell style", `<td style="color: red">x</td>`, `<td>x</td>`},
+		{"style smuggled after alignment", `<td style="text-align: left; background: url(x)">x</td>`, `<td>x</td>`},
+		{"style on other elements", `<p style="text-align: left">x</p>`, `<p>x</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Sanitize(tt.html); got != tt.want {
+				t.Fatalf("Sanitize(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
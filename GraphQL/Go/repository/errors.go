@@ -0,0 +1,11 @@
+package repository
+
+import "errors"
+
+// Sentinel errors that every backend wraps so callers can classify failures
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict means the record changed between being read and being written
+	ErrConflict = errors.New("was changed by another request")
+)
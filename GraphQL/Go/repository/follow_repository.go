@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Follow is a directed edge in the follow graph. Seq increases with every
+// follow and gives connections a stable order that survives unfollows.
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	Seq        int64
+	CreatedAt  time.Time
+}
+
+// FollowRepository stores who follows whom.
+// Every operation is scoped to the tenant carried in ctx.
+type FollowRepository interface {
+	// Follow records that followerID follows followeeID; ErrAlreadyExists if it already does
+	Follow(ctx context.Context, followerID, followeeID string) error
+	// Unfollow removes the edge; ErrNotFound if there was none
+	Unfollow(ctx context.Context, followerID, followeeID string) error
+	// ListFollowers returns up to limit edges pointing at userID, newest first,
+	// with Seq below before (0 starts at the newest)
+	ListFollowers(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error)
+	// ListFollowing is ListFollowers for the edges leaving userID
+	ListFollowing(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error)
+	CountFollowers(ctx context.Context, userID string) (int, error)
+	CountFollowing(ctx context.Context, userID string) (int, error)
+	// FollowingIDs returns every user followerID follows
+	FollowingIDs(ctx context.Context, followerID string) ([]string, error)
+	Ping(ctx context.Context) error
+}
+
+type InMemoryFollowRepository struct {
+	// Both directions are indexed so either list is read without a scan; slices are oldest first
+	followers map[string]map[string][]*Follow // tenant ID -> followee ID -> edges
+	following map[string]map[string][]*Follow // tenant ID -> follower ID -> edges
+	seq       int64
+	mu        sync.RWMutex
+}
+
+func NewInMemoryFollowRepository() *InMemoryFollowRepository {
+	return &InMemoryFollowRepository{
+		followers: map[string]map[string][]*Follow{},
+		following: map[string]map[string][]*Follow{},
+	}
+}
+
+func (r *InMemoryFollowRepository) Follow(ctx context.Context, followerID, followeeID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, edge := range r.following[tenantID][followerID] {
+		if edge.FolloweeID == followeeID {
+			return fmt.Errorf("follow from %s to %s %w", followerID, followeeID, ErrAlreadyExists)
+		}
+	}
+
+	if r.followers[tenantID] == nil {
+		r.followers[tenantID] = map[string][]*Follow{}
+		r.following[tenantID] = map[string][]*Follow{}
+	}
+	r.seq++
+	edge := &Follow{FollowerID: followerID, FolloweeID: followeeID, Seq: r.seq, CreatedAt: time.Now()}
+	r.followers[tenantID][followeeID] = append(r.followers[tenantID][followeeID], edge)
+	r.following[tenantID][followerID] = append(r.following[tenantID][followerID], edge)
+	return nil
+}
+
+func (r *InMemoryFollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	following, removed := removeEdge(r.following[tenantID][followerID], followerID, followeeID)
+	if !removed {
+		return fmt.Errorf("follow from %s to %s %w", followerID, followeeID, ErrNotFound)
+	}
+	r.following[tenantID][followerID] = following
+	r.followers[tenantID][followeeID], _ = removeEdge(r.followers[tenantID][followeeID], followerID, followeeID)
+	return nil
+}
+
+func (r *InMemoryFollowRepository) ListFollowers(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error) {
+	return r.list(ctx, r.followers, userID, before, limit)
+}
+
+func (r *InMemoryFollowRepository) ListFollowing(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error) {
+	return r.list(ctx, r.following, userID, before, limit)
+}
+
+func (r *InMemoryFollowRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	return r.count(ctx, r.followers, userID)
+}
+
+func (r *InMemoryFollowRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	return r.count(ctx, r.following, userID)
+}
+
+func (r *InMemoryFollowRepository) FollowingIDs(ctx context.Context, followerID string) ([]string, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	edges := r.following[tenantID][followerID]
+	ids := make([]string, len(edges))
+	for i, edge := range edges {
+		ids[i] = edge.FolloweeID
+	}
+	return ids, nil
+}
+
+// Ping reports backend health for readiness probes; an in-memory store is always reachable
+func (r *InMemoryFollowRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// list walks one index from the newest edge backwards
+func (r *InMemoryFollowRepository) list(ctx context.Context, index map[string]map[string][]*Follow, userID string, before int64, limit int) ([]*Follow, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	edges := index[tenantID][userID]
+	page := make([]*Follow, 0, min(limit, len(edges)))
+	for i := len(edges) - 1; i >= 0 && len(page) < limit; i-- {
+		if before > 0 && edges[i].Seq >= before {
+			continue
+		}
+		page = append(page, edges[i])
+	}
+	return page, nil
+}
+
+func (r *InMemoryFollowRepository) count(ctx context.Context, index map[string]map[string][]*Follow, userID string) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(index[tenantID][userID]), nil
+}
+
+// removeEdge returns edges without the follow from followerID to followeeID
+func removeEdge(edges []*Follow, followerID, followeeID string) ([]*Follow, bool) {
+	for i, edge := range edges {
+		if edge.FollowerID == followerID && edge.FolloweeID == followeeID {
+			return append(edges[:i], edges[i+1:]...), true
+		}
+	}
+	return edges, false
+}
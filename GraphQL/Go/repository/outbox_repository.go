@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+)
+
+// OutboxEntry is an event waiting to be published
+type OutboxEntry struct {
+	Seq int64
+	// Event.Data is the JSON snapshot taken when the entry was written
+	Event         events.Event
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// OutboxRepository is the log of events still to be published. Entries are
+// written by the repository call that stores the change they describe (the
+// events passed to Create and Delete or returned by Modify's mutate), so a change is never stored without its
+// event. Unlike other repositories it spans tenants: one relay drains them all.
+type OutboxRepository interface {
+	// Pending returns up to limit entries due by now, oldest first
+	Pending(ctx context.Context, now time.Time, limit int) ([]*OutboxEntry, error)
+	// MarkPublished removes a delivered entry
+	MarkPublished(ctx context.Context, seq int64) error
+	// MarkFailed records a failed attempt and holds the entry back until next
+	MarkFailed(ctx context.Context, seq int64, reason string, next time.Time) error
+	Ping(ctx context.Context) error
+}
+
+// InMemoryOutboxRepository is shared with the in-memory user and post
+// repositories (see their UseOutbox), which append to it under their own lock
+type InMemoryOutboxRepository struct {
+	entries []*OutboxEntry // oldest first
+	seq     int64
+	mu      sync.Mutex
+}
+
+func NewInMemoryOutboxRepository() *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{}
+}
+
+// record snapshots evts into the outbox. It fails before anything is appended,
+// so callers can record first and then apply their own write. A nil outbox
+// drops the events.
+func (r *InMemoryOutboxRepository) record(evts []events.Event) error {
+	if r == nil || len(evts) == 0 {
+		return nil
+	}
+	snapshots := make([]events.Event, len(evts))
+	for i, event := range evts {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("encode %s event: %w", event.Type, err)
+		}
+		event.Data = json.RawMessage(data)
+		snapshots[i] = event
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range snapshots {
+		r.seq++
+		r.entries = append(r.entries, &OutboxEntry{Seq: r.seq, Event: event, NextAttemptAt: event.OccurredAt})
+	}
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) Pending(ctx context.Context, now time.Time, limit int) ([]*OutboxEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*OutboxEntry
+	for _, entry := range r.entries {
+		if len(due) == limit {
+			break
+		}
+		if !entry.NextAttemptAt.After(now) {
+			copied := *entry
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *InMemoryOutboxRepository) MarkPublished(ctx context.Context, seq int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(seq)
+	if err != nil {
+		return err
+	}
+	r.entries = slices.Delete(r.entries, i, i+1)
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) MarkFailed(ctx context.Context, seq int64, reason string, next time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(seq)
+	if err != nil {
+		return err
+	}
+	entry := r.entries[i]
+	entry.Attempts++
+	entry.LastError = reason
+	entry.NextAttemptAt = next
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) find(seq int64) (int, error) {
+	i, found := slices.BinarySearchFunc(r.entries, seq, func(e *OutboxEntry, seq int64) int {
+		return cmp.Compare(e.Seq, seq)
+	})
+	if !found {
+		return 0, fmt.Errorf("outbox entry %d %w", seq, ErrNotFound)
+	}
+	return i, nil
+}
+
+func (r *InMemoryOutboxRepository) Ping(ctx context.Context) error {
+	return nil
+}
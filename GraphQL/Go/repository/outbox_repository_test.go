@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestInMemoryOutboxRepository(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewInMemoryOutboxRepository()
+	if err := repo.record([]events.Event{
+		{ID: "e1", Type: events.UserCreated, OccurredAt: start},
+		{ID: "e2", Type: events.PostCreated, OccurredAt: start},
+		{ID: "e3", Type: events.PostDeleted, OccurredAt: start.Add(time.Minute)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(now time.Time, limit int) []string {
+		t.Helper()
+		entries, err := repo.Pending(ctx, now, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Event.ID)
+		}
+		return out
+	}
+	equal := func(got []string, want ...string) bool { return slices.Equal(got, want) }
+
+	if got := ids(start, 10); !equal(got, "e1", "e2") {
+		t.Errorf("pending at start = %v, want e1 e2 (e3 is not due)", got)
+	}
+	if got := ids(start.Add(time.Minute), 2); !equal(got, "e1", "e2") {
+		t.Errorf("pending with limit 2 = %v, want the oldest two", got)
+	}
+
+	// A failed entry is held back until its next attempt
+	if err := repo.MarkFailed(ctx, 1, "broker down", start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(start.Add(time.Minute), 10); !equal(got, "e2", "e3") {
+		t.Errorf("pending after failure = %v, want e2 e3", got)
+	}
+	entries, _ := repo.Pending(ctx, start.Add(time.Hour), 10)
+	if entries[0].Event.ID != "e1" || entries[0].Attempts != 1 || entries[0].LastError != "broker down" {
+		t.Errorf("failed entry = %+v, want one attempt recorded", entries[0])
+	}
+
+	// A published entry is gone for good
+	if err := repo.MarkPublished(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(start.Add(time.Hour), 10); !equal(got, "e1", "e3") {
+		t.Errorf("pending after publish = %v, want e1 e3", got)
+	}
+
+	for name, err := range map[string]error{
+		"MarkPublished": repo.MarkPublished(ctx, 2),
+		"MarkFailed":    repo.MarkFailed(ctx, 99, "", start),
+	} {
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s of a missing entry = %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func TestInMemoryOutboxRepository_PendingReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOutboxRepository()
+	if err := repo.record([]events.Event{{ID: "e1", Type: events.UserCreated}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := repo.Pending(ctx, time.Now(), 10)
+	entries[0].Attempts = 99
+
+	entries, _ = repo.Pending(ctx, time.Now(), 10)
+	if entries[0].Attempts != 0 {
+		t.Error("changing a returned entry changed the stored one")
+	}
+}
+
+func TestInMemoryUserRepository_RecordsEventsWithWrite(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewInMemoryOutboxRepository()
+	users := NewInMemoryUserRepository()
+	users.UseOutbox(outbox)
+
+	acme := tenant.WithID(ctx, "acme")
+	if err := users.Create(acme, &model.User{ID: "1", Email: "a@example.com"}, events.Event{ID: "e1", Type: events.UserCreated}); err != nil {
+		t.Fatal(err)
+	}
+	// A rejected write records nothing
+	if err := users.Create(acme, &model.User{ID: "1", Email: "b@example.com"}, events.Event{ID: "e2", Type: events.UserCreated}); err == nil {
+		t.Fatal("duplicate user was created")
+	}
+
+	entries, _ := outbox.Pending(ctx, time.Now(), 10)
+	if len(entries) != 1 || entries[0].Event.ID != "e1" {
+		t.Errorf("outbox = %+v, want only e1", entries)
+	}
+}
@@ -3,82 +3,407 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
 )
 
+// PostRepository defines the interface for post data operations.
+// Every operation is scoped to the tenant carried in ctx.
 type PostRepository interface {
 	GetAll(ctx context.Context) ([]*model.Post, error)
 	GetByID(ctx context.Context, id string) (*model.Post, error)
 	GetByAuthorID(ctx context.Context, authorID string) ([]*model.Post, error)
-	Create(ctx context.Context, post *model.Post) error
-	Delete(ctx context.Context, id string) (*model.Post, error)
+	// Create and Delete record evts in the outbox in the same step as the write,
+	// as Modify does with the events its mutate returns
+	Create(ctx context.Context, post *model.Post, evts ...events.Event) error
+	// Update replaces the stored post unconditionally; imports use it to overwrite
+	Update(ctx context.Context, post *model.Post) error
+	// Modify reads the post, lets mutate change a copy and stores the copy, all
+	// in one step so concurrent changes are not lost. mutate sees the current
+	// post and returns the events to record in the outbox with the write, or an
+	// error, which aborts the write and is returned; a SQL backend runs it in a
+	// transaction holding the row lock.
+	Modify(ctx context.Context, id string, mutate func(post *model.Post) ([]events.Event, error)) (*model.Post, error)
+	Delete(ctx context.Context, id string, evts ...events.Event) (*model.Post, error)
+	// GetDueScheduled returns SCHEDULED posts whose publishAt is not after now
+	GetDueScheduled(ctx context.Context, now time.Time) ([]*model.Post, error)
+	// GetPublishedByAuthors returns up to limit PUBLISHED posts by the given authors,
+	// newest first, that come after the position (nil starts at the newest)
+	GetPublishedByAuthors(ctx context.Context, authorIDs []string, after *FeedPosition, limit int) ([]*model.Post, error)
+
+	// Aggregates are computed by the store so a SQL backend can answer them
+	// with COUNT ... GROUP BY instead of shipping every row to the service
+	Count(ctx context.Context) (int, error)
+	// CountByAuthor counts an author's posts, only those in status when it is not nil
+	CountByAuthor(ctx context.Context, authorID string, status *model.PostStatus) (int, error)
+	// CountPerDay counts posts created in [from, to) grouped by UTC calendar day;
+	// days without posts are omitted
+	CountPerDay(ctx context.Context, from, to time.Time) ([]DayCount, error)
+	// TopAuthors returns the limit authors with the most posts, ties broken by author ID
+	TopAuthors(ctx context.Context, limit int) ([]AuthorCount, error)
+
+	// Tenants lists every tenant holding posts; background jobs use it to visit each workspace
+	Tenants(ctx context.Context) ([]string, error)
+	Ping(ctx context.Context) error
+}
+
+// FeedPosition is a point in the newest-first ordering of published posts.
+// Ties on PublishedAt are broken by ID so the order is total and cursors stay stable.
+type FeedPosition struct {
+	PublishedAt time.Time
+	ID          string
+}
+
+// Before reports whether post sorts after p in the newest-first ordering
+func (p FeedPosition) Before(post *model.Post) bool {
+	if !post.PublishedAt.Equal(p.PublishedAt) {
+		return post.PublishedAt.Before(p.PublishedAt)
+	}
+	return post.ID < p.ID
+}
+
+// DayCount is the number of posts created on one UTC calendar day
+type DayCount struct {
+	Day   time.Time // midnight UTC
+	Count int
+}
+
+// AuthorCount is the number of posts written by one author
+type AuthorCount struct {
+	AuthorID string
+	Count    int
 }
 
 type InMemoryPostRepository struct {
-	posts []*model.Post
-	mu    sync.RWMutex
+	posts    map[string][]*model.Post            // keyed by tenant ID
+	byAuthor map[string]map[string][]*model.Post // tenant ID -> author ID -> posts
+	outbox   *InMemoryOutboxRepository
+	mu       sync.RWMutex
 }
 
 func NewInMemoryPostRepository() *InMemoryPostRepository {
 	return &InMemoryPostRepository{
-		posts: []*model.Post{},
+		posts:    map[string][]*model.Post{},
+		byAuthor: map[string]map[string][]*model.Post{},
 	}
 }
 
 func (r *InMemoryPostRepository) GetAll(ctx context.Context) ([]*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	posts := make([]*model.Post, len(r.posts))
-	copy(posts, r.posts)
+	posts := make([]*model.Post, len(r.posts[tenantID]))
+	copy(posts, r.posts[tenantID])
 	return posts, nil
 }
 
 func (r *InMemoryPostRepository) GetByID(ctx context.Context, id string) (*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, post := range r.posts {
+	for _, post := range r.posts[tenantID] {
 		if post.ID == id {
 			return post, nil
 		}
 	}
-	return nil, fmt.Errorf("post with id %s not found", id)
+	return nil, fmt.Errorf("post with id %s %w", id, ErrNotFound)
 }
 
 func (r *InMemoryPostRepository) GetByAuthorID(ctx context.Context, authorID string) ([]*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	var authorPosts []*model.Post
-	for _, post := range r.posts {
-		if post.Author.ID == authorID {
-			authorPosts = append(authorPosts, post)
-		}
-	}
+	authorPosts := make([]*model.Post, len(r.byAuthor[tenantID][authorID]))
+	copy(authorPosts, r.byAuthor[tenantID][authorID])
 	return authorPosts, nil
 }
 
-func (r *InMemoryPostRepository) Create(ctx context.Context, post *model.Post) error {
+// UseOutbox makes writes record their events in outbox; without one they are dropped
+func (r *InMemoryPostRepository) UseOutbox(outbox *InMemoryOutboxRepository) {
+	r.outbox = outbox
+}
+
+func (r *InMemoryPostRepository) Create(ctx context.Context, post *model.Post, evts ...events.Event) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.posts = append(r.posts, post)
+	if err := r.outbox.record(evts); err != nil {
+		return err
+	}
+
+	r.posts[tenantID] = append(r.posts[tenantID], post)
+	if r.byAuthor[tenantID] == nil {
+		r.byAuthor[tenantID] = map[string][]*model.Post{}
+	}
+	r.byAuthor[tenantID][post.AuthorID] = append(r.byAuthor[tenantID][post.AuthorID], post)
 	return nil
 }
 
-func (r *InMemoryPostRepository) Delete(ctx context.Context, id string) (*model.Post, error) {
+// Update replaces the stored post with the same ID. Callers pass a modified copy
+// rather than mutating a post other goroutines may be reading.
+func (r *InMemoryPostRepository) Update(ctx context.Context, post *model.Post) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	for i, post := range r.posts {
+	if i := r.index(tenantID, post.ID); i >= 0 {
+		r.replace(tenantID, i, post)
+		return nil
+	}
+	return fmt.Errorf("post with id %s %w", post.ID, ErrNotFound)
+}
+
+// Modify holds the write lock while mutate runs, so mutate must not call back
+// into this repository
+func (r *InMemoryPostRepository) Modify(ctx context.Context, id string, mutate func(post *model.Post) ([]events.Event, error)) (*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(tenantID, id)
+	if i < 0 {
+		return nil, fmt.Errorf("post with id %s %w", id, ErrNotFound)
+	}
+	updated := *r.posts[tenantID][i]
+	evts, err := mutate(&updated)
+	if err != nil {
+		return nil, err
+	}
+	updated.ID = id
+	if err := r.outbox.record(evts); err != nil {
+		return nil, err
+	}
+	r.replace(tenantID, i, &updated)
+	return &updated, nil
+}
+
+// index finds a post's position in the tenant's list, or -1; callers hold r.mu
+func (r *InMemoryPostRepository) index(tenantID, id string) int {
+	return slices.IndexFunc(r.posts[tenantID], func(p *model.Post) bool { return p.ID == id })
+}
+
+// replace stores post at position i and keeps the author index in step,
+// moving the post when it was reassigned; callers hold r.mu
+func (r *InMemoryPostRepository) replace(tenantID string, i int, post *model.Post) {
+	p := r.posts[tenantID][i]
+	r.posts[tenantID][i] = post
+	authorPosts := r.byAuthor[tenantID][p.AuthorID]
+	if post.AuthorID == p.AuthorID {
+		authorPosts[slices.Index(authorPosts, p)] = post
+		return
+	}
+	r.byAuthor[tenantID][p.AuthorID] = slices.DeleteFunc(authorPosts, func(q *model.Post) bool { return q == p })
+	r.byAuthor[tenantID][post.AuthorID] = append(r.byAuthor[tenantID][post.AuthorID], post)
+}
+
+func (r *InMemoryPostRepository) Delete(ctx context.Context, id string, evts ...events.Event) (*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	posts := r.posts[tenantID]
+	for i, post := range posts {
 		if post.ID == id {
+			if err := r.outbox.record(evts); err != nil {
+				return nil, err
+			}
 			deleted := post
-			r.posts = append(r.posts[:i], r.posts[i+1:]...)
+			r.posts[tenantID] = append(posts[:i], posts[i+1:]...)
+			r.byAuthor[tenantID][post.AuthorID] = slices.DeleteFunc(r.byAuthor[tenantID][post.AuthorID], func(p *model.Post) bool {
+				return p == deleted
+			})
 			return deleted, nil
 		}
 	}
-	return nil, fmt.Errorf("post with id %s not found", id)
+	return nil, fmt.Errorf("post with id %s %w", id, ErrNotFound)
+}
+
+func (r *InMemoryPostRepository) GetDueScheduled(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var due []*model.Post
+	for _, post := range r.posts[tenantID] {
+		if post.Status == model.PostStatusScheduled && post.PublishAt != nil && !post.PublishAt.After(now) {
+			due = append(due, post)
+		}
+	}
+	return due, nil
+}
+
+// GetPublishedByAuthors only visits the posts of the requested authors, so a
+// feed costs the same however many posts the tenant holds overall
+func (r *InMemoryPostRepository) GetPublishedByAuthors(ctx context.Context, authorIDs []string, after *FeedPosition, limit int) ([]*model.Post, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var page []*model.Post
+	for _, authorID := range authorIDs {
+		for _, post := range r.byAuthor[tenantID][authorID] {
+			if post.Status != model.PostStatusPublished || post.PublishedAt == nil {
+				continue
+			}
+			if after != nil && !after.Before(post) {
+				continue
+			}
+			page = append(page, post)
+		}
+	}
+
+	slices.SortFunc(page, func(a, b *model.Post) int {
+		if c := b.PublishedAt.Compare(*a.PublishedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	return page[:min(limit, len(page))], nil
+}
+
+func (r *InMemoryPostRepository) Count(ctx context.Context) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.posts[tenantID]), nil
+}
+
+func (r *InMemoryPostRepository) CountByAuthor(ctx context.Context, authorID string, status *model.PostStatus) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	authorPosts := r.byAuthor[tenantID][authorID]
+	if status == nil {
+		return len(authorPosts), nil
+	}
+	n := 0
+	for _, post := range authorPosts {
+		if post.Status == *status {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *InMemoryPostRepository) CountPerDay(ctx context.Context, from, to time.Time) ([]DayCount, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	perDay := map[time.Time]int{}
+	for _, post := range r.posts[tenantID] {
+		if post.CreatedAt.Before(from) || !post.CreatedAt.Before(to) {
+			continue
+		}
+		perDay[post.CreatedAt.UTC().Truncate(24*time.Hour)]++
+	}
+
+	counts := make([]DayCount, 0, len(perDay))
+	for day, n := range perDay {
+		counts = append(counts, DayCount{Day: day, Count: n})
+	}
+	slices.SortFunc(counts, func(a, b DayCount) int { return a.Day.Compare(b.Day) })
+	return counts, nil
+}
+
+func (r *InMemoryPostRepository) TopAuthors(ctx context.Context, limit int) ([]AuthorCount, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make([]AuthorCount, 0, len(r.byAuthor[tenantID]))
+	for authorID, posts := range r.byAuthor[tenantID] {
+		if len(posts) > 0 {
+			counts = append(counts, AuthorCount{AuthorID: authorID, Count: len(posts)})
+		}
+	}
+	slices.SortFunc(counts, func(a, b AuthorCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.AuthorID, b.AuthorID)
+	})
+	return counts[:min(limit, len(counts))], nil
+}
+
+func (r *InMemoryPostRepository) Tenants(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]string, 0, len(r.posts))
+	for id := range r.posts {
+		tenants = append(tenants, id)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// Ping reports backend health for readiness probes; an in-memory store is always reachable
+func (r *InMemoryPostRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
 }
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestInMemoryPostRepository_CrossTenantAccess(t *testing.T) {
+	repo := NewInMemoryPostRepository()
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+
+	if err := repo.Create(acme, &model.Post{ID: "p1", Title: "Anvils", AuthorID: "7"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, err := repo.GetByID(globex, "p1"); err == nil {
+		t.Fatal("globex read acme's post by ID")
+	}
+	if posts, _ := repo.GetByAuthorID(globex, "7"); len(posts) != 0 {
+		t.Fatalf("globex listed %d of acme's posts by author ID", len(posts))
+	}
+	if _, err := repo.Delete(globex, "p1"); err == nil {
+		t.Fatal("globex deleted acme's post by ID")
+	}
+	if posts, _ := repo.GetAll(globex); len(posts) != 0 {
+		t.Fatalf("globex listed %d posts, want 0", len(posts))
+	}
+
+	if _, err := repo.GetByID(acme, "p1"); err != nil {
+		t.Fatalf("acme lost its own post: %v", err)
+	}
+}
+
+func TestInMemoryPostRepository_UpdateReassignsAuthor(t *testing.T) {
+	repo := NewInMemoryPostRepository()
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	post := &model.Post{ID: "p1", Title: "Anvils", AuthorID: "7"}
+	if err := repo.Create(ctx, post); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	moved := *post
+	moved.AuthorID = "8"
+	if err := repo.Update(ctx, &moved); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if posts, _ := repo.GetByAuthorID(ctx, "7"); len(posts) != 0 {
+		t.Fatalf("old author still lists %d posts", len(posts))
+	}
+	if posts, _ := repo.GetByAuthorID(ctx, "8"); len(posts) != 1 || posts[0] != &moved {
+		t.Fatalf("new author lists %v, want the reassigned post", posts)
+	}
+}
+
+func TestInMemoryPostRepository_ModifyLosesNoUpdates(t *testing.T) {
+	repo := NewInMemoryPostRepository()
+	ctx := tenant.WithID(context.Background(), "acme")
+	if err := repo.Create(ctx, &model.Post{ID: "p1", Title: "", AuthorID: "7"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			if _, err := repo.Modify(ctx, "p1", func(p *model.Post) ([]events.Event, error) {
+				p.Title += "x"
+				return nil, nil
+			}); err != nil {
+				t.Errorf("modify: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if post, _ := repo.GetByID(ctx, "p1"); len(post.Title) != 50 {
+		t.Fatalf("title has %d of 50 appends", len(post.Title))
+	}
+
+	// An error from mutate leaves the post alone
+	abort := errors.New("abort")
+	if _, err := repo.Modify(ctx, "p1", func(p *model.Post) ([]events.Event, error) {
+		p.Title = "changed"
+		return nil, abort
+	}); !errors.Is(err, abort) {
+		t.Fatalf("modify = %v, want the mutate error", err)
+	}
+	if post, _ := repo.GetByID(ctx, "p1"); post.Title == "changed" {
+		t.Fatal("aborted modify was stored")
+	}
+	if _, err := repo.Modify(ctx, "missing", func(*model.Post) ([]events.Event, error) { return nil, nil }); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("modify of a missing post = %v, want ErrNotFound", err)
+	}
+}
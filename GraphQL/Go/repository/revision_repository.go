@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// RevisionRepository stores the immutable edit history of posts.
+// Every operation is scoped to the tenant carried in ctx.
+type RevisionRepository interface {
+	// Append stores the next revision of a post; rev.Number must follow the latest one
+	Append(ctx context.Context, postID string, rev *model.PostRevision) error
+	// ListByPost returns up to limit revisions numbered after the given one, oldest first
+	ListByPost(ctx context.Context, postID string, after int32, limit int) ([]*model.PostRevision, error)
+	Get(ctx context.Context, postID string, number int32) (*model.PostRevision, error)
+	// Count returns the number of revisions, which is also the latest revision number
+	Count(ctx context.Context, postID string) (int, error)
+	// CountByEditor returns the number of revisions, across all posts, that name editorID
+	CountByEditor(ctx context.Context, editorID string) (int, error)
+	// DeleteByPost removes a post's whole history; deleting a post with none is not an error
+	DeleteByPost(ctx context.Context, postID string) error
+	Ping(ctx context.Context) error
+}
+
+type InMemoryRevisionRepository struct {
+	revisions map[string]map[string][]*model.PostRevision // tenant ID -> post ID -> revisions
+	mu        sync.RWMutex
+}
+
+func NewInMemoryRevisionRepository() *InMemoryRevisionRepository {
+	return &InMemoryRevisionRepository{
+		revisions: map[string]map[string][]*model.PostRevision{},
+	}
+}
+
+func (r *InMemoryRevisionRepository) Append(ctx context.Context, postID string, rev *model.PostRevision) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.revisions[tenantID] == nil {
+		r.revisions[tenantID] = map[string][]*model.PostRevision{}
+	}
+	history := r.revisions[tenantID][postID]
+
+	// Numbers are dense, so a concurrent writer that read a stale count loses here
+	if want := int32(len(history) + 1); rev.Number != want {
+		return fmt.Errorf("revision %d of post %s %w", rev.Number, postID, ErrAlreadyExists)
+	}
+
+	r.revisions[tenantID][postID] = append(history, rev)
+	return nil
+}
+
+func (r *InMemoryRevisionRepository) ListByPost(ctx context.Context, postID string, after int32, limit int) ([]*model.PostRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Revision n lives at index n-1
+	history := r.revisions[tenantID][postID]
+	start := min(max(int(after), 0), len(history))
+	end := min(start+limit, len(history))
+
+	revisions := make([]*model.PostRevision, end-start)
+	copy(revisions, history[start:end])
+	return revisions, nil
+}
+
+func (r *InMemoryRevisionRepository) Get(ctx context.Context, postID string, number int32) (*model.PostRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.revisions[tenantID][postID]
+	if number < 1 || int(number) > len(history) {
+		return nil, fmt.Errorf("revision %d of post %s %w", number, postID, ErrNotFound)
+	}
+	return history[number-1], nil
+}
+
+func (r *InMemoryRevisionRepository) Count(ctx context.Context, postID string) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.revisions[tenantID][postID]), nil
+}
+
+func (r *InMemoryRevisionRepository) CountByEditor(ctx context.Context, editorID string) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, revs := range r.revisions[tenantID] {
+		for _, rev := range revs {
+			if rev.EditorID == editorID {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func (r *InMemoryRevisionRepository) DeleteByPost(ctx context.Context, postID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.revisions[tenantID], postID)
+	return nil
+}
+
+// Ping reports backend health for readiness probes; an in-memory store is always reachable
+func (r *InMemoryRevisionRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
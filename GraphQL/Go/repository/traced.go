@@ -0,0 +1,505 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/Krushnal121/API-Hub/GraphQL/Go/repository")
+
+// startSpan opens a client span for a repository call tagged with the tenant
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if id, ok := tenant.FromContext(ctx); ok {
+		attrs = append(attrs, attribute.String("tenant.id", id))
+	}
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span before ending it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedUserRepository wraps a UserRepository with a span per call
+type tracedUserRepository struct {
+	next UserRepository
+}
+
+// NewTracedUserRepository decorates any UserRepository backend with OpenTelemetry spans
+func NewTracedUserRepository(next UserRepository) UserRepository {
+	return &tracedUserRepository{next: next}
+}
+
+func (r *tracedUserRepository) GetAll(ctx context.Context) ([]*model.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetAll")
+	users, err := r.next.GetAll(ctx)
+	endSpan(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByID", attribute.String("user.id", id))
+	user, err := r.next.GetByID(ctx, id)
+	endSpan(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	// The address itself is personal data, so it stays off the span
+	ctx, span := startSpan(ctx, "UserRepository.GetByEmail")
+	user, err := r.next.GetByEmail(ctx, email)
+	endSpan(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) Create(ctx context.Context, user *model.User, evts ...events.Event) error {
+	ctx, span := startSpan(ctx, "UserRepository.Create", attribute.String("user.id", user.ID))
+	err := r.next.Create(ctx, user, evts...)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Update(ctx context.Context, user *model.User) error {
+	ctx, span := startSpan(ctx, "UserRepository.Update", attribute.String("user.id", user.ID))
+	err := r.next.Update(ctx, user)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) WithUser(ctx context.Context, id string, fn func(user *model.User) error) error {
+	ctx, span := startSpan(ctx, "UserRepository.WithUser", attribute.String("user.id", id))
+	err := r.next.WithUser(ctx, id, fn)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Delete(ctx context.Context, id string, check func() error) error {
+	ctx, span := startSpan(ctx, "UserRepository.Delete", attribute.String("user.id", id))
+	err := r.next.Delete(ctx, id, check)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Count(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "UserRepository.Count")
+	n, err := r.next.Count(ctx)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedUserRepository) Tenants(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "UserRepository.Tenants")
+	tenants, err := r.next.Tenants(ctx)
+	endSpan(span, err)
+	return tenants, err
+}
+
+func (r *tracedUserRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedPostRepository wraps a PostRepository with a span per call
+type tracedPostRepository struct {
+	next PostRepository
+}
+
+// NewTracedPostRepository decorates any PostRepository backend with OpenTelemetry spans
+func NewTracedPostRepository(next PostRepository) PostRepository {
+	return &tracedPostRepository{next: next}
+}
+
+func (r *tracedPostRepository) GetAll(ctx context.Context) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.GetAll")
+	posts, err := r.next.GetAll(ctx)
+	endSpan(span, err)
+	return posts, err
+}
+
+func (r *tracedPostRepository) GetByID(ctx context.Context, id string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.GetByID", attribute.String("post.id", id))
+	post, err := r.next.GetByID(ctx, id)
+	endSpan(span, err)
+	return post, err
+}
+
+func (r *tracedPostRepository) GetByAuthorID(ctx context.Context, authorID string) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.GetByAuthorID", attribute.String("author.id", authorID))
+	posts, err := r.next.GetByAuthorID(ctx, authorID)
+	endSpan(span, err)
+	return posts, err
+}
+
+func (r *tracedPostRepository) Create(ctx context.Context, post *model.Post, evts ...events.Event) error {
+	ctx, span := startSpan(ctx, "PostRepository.Create", attribute.String("post.id", post.ID))
+	err := r.next.Create(ctx, post, evts...)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedPostRepository) Update(ctx context.Context, post *model.Post) error {
+	ctx, span := startSpan(ctx, "PostRepository.Update", attribute.String("post.id", post.ID))
+	err := r.next.Update(ctx, post)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedPostRepository) Modify(ctx context.Context, id string, mutate func(post *model.Post) ([]events.Event, error)) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.Modify", attribute.String("post.id", id))
+	post, err := r.next.Modify(ctx, id, mutate)
+	endSpan(span, err)
+	return post, err
+}
+
+func (r *tracedPostRepository) Delete(ctx context.Context, id string, evts ...events.Event) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.Delete", attribute.String("post.id", id))
+	post, err := r.next.Delete(ctx, id, evts...)
+	endSpan(span, err)
+	return post, err
+}
+
+func (r *tracedPostRepository) GetDueScheduled(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.GetDueScheduled")
+	posts, err := r.next.GetDueScheduled(ctx, now)
+	endSpan(span, err)
+	return posts, err
+}
+
+func (r *tracedPostRepository) GetPublishedByAuthors(ctx context.Context, authorIDs []string, after *FeedPosition, limit int) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostRepository.GetPublishedByAuthors", attribute.Int("author.count", len(authorIDs)))
+	posts, err := r.next.GetPublishedByAuthors(ctx, authorIDs, after, limit)
+	endSpan(span, err)
+	return posts, err
+}
+
+func (r *tracedPostRepository) Count(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "PostRepository.Count")
+	n, err := r.next.Count(ctx)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedPostRepository) CountByAuthor(ctx context.Context, authorID string, status *model.PostStatus) (int, error) {
+	ctx, span := startSpan(ctx, "PostRepository.CountByAuthor", attribute.String("author.id", authorID))
+	n, err := r.next.CountByAuthor(ctx, authorID, status)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedPostRepository) CountPerDay(ctx context.Context, from, to time.Time) ([]DayCount, error) {
+	ctx, span := startSpan(ctx, "PostRepository.CountPerDay",
+		attribute.String("range.from", from.Format(time.RFC3339)),
+		attribute.String("range.to", to.Format(time.RFC3339)),
+	)
+	counts, err := r.next.CountPerDay(ctx, from, to)
+	endSpan(span, err)
+	return counts, err
+}
+
+func (r *tracedPostRepository) TopAuthors(ctx context.Context, limit int) ([]AuthorCount, error) {
+	ctx, span := startSpan(ctx, "PostRepository.TopAuthors", attribute.Int("limit", limit))
+	counts, err := r.next.TopAuthors(ctx, limit)
+	endSpan(span, err)
+	return counts, err
+}
+
+func (r *tracedPostRepository) Tenants(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "PostRepository.Tenants")
+	tenants, err := r.next.Tenants(ctx)
+	endSpan(span, err)
+	return tenants, err
+}
+
+func (r *tracedPostRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedRevisionRepository wraps a RevisionRepository with a span per call
+type tracedRevisionRepository struct {
+	next RevisionRepository
+}
+
+// NewTracedRevisionRepository decorates any RevisionRepository backend with OpenTelemetry spans
+func NewTracedRevisionRepository(next RevisionRepository) RevisionRepository {
+	return &tracedRevisionRepository{next: next}
+}
+
+func (r *tracedRevisionRepository) Append(ctx context.Context, postID string, rev *model.PostRevision) error {
+	ctx, span := startSpan(ctx, "RevisionRepository.Append",
+		attribute.String("post.id", postID),
+		attribute.Int("revision.number", int(rev.Number)),
+	)
+	err := r.next.Append(ctx, postID, rev)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedRevisionRepository) ListByPost(ctx context.Context, postID string, after int32, limit int) ([]*model.PostRevision, error) {
+	ctx, span := startSpan(ctx, "RevisionRepository.ListByPost", attribute.String("post.id", postID))
+	revisions, err := r.next.ListByPost(ctx, postID, after, limit)
+	endSpan(span, err)
+	return revisions, err
+}
+
+func (r *tracedRevisionRepository) Get(ctx context.Context, postID string, number int32) (*model.PostRevision, error) {
+	ctx, span := startSpan(ctx, "RevisionRepository.Get",
+		attribute.String("post.id", postID),
+		attribute.Int("revision.number", int(number)),
+	)
+	rev, err := r.next.Get(ctx, postID, number)
+	endSpan(span, err)
+	return rev, err
+}
+
+func (r *tracedRevisionRepository) Count(ctx context.Context, postID string) (int, error) {
+	ctx, span := startSpan(ctx, "RevisionRepository.Count", attribute.String("post.id", postID))
+	n, err := r.next.Count(ctx, postID)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedRevisionRepository) CountByEditor(ctx context.Context, editorID string) (int, error) {
+	ctx, span := startSpan(ctx, "RevisionRepository.CountByEditor", attribute.String("user.id", editorID))
+	n, err := r.next.CountByEditor(ctx, editorID)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedRevisionRepository) DeleteByPost(ctx context.Context, postID string) error {
+	ctx, span := startSpan(ctx, "RevisionRepository.DeleteByPost", attribute.String("post.id", postID))
+	err := r.next.DeleteByPost(ctx, postID)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedRevisionRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedFollowRepository wraps a FollowRepository with a span per call
+type tracedFollowRepository struct {
+	next FollowRepository
+}
+
+// NewTracedFollowRepository decorates any FollowRepository backend with OpenTelemetry spans
+func NewTracedFollowRepository(next FollowRepository) FollowRepository {
+	return &tracedFollowRepository{next: next}
+}
+
+func (r *tracedFollowRepository) Follow(ctx context.Context, followerID, followeeID string) error {
+	ctx, span := startSpan(ctx, "FollowRepository.Follow",
+		attribute.String("follower.id", followerID),
+		attribute.String("followee.id", followeeID),
+	)
+	err := r.next.Follow(ctx, followerID, followeeID)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedFollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	ctx, span := startSpan(ctx, "FollowRepository.Unfollow",
+		attribute.String("follower.id", followerID),
+		attribute.String("followee.id", followeeID),
+	)
+	err := r.next.Unfollow(ctx, followerID, followeeID)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedFollowRepository) ListFollowers(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.ListFollowers", attribute.String("user.id", userID))
+	edges, err := r.next.ListFollowers(ctx, userID, before, limit)
+	endSpan(span, err)
+	return edges, err
+}
+
+func (r *tracedFollowRepository) ListFollowing(ctx context.Context, userID string, before int64, limit int) ([]*Follow, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.ListFollowing", attribute.String("user.id", userID))
+	edges, err := r.next.ListFollowing(ctx, userID, before, limit)
+	endSpan(span, err)
+	return edges, err
+}
+
+func (r *tracedFollowRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.CountFollowers", attribute.String("user.id", userID))
+	n, err := r.next.CountFollowers(ctx, userID)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedFollowRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.CountFollowing", attribute.String("user.id", userID))
+	n, err := r.next.CountFollowing(ctx, userID)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracedFollowRepository) FollowingIDs(ctx context.Context, followerID string) ([]string, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.FollowingIDs", attribute.String("follower.id", followerID))
+	ids, err := r.next.FollowingIDs(ctx, followerID)
+	endSpan(span, err)
+	return ids, err
+}
+
+func (r *tracedFollowRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedWebhookRepository wraps a WebhookRepository with a span per call
+type tracedWebhookRepository struct {
+	next WebhookRepository
+}
+
+// NewTracedWebhookRepository decorates any WebhookRepository backend with OpenTelemetry spans
+func NewTracedWebhookRepository(next WebhookRepository) WebhookRepository {
+	return &tracedWebhookRepository{next: next}
+}
+
+func (r *tracedWebhookRepository) CreateWebhook(ctx context.Context, hook *Webhook) error {
+	ctx, span := startSpan(ctx, "WebhookRepository.CreateWebhook", attribute.String("webhook.id", hook.Subscription.ID))
+	err := r.next.CreateWebhook(ctx, hook)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedWebhookRepository) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.GetWebhook", attribute.String("webhook.id", id))
+	hook, err := r.next.GetWebhook(ctx, id)
+	endSpan(span, err)
+	return hook, err
+}
+
+func (r *tracedWebhookRepository) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ListWebhooks")
+	hooks, err := r.next.ListWebhooks(ctx)
+	span.SetAttributes(attribute.Int("webhook.count", len(hooks)))
+	endSpan(span, err)
+	return hooks, err
+}
+
+func (r *tracedWebhookRepository) DeleteWebhook(ctx context.Context, id string) (*Webhook, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.DeleteWebhook", attribute.String("webhook.id", id))
+	hook, err := r.next.DeleteWebhook(ctx, id)
+	endSpan(span, err)
+	return hook, err
+}
+
+func (r *tracedWebhookRepository) RecordDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	ctx, span := startSpan(ctx, "WebhookRepository.RecordDelivery",
+		attribute.String("webhook.id", delivery.SubscriptionID),
+		attribute.String("event.id", delivery.EventID),
+	)
+	err := r.next.RecordDelivery(ctx, delivery)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ListDeliveries", attribute.String("webhook.id", subscriptionID))
+	deliveries, err := r.next.ListDeliveries(ctx, subscriptionID, limit)
+	endSpan(span, err)
+	return deliveries, err
+}
+
+func (r *tracedWebhookRepository) AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error {
+	ctx, span := startSpan(ctx, "WebhookRepository.AddDeadLetter",
+		attribute.String("webhook.id", letter.SubscriptionID),
+		attribute.String("event.id", letter.EventID),
+	)
+	err := r.next.AddDeadLetter(ctx, letter)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedWebhookRepository) ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ListDeadLetters")
+	letters, err := r.next.ListDeadLetters(ctx)
+	endSpan(span, err)
+	return letters, err
+}
+
+func (r *tracedWebhookRepository) TakeDeadLetter(ctx context.Context, id string) (*model.WebhookDeadLetter, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.TakeDeadLetter", attribute.String("dead_letter.id", id))
+	letter, err := r.next.TakeDeadLetter(ctx, id)
+	endSpan(span, err)
+	return letter, err
+}
+
+func (r *tracedWebhookRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedOutboxRepository wraps an OutboxRepository with a span per call
+type tracedOutboxRepository struct {
+	next OutboxRepository
+}
+
+// NewTracedOutboxRepository decorates any OutboxRepository backend with OpenTelemetry spans
+func NewTracedOutboxRepository(next OutboxRepository) OutboxRepository {
+	return &tracedOutboxRepository{next: next}
+}
+
+func (r *tracedOutboxRepository) Pending(ctx context.Context, now time.Time, limit int) ([]*OutboxEntry, error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.Pending")
+	entries, err := r.next.Pending(ctx, now, limit)
+	span.SetAttributes(attribute.Int("outbox.count", len(entries)))
+	endSpan(span, err)
+	return entries, err
+}
+
+func (r *tracedOutboxRepository) MarkPublished(ctx context.Context, seq int64) error {
+	ctx, span := startSpan(ctx, "OutboxRepository.MarkPublished", attribute.Int64("outbox.seq", seq))
+	err := r.next.MarkPublished(ctx, seq)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedOutboxRepository) MarkFailed(ctx context.Context, seq int64, reason string, next time.Time) error {
+	ctx, span := startSpan(ctx, "OutboxRepository.MarkFailed", attribute.Int64("outbox.seq", seq))
+	err := r.next.MarkFailed(ctx, seq, reason, next)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedOutboxRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+
+// tracedVerificationRepository wraps a VerificationRepository with a span per call
+type tracedVerificationRepository struct {
+	next VerificationRepository
+}
+
+// NewTracedVerificationRepository decorates any VerificationRepository backend with OpenTelemetry spans
+func NewTracedVerificationRepository(next VerificationRepository) VerificationRepository {
+	return &tracedVerificationRepository{next: next}
+}
+
+func (r *tracedVerificationRepository) Create(ctx context.Context, v *EmailVerification) error {
+	ctx, span := startSpan(ctx, "VerificationRepository.Create", attribute.String("user.id", v.UserID))
+	err := r.next.Create(ctx, v)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracedVerificationRepository) Take(ctx context.Context, tokenHash string) (*EmailVerification, error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.Take")
+	v, err := r.next.Take(ctx, tokenHash)
+	endSpan(span, err)
+	return v, err
+}
+
+func (r *tracedVerificationRepository) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
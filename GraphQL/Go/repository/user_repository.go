@@ -3,92 +3,240 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
 )
 
-// UserRepository defines the interface for user data operations
+// UserRepository defines the interface for user data operations.
+// Every operation is scoped to the tenant carried in ctx.
 type UserRepository interface {
 	GetAll(ctx context.Context) ([]*model.User, error)
 	GetByID(ctx context.Context, id string) (*model.User, error)
-	Create(ctx context.Context, user *model.User) error
-	Delete(ctx context.Context, id string) error
+	// GetByEmail finds a user by email address, compared after NormalizeEmail
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	// Create stores user and records evts in the outbox in the same step
+	Create(ctx context.Context, user *model.User, evts ...events.Event) error
+	// Update replaces the stored user with the same ID.
+	// Create and Update return ErrAlreadyExists for an email another user has.
+	Update(ctx context.Context, user *model.User) error
+	// WithUser runs fn while the user cannot be deleted, so records naming
+	// them are never written for a user who is gone
+	WithUser(ctx context.Context, id string, fn func(user *model.User) error) error
+	// Delete removes the user once check passes; check runs with WithUser
+	// shut out, so nothing can start naming the user until the delete is done
+	Delete(ctx context.Context, id string, check func() error) error
+	Count(ctx context.Context) (int, error)
+	// Tenants lists every tenant holding users
+	Tenants(ctx context.Context) ([]string, error)
+	Ping(ctx context.Context) error
 }
 
 // InMemoryUserRepository is a fake repository for demonstration
 // In production, this would be a PostgresUserRepository, MongoUserRepository, etc.
 type InMemoryUserRepository struct {
-	users []*model.User
-	mu    sync.RWMutex // Thread-safe for concurrent GraphQL resolvers
+	users   map[string][]*model.User     // keyed by tenant ID
+	byEmail map[string]map[string]string // tenant ID -> normalized email -> user ID
+	outbox  *InMemoryOutboxRepository
+	mu      sync.RWMutex // Thread-safe for concurrent GraphQL resolvers
 }
 
-// NewInMemoryUserRepository creates a new repository with sample data
+// NewInMemoryUserRepository creates an empty repository; sample data is loaded by the seed package
 func NewInMemoryUserRepository() *InMemoryUserRepository {
 	return &InMemoryUserRepository{
-		users: []*model.User{
-			{
-				ID:    "1",
-				Name:  "Alice Johnson",
-				Email: "alice@example.com",
-				Posts: []*model.Post{},
-			},
-			{
-				ID:    "2",
-				Name:  "Bob Smith",
-				Email: "bob@example.com",
-				Posts: []*model.Post{},
-			},
-		},
+		users:   map[string][]*model.User{},
+		byEmail: map[string]map[string]string{},
 	}
 }
 
+// NormalizeEmail is the form email addresses are compared in, so that
+// "Ada@Example.com " and "ada@example.com" belong to the same user
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func (r *InMemoryUserRepository) GetAll(ctx context.Context) ([]*model.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	// Return a copy to prevent external modification
-	users := make([]*model.User, len(r.users))
-	copy(users, r.users)
+	users := make([]*model.User, len(r.users[tenantID]))
+	copy(users, r.users[tenantID])
 	return users, nil
 }
 
 func (r *InMemoryUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, user := range r.users {
+	for _, user := range r.users[tenantID] {
 		if user.ID == id {
 			return user, nil
 		}
 	}
-	return nil, fmt.Errorf("user with id %s not found", id)
+	return nil, fmt.Errorf("user with id %s %w", id, ErrNotFound)
+}
+
+func (r *InMemoryUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id, ok := r.byEmail[tenantID][NormalizeEmail(email)]; ok {
+		for _, user := range r.users[tenantID] {
+			if user.ID == id {
+				return user, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("user with email %s %w", email, ErrNotFound)
 }
 
-func (r *InMemoryUserRepository) Create(ctx context.Context, user *model.User) error {
+// UseOutbox makes writes record their events in outbox; without one they are dropped
+func (r *InMemoryUserRepository) UseOutbox(outbox *InMemoryOutboxRepository) {
+	r.outbox = outbox
+}
+
+func (r *InMemoryUserRepository) Create(ctx context.Context, user *model.User, evts ...events.Event) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Check for duplicate IDs
-	for _, u := range r.users {
+	// Check for duplicate IDs and emails
+	for _, u := range r.users[tenantID] {
 		if u.ID == user.ID {
-			return fmt.Errorf("user with id %s already exists", user.ID)
+			return fmt.Errorf("user with id %s %w", user.ID, ErrAlreadyExists)
 		}
 	}
+	email := NormalizeEmail(user.Email)
+	if _, taken := r.byEmail[tenantID][email]; taken {
+		return fmt.Errorf("user with email %s %w", user.Email, ErrAlreadyExists)
+	}
+	if err := r.outbox.record(evts); err != nil {
+		return err
+	}
 
-	r.users = append(r.users, user)
+	r.users[tenantID] = append(r.users[tenantID], user)
+	if r.byEmail[tenantID] == nil {
+		r.byEmail[tenantID] = map[string]string{}
+	}
+	r.byEmail[tenantID][email] = user.ID
 	return nil
 }
 
-func (r *InMemoryUserRepository) Delete(ctx context.Context, id string) error {
+func (r *InMemoryUserRepository) Update(ctx context.Context, user *model.User) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.users[tenantID] {
+		if u.ID == user.ID {
+			email := NormalizeEmail(user.Email)
+			if owner, taken := r.byEmail[tenantID][email]; taken && owner != user.ID {
+				return fmt.Errorf("user with email %s %w", user.Email, ErrAlreadyExists)
+			}
+			delete(r.byEmail[tenantID], NormalizeEmail(u.Email))
+			r.byEmail[tenantID][email] = user.ID
+			r.users[tenantID][i] = user
+			return nil
+		}
+	}
+	return fmt.Errorf("user with id %s %w", user.ID, ErrNotFound)
+}
+
+func (r *InMemoryUserRepository) WithUser(ctx context.Context, id string, fn func(user *model.User) error) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users[tenantID] {
+		if user.ID == id {
+			return fn(user)
+		}
+	}
+	return fmt.Errorf("user with id %s %w", id, ErrNotFound)
+}
+
+func (r *InMemoryUserRepository) Delete(ctx context.Context, id string, check func() error) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	for i, user := range r.users {
+	users := r.users[tenantID]
+	for i, user := range users {
 		if user.ID == id {
-			r.users = append(r.users[:i], r.users[i+1:]...)
+			if check != nil {
+				if err := check(); err != nil {
+					return err
+				}
+			}
+			r.users[tenantID] = append(users[:i], users[i+1:]...)
+			delete(r.byEmail[tenantID], NormalizeEmail(user.Email))
 			return nil
 		}
 	}
-	return fmt.Errorf("user with id %s not found", id)
+	return fmt.Errorf("user with id %s %w", id, ErrNotFound)
+}
+
+func (r *InMemoryUserRepository) Count(ctx context.Context) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.users[tenantID]), nil
+}
+
+func (r *InMemoryUserRepository) Tenants(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]string, 0, len(r.users))
+	for id := range r.users {
+		tenants = append(tenants, id)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// Ping reports backend health for readiness probes; an in-memory store is always reachable
+func (r *InMemoryUserRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestInMemoryUserRepository_CrossTenantAccess(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+	defaultTenant := tenant.WithID(context.Background(), tenant.Default)
+
+	if err := repo.Create(defaultTenant, &model.User{ID: "1", Name: "Alice Johnson"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.Create(acme, &model.User{ID: "42", Name: "Wile", Email: "wile@acme.test"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, err := repo.GetByID(globex, "42"); err == nil {
+		t.Fatal("globex read acme's user by ID")
+	}
+	if err := repo.Delete(globex, "42", nil); err == nil {
+		t.Fatal("globex deleted acme's user by ID")
+	}
+	if users, _ := repo.GetAll(globex); len(users) != 0 {
+		t.Fatalf("globex listed %d users, want 0", len(users))
+	}
+
+	// The default tenant is not a shared namespace
+	if _, err := repo.GetByID(acme, "1"); err == nil {
+		t.Fatal("acme read a default tenant user by ID")
+	}
+
+	if _, err := repo.GetByID(acme, "42"); err != nil {
+		t.Fatalf("acme lost its own user: %v", err)
+	}
+}
+
+func TestInMemoryUserRepository_SameIDInDifferentTenants(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	acme := tenant.WithID(context.Background(), "acme")
+	defaultTenant := tenant.WithID(context.Background(), tenant.Default)
+
+	if err := repo.Create(defaultTenant, &model.User{ID: "1", Name: "Alice Johnson"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// ID "1" is taken in the default tenant but must be free elsewhere
+	if err := repo.Create(acme, &model.User{ID: "1", Name: "Road Runner"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	user, err := repo.GetByID(defaultTenant, "1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if user.Name != "Alice Johnson" {
+		t.Fatalf("default tenant user was overwritten: got %q", user.Name)
+	}
+}
+
+func TestInMemoryUserRepository_RequiresTenant(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+
+	if _, err := repo.GetAll(context.Background()); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("GetAll without tenant: got %v, want ErrNoTenant", err)
+	}
+	if _, err := repo.GetByID(context.Background(), "1"); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("GetByID without tenant: got %v, want ErrNoTenant", err)
+	}
+}
+
+func TestInMemoryUserRepository_UniqueEmail(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	acme := tenant.WithID(context.Background(), "acme")
+
+	if err := repo.Create(ctx, &model.User{ID: "1", Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.Create(ctx, &model.User{ID: "2", Name: "Alias", Email: " Alice@Example.COM"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("create with differently-cased email: got %v, want ErrAlreadyExists", err)
+	}
+	// Emails are unique per tenant
+	if err := repo.Create(acme, &model.User{ID: "2", Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatalf("create in another tenant: %v", err)
+	}
+
+	user, err := repo.GetByEmail(ctx, "ALICE@example.com")
+	if err != nil || user.ID != "1" {
+		t.Fatalf("GetByEmail: got %v, %v; want user 1", user, err)
+	}
+
+	if err := repo.Create(ctx, &model.User{ID: "3", Name: "Bob", Email: "bob@example.com"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.Update(ctx, &model.User{ID: "3", Name: "Bob", Email: "Alice@example.com"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("update to a taken email: got %v, want ErrAlreadyExists", err)
+	}
+
+	// Changing an email frees the old one, as does deleting the user
+	if err := repo.Update(ctx, &model.User{ID: "1", Name: "Alice", Email: "alice@example.org"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := repo.GetByEmail(ctx, "alice@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("old email still indexed: %v", err)
+	}
+	if err := repo.Delete(ctx, "1", nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Create(ctx, &model.User{ID: "4", Name: "Alice", Email: "alice@example.org"}); err != nil {
+		t.Fatalf("email of a deleted user is still taken: %v", err)
+	}
+}
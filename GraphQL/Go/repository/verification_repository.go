@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// EmailVerification is a pending change of a user's email address. Only a
+// hash of the token is stored, so a leaked store cannot confirm changes.
+type EmailVerification struct {
+	UserID    string
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// VerificationRepository stores pending email changes, at most one per user.
+// Every operation is scoped to the tenant carried in ctx.
+type VerificationRepository interface {
+	// Create stores v, replacing any pending change for the same user
+	Create(ctx context.Context, v *EmailVerification) error
+	// Take removes and returns the change with the token hash; ErrNotFound if there is none.
+	// Expired changes are returned too, so the caller can say why they were refused.
+	Take(ctx context.Context, tokenHash string) (*EmailVerification, error)
+	Ping(ctx context.Context) error
+}
+
+type InMemoryVerificationRepository struct {
+	pending map[string]map[string]*EmailVerification // tenant ID -> token hash -> change
+	mu      sync.Mutex
+}
+
+func NewInMemoryVerificationRepository() *InMemoryVerificationRepository {
+	return &InMemoryVerificationRepository{
+		pending: map[string]map[string]*EmailVerification{},
+	}
+}
+
+func (r *InMemoryVerificationRepository) Create(ctx context.Context, v *EmailVerification) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pending[tenantID] == nil {
+		r.pending[tenantID] = map[string]*EmailVerification{}
+	}
+	// A new request supersedes the user's earlier ones
+	for hash, old := range r.pending[tenantID] {
+		if old.UserID == v.UserID {
+			delete(r.pending[tenantID], hash)
+		}
+	}
+	r.pending[tenantID][v.TokenHash] = v
+	return nil
+}
+
+func (r *InMemoryVerificationRepository) Take(ctx context.Context, tokenHash string) (*EmailVerification, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, exists := r.pending[tenantID][tokenHash]
+	if !exists {
+		return nil, fmt.Errorf("email verification %w", ErrNotFound)
+	}
+	delete(r.pending[tenantID], tokenHash)
+	return v, nil
+}
+
+func (r *InMemoryVerificationRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Webhook is a subscription together with the secret that signs its deliveries
+type Webhook struct {
+	Subscription *model.WebhookSubscription
+	Secret       string
+}
+
+// WebhookRepository stores webhook subscriptions, the delivery log and the
+// dead-letter list. Every operation is scoped to the tenant carried in ctx.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, hook *Webhook) error
+	GetWebhook(ctx context.Context, id string) (*Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) (*Webhook, error)
+
+	// RecordDelivery appends to the delivery log, assigning the entry an ID
+	RecordDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	// ListDeliveries returns up to limit attempts for a subscription, newest first
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error)
+
+	// AddDeadLetter stores an event that failed every attempt, assigning it an ID
+	AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error
+	ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error)
+	// TakeDeadLetter removes and returns a dead letter; ErrNotFound if there is none
+	TakeDeadLetter(ctx context.Context, id string) (*model.WebhookDeadLetter, error)
+	Ping(ctx context.Context) error
+}
+
+// maxDeliveryLog bounds the log kept per subscription; the oldest entries go first
+const maxDeliveryLog = 500
+
+type InMemoryWebhookRepository struct {
+	hooks       map[string]map[string]*Webhook                 // tenant ID -> subscription ID -> hook
+	deliveries  map[string]map[string][]*model.WebhookDelivery // tenant ID -> subscription ID -> log, oldest first
+	deadLetters map[string][]*model.WebhookDeadLetter          // tenant ID -> letters, oldest first
+	seq         int64
+	mu          sync.RWMutex
+}
+
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{
+		hooks:       map[string]map[string]*Webhook{},
+		deliveries:  map[string]map[string][]*model.WebhookDelivery{},
+		deadLetters: map[string][]*model.WebhookDeadLetter{},
+	}
+}
+
+func (r *InMemoryWebhookRepository) CreateWebhook(ctx context.Context, hook *Webhook) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := hook.Subscription.ID
+	if _, exists := r.hooks[tenantID][id]; exists {
+		return fmt.Errorf("webhook with id %s %w", id, ErrAlreadyExists)
+	}
+	if r.hooks[tenantID] == nil {
+		r.hooks[tenantID] = map[string]*Webhook{}
+	}
+	r.hooks[tenantID][id] = hook
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hook, exists := r.hooks[tenantID][id]
+	if !exists {
+		return nil, fmt.Errorf("webhook with id %s %w", id, ErrNotFound)
+	}
+	return hook, nil
+}
+
+func (r *InMemoryWebhookRepository) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hooks := make([]*Webhook, 0, len(r.hooks[tenantID]))
+	for _, hook := range r.hooks[tenantID] {
+		hooks = append(hooks, hook)
+	}
+	slices.SortFunc(hooks, func(a, b *Webhook) int {
+		return a.Subscription.CreatedAt.Compare(b.Subscription.CreatedAt)
+	})
+	return hooks, nil
+}
+
+func (r *InMemoryWebhookRepository) DeleteWebhook(ctx context.Context, id string) (*Webhook, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hook, exists := r.hooks[tenantID][id]
+	if !exists {
+		return nil, fmt.Errorf("webhook with id %s %w", id, ErrNotFound)
+	}
+	delete(r.hooks[tenantID], id)
+	return hook, nil
+}
+
+func (r *InMemoryWebhookRepository) RecordDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	delivery.ID = strconv.FormatInt(r.seq, 10)
+	if r.deliveries[tenantID] == nil {
+		r.deliveries[tenantID] = map[string][]*model.WebhookDelivery{}
+	}
+	log := append(r.deliveries[tenantID][delivery.SubscriptionID], delivery)
+	if len(log) > maxDeliveryLog {
+		log = slices.Delete(log, 0, len(log)-maxDeliveryLog)
+	}
+	r.deliveries[tenantID][delivery.SubscriptionID] = log
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	log := r.deliveries[tenantID][subscriptionID]
+	deliveries := make([]*model.WebhookDelivery, 0, min(limit, len(log)))
+	for i := len(log) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		deliveries = append(deliveries, log[i])
+	}
+	return deliveries, nil
+}
+
+func (r *InMemoryWebhookRepository) AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	letter.ID = strconv.FormatInt(r.seq, 10)
+	r.deadLetters[tenantID] = append(r.deadLetters[tenantID], letter)
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.deadLetters[tenantID]), nil
+}
+
+func (r *InMemoryWebhookRepository) TakeDeadLetter(ctx context.Context, id string) (*model.WebhookDeadLetter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters := r.deadLetters[tenantID]
+	i := slices.IndexFunc(letters, func(l *model.WebhookDeadLetter) bool { return l.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("dead letter with id %s %w", id, ErrNotFound)
+	}
+	letter := letters[i]
+	r.deadLetters[tenantID] = slices.Delete(letters, i, i+1)
+	return letter, nil
+}
+
+func (r *InMemoryWebhookRepository) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,159 @@
+package requestlog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/ratelimit"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client identification headers, as sent by Apollo Client and most other clients
+const (
+	ClientNameHeader    = "apollographql-client-name"
+	ClientVersionHeader = "apollographql-client-version"
+)
+
+// Logger logs every GraphQL operation once it has finished, including ones
+// rejected before execution such as invalid or rate limited operations.
+// Subscriptions are logged when they end. Register it with srv.Use.
+type Logger struct {
+	log    *slog.Logger
+	redact redactor
+	es     graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = (*Logger)(nil)
+
+// New logs to log, masking variables named in redactFields
+func New(log *slog.Logger, redactFields []string) *Logger {
+	return &Logger{log: log, redact: newRedactor(redactFields)}
+}
+
+func (l *Logger) ExtensionName() string {
+	return "RequestLog"
+}
+
+func (l *Logger) Validate(schema graphql.ExecutableSchema) error {
+	l.es = schema
+	return nil
+}
+
+// pending collects error codes across the responses of one operation.
+// Subscriptions and @defer produce several, pulled one after another.
+type pending struct {
+	codes  []string
+	logged bool
+}
+
+func (l *Logger) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+	oc := graphql.GetOperationContext(ctx)
+
+	p, _ := oc.Stats.GetExtension("RequestLog").(*pending)
+	if p == nil {
+		p = &pending{}
+		oc.Stats.SetExtension("RequestLog", p)
+	}
+	if p.logged {
+		return resp
+	}
+	if resp != nil {
+		p.codes = append(p.codes, errorCodes(resp)...)
+	}
+
+	// A subscription has ended when it returns nil, anything else with its
+	// last payload
+	subscription := oc.Operation != nil && oc.Operation.Operation == ast.Subscription
+	last := resp == nil || (!subscription && (resp.HasNext == nil || !*resp.HasNext))
+	if last {
+		p.logged = true
+		l.write(ctx, oc, p.codes)
+	}
+	return resp
+}
+
+func (l *Logger) write(ctx context.Context, oc *graphql.OperationContext, codes []string) {
+	name, opType := oc.OperationName, ""
+	if oc.Operation != nil {
+		if name == "" {
+			name = oc.Operation.Name
+		}
+		opType = string(oc.Operation.Operation)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("request_id", RequestID(ctx)),
+		slog.String("operation", name),
+		slog.String("type", opType),
+		slog.Float64("duration_ms", float64(time.Since(oc.Stats.OperationStart).Microseconds())/1000),
+		slog.Any("error_codes", compact(codes)),
+	}
+	if cost, ok := l.complexity(ctx, oc); ok {
+		attrs = append(attrs, slog.Int("complexity", cost))
+	}
+	if id, ok := tenant.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("tenant", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		attrs = append(attrs, slog.String("trace_id", span.TraceID().String()))
+	}
+	if oc.Headers != nil {
+		attrs = append(attrs,
+			slog.String("client_name", oc.Headers.Get(ClientNameHeader)),
+			slog.String("client_version", oc.Headers.Get(ClientVersionHeader)),
+		)
+	}
+	if len(oc.Variables) > 0 {
+		attrs = append(attrs, slog.Any("variables", l.redact.redact(oc.Variables)))
+	}
+
+	level := slog.LevelInfo
+	if len(codes) > 0 {
+		level = slog.LevelWarn
+	}
+	l.log.LogAttrs(ctx, level, "graphql operation", attrs...)
+}
+
+// complexity reuses the rate limiter's figure when it charged the operation
+func (l *Logger) complexity(ctx context.Context, oc *graphql.OperationContext) (int, bool) {
+	if stats := ratelimit.GetStats(ctx); stats != nil {
+		return stats.Cost, true
+	}
+	if oc.Operation == nil || l.es == nil {
+		return 0, false
+	}
+	return complexity.Calculate(ctx, l.es, oc.Operation, oc.Variables), true
+}
+
+// errorCodes lists extensions.code of each error, as the metrics extension counts them
+func errorCodes(resp *graphql.Response) []string {
+	codes := make([]string, 0, len(resp.Errors))
+	for _, err := range resp.Errors {
+		code, _ := err.Extensions["code"].(string)
+		if code == "" {
+			code = "UNKNOWN"
+		}
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// compact sorts and de-duplicates codes, keeping an empty list rather than null
+func compact(codes []string) []string {
+	out := append([]string{}, codes...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
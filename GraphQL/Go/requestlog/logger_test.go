@@ -0,0 +1,107 @@
+package requestlog_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/graphtest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/requestlog"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// newServer logs every operation as JSON to buf, redacting email and token
+func newServer(t *testing.T, buf *bytes.Buffer) *graphtest.Server {
+	t.Helper()
+	repos := graphtest.NewRepositories()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	if err := repos.Users.Create(ctx, &model.User{ID: "1", Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	logger := requestlog.New(slog.New(slog.NewJSONHandler(buf, nil)), []string{"email", "TOKEN"})
+	return graphtest.NewServer(repos, graphtest.Options{Extensions: []graphql.HandlerExtension{logger}})
+}
+
+// entries decodes the JSON lines in buf
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode log line %s: %v", scanner.Bytes(), err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestLogger_RedactsNestedVariables(t *testing.T) {
+	var buf bytes.Buffer
+	srv := newServer(t, &buf)
+
+	srv.Do(t, graphtest.Request{
+		Query: `mutation Signup($input: NewUser!, $token: String!) {
+			createUser(input: $input) { id }
+			verifyEmail(token: $token) { id }
+		}`,
+		Variables: map[string]any{
+			"input": map[string]any{"name": "Bob", "email": "bob@example.com"},
+			"token": "secret-token",
+		},
+	})
+
+	logged := entries(t, &buf)
+	if len(logged) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(logged))
+	}
+	vars := logged[0]["variables"].(map[string]any)
+	input := vars["input"].(map[string]any)
+	if input["email"] != requestlog.Redacted || vars["token"] != requestlog.Redacted {
+		t.Errorf("variables = %v, want email and token redacted", vars)
+	}
+	if input["name"] != "Bob" {
+		t.Errorf("name = %v, want it logged as is", input["name"])
+	}
+	if raw := buf.String(); strings.Contains(raw, "bob@example.com") || strings.Contains(raw, "secret-token") {
+		t.Errorf("log contains a redacted value: %s", raw)
+	}
+	if logged[0]["operation"] != "Signup" || logged[0]["type"] != "mutation" {
+		t.Errorf("entry = %v, want operation Signup of type mutation", logged[0])
+	}
+}
+
+func TestLogger_LogsDeferredOperationOnce(t *testing.T) {
+	var buf bytes.Buffer
+	srv := newServer(t, &buf)
+
+	body := `{"query":"query Deferred { user(id: \"1\") { id ... @defer { postCount } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "multipart/mixed")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"incremental"`) {
+		t.Fatalf("response was not delivered incrementally: %s", rec.Body)
+	}
+	logged := entries(t, &buf)
+	if len(logged) != 1 {
+		t.Fatalf("got %d log entries for one deferred operation, want 1", len(logged))
+	}
+	if logged[0]["operation"] != "Deferred" {
+		t.Errorf("entry = %v, want operation Deferred", logged[0])
+	}
+	if codes := logged[0]["error_codes"].([]any); len(codes) != 0 {
+		t.Errorf("error_codes = %v, want none", codes)
+	}
+}
@@ -0,0 +1,43 @@
+package requestlog
+
+import "strings"
+
+// Redacted replaces the values of redacted fields
+const Redacted = "[REDACTED]"
+
+// redactor masks variables whose name, at any depth, is one of its fields.
+// Names are compared case-insensitively.
+type redactor map[string]struct{}
+
+func newRedactor(fields []string) redactor {
+	r := make(redactor, len(fields))
+	for _, f := range fields {
+		r[strings.ToLower(f)] = struct{}{}
+	}
+	return r
+}
+
+// redact returns a copy of v with sensitive fields masked; v itself is the
+// operation's live variables and must not change
+func (r redactor) redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if _, ok := r[strings.ToLower(k)]; ok && item != nil {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = r.redact(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = r.redact(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+)
+
+// Prefix is where the versioned REST API is mounted
+const Prefix = "/api/v1"
+
+// User is the REST representation of a user
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Post is the REST representation of a post; the author is referenced by ID
+type Post struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title"`
+	Content     *string          `json:"content,omitempty"`
+	AuthorID    string           `json:"authorId"`
+	Status      model.PostStatus `json:"status"`
+	PublishAt   *time.Time       `json:"publishAt,omitempty"`
+	PublishedAt *time.Time       `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+// Handler serves the REST API on top of the same services as the GraphQL resolvers
+type Handler struct {
+	userService service.UserService
+	postService service.PostService
+	mux         *http.ServeMux
+}
+
+// NewHandler creates a new REST handler with injected services
+func NewHandler(userService service.UserService, postService service.PostService) *Handler {
+	h := &Handler{
+		userService: userService,
+		postService: postService,
+		mux:         http.NewServeMux(),
+	}
+	for _, rt := range h.routes() {
+		h.mux.HandleFunc(rt.method+" "+Prefix+rt.path, rt.handler)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePage(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error(), "")
+		return
+	}
+	users, err := h.userService.GetAllUsers(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toUsers(paginate(w, r, p, users)))
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userService.GetUserByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toUser(user))
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var input model.NewUser
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error(), "")
+		return
+	}
+	user, err := h.userService.CreateUser(r.Context(), input)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", Prefix+"/users/"+user.ID)
+	writeJSON(w, http.StatusCreated, toUser(user))
+}
+
+func (h *Handler) listUserPosts(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePage(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error(), "")
+		return
+	}
+	// Unknown users are a 404 rather than an empty list
+	user, err := h.userService.GetUserByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	posts, err := h.postService.GetPostsByUser(r.Context(), user.ID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPosts(paginate(w, r, p, posts)))
+}
+
+func (h *Handler) listPosts(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePage(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error(), "")
+		return
+	}
+	posts, err := h.postService.GetAllPosts(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPosts(paginate(w, r, p, posts)))
+}
+
+func (h *Handler) getPost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postService.GetPostByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPost(post))
+}
+
+func (h *Handler) createPost(w http.ResponseWriter, r *http.Request) {
+	var input model.NewPost
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error(), "")
+		return
+	}
+	post, err := h.postService.CreatePost(r.Context(), input)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", Prefix+"/posts/"+post.ID)
+	writeJSON(w, http.StatusCreated, toPost(post))
+}
+
+func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.postService.DeletePost(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPost(post))
+}
+
+func toUser(u *model.User) User {
+	return User{ID: u.ID, Name: u.Name, Email: u.Email}
+}
+
+func toUsers(users []*model.User) []User {
+	out := make([]User, 0, len(users))
+	for _, u := range users {
+		out = append(out, toUser(u))
+	}
+	return out
+}
+
+func toPost(p *model.Post) Post {
+	return Post{
+		ID:          p.ID,
+		Title:       p.Title,
+		Content:     p.Content,
+		Status:      p.Status,
+		PublishAt:   p.PublishAt,
+		PublishedAt: p.PublishedAt,
+		CreatedAt:   p.CreatedAt,
+		AuthorID:    p.AuthorID,
+	}
+}
+
+func toPosts(posts []*model.Post) []Post {
+	out := make([]Post, 0, len(posts))
+	for _, p := range posts {
+		out = append(out, toPost(p))
+	}
+	return out
+}
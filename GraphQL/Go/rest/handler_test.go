@@ -0,0 +1,189 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// newTestHandler serves the REST API over in-memory repositories holding
+// users u0…u(users-1) and a published post p1 by u0. Callers name themselves with
+// X-User-ID.
+func newTestHandler(t *testing.T, users int) (*Handler, http.Handler) {
+	t.Helper()
+	repos := app.NewRepositories()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	for i := range users {
+		id := fmt.Sprintf("u%d", i)
+		if err := repos.Users.Create(ctx, &model.User{ID: id, Name: "User " + id, Email: id + "@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if users > 0 {
+		if err := repos.Posts.Create(ctx, &model.Post{ID: "p1", Title: "Hello", AuthorID: "u0", Status: model.PostStatusPublished}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	services := app.NewServices(repos, app.Options{})
+	h := NewHandler(services.Users, services.Posts)
+	served := tenant.Middleware(tenant.Options{Default: tenant.Default})(h)
+	return h, auth.Middleware(auth.Options{TrustUserHeader: true})(served)
+}
+
+func do(t *testing.T, h http.Handler, method, target, as, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if as != "" {
+		req.Header.Set(auth.UserHeader, as)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPagination(t *testing.T) {
+	_, h := newTestHandler(t, 5)
+	tests := []struct {
+		query string
+		ids   []string
+		links []string
+	}{
+		{"", []string{"u0", "u1", "u2", "u3", "u4"}, nil},
+		{"?limit=2", []string{"u0", "u1"}, []string{`</api/v1/users?limit=2&offset=2>; rel="next"`}},
+		{"?limit=2&offset=2", []string{"u2", "u3"}, []string{
+			`</api/v1/users?limit=2&offset=4>; rel="next"`,
+			`</api/v1/users?limit=2&offset=0>; rel="prev"`,
+		}},
+		{"?limit=2&offset=4", []string{"u4"}, []string{`</api/v1/users?limit=2&offset=2>; rel="prev"`}},
+		// prev never goes below zero
+		{"?limit=3&offset=1", []string{"u1", "u2", "u3"}, []string{
+			`</api/v1/users?limit=3&offset=4>; rel="next"`,
+			`</api/v1/users?limit=3&offset=0>; rel="prev"`,
+		}},
+		{"?offset=10", []string{}, []string{`</api/v1/users?limit=20&offset=0>; rel="prev"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := do(t, h, http.MethodGet, Prefix+"/users"+tt.query, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != "5" {
+				t.Errorf("X-Total-Count = %q, want 5", got)
+			}
+			if got := rec.Header().Values("Link"); !reflect.DeepEqual(got, tt.links) {
+				t.Errorf("Link = %q, want %q", got, tt.links)
+			}
+			var users []User
+			if err := json.Unmarshal(rec.Body.Bytes(), &users); err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, u := range users {
+				ids = append(ids, u.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("ids = %v, want %v", ids, tt.ids)
+			}
+		})
+	}
+}
+
+func TestPagination_BadParameters(t *testing.T) {
+	_, h := newTestHandler(t, 1)
+	for _, query := range []string{"limit=0", "limit=101", "limit=ten", "offset=-1", "offset=first"} {
+		for _, path := range []string{"/users", "/posts", "/users/u0/posts"} {
+			target := Prefix + path + "?" + query
+			rec := do(t, h, http.MethodGet, target, "", "")
+			if rec.Code != http.StatusBadRequest || errorCode(t, rec) != "BAD_REQUEST" {
+				t.Errorf("GET %s: status %d: %s", target, rec.Code, rec.Body)
+			}
+		}
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	_, h := newTestHandler(t, 2)
+	tests := []struct {
+		name         string
+		method, path string
+		as, body     string
+		status       int
+		code, field  string
+	}{
+		{"unknown user", http.MethodGet, "/users/missing", "", "", http.StatusNotFound, "NOT_FOUND", ""},
+		{"posts of an unknown user", http.MethodGet, "/users/missing/posts", "", "", http.StatusNotFound, "NOT_FOUND", ""},
+		{"unknown post", http.MethodDelete, "/posts/missing", "u0", "", http.StatusNotFound, "NOT_FOUND", ""},
+		{"missing title", http.MethodPost, "/posts", "u0", `{"title": "", "authorId": "u0"}`, http.StatusUnprocessableEntity, "VALIDATION_FAILED", "title"},
+		{"taken email", http.MethodPost, "/users", "", `{"name": "Again", "email": "u1@example.com"}`, http.StatusUnprocessableEntity, "VALIDATION_FAILED", "email"},
+		{"someone else's post", http.MethodDelete, "/posts/p1", "u1", "", http.StatusForbidden, "FORBIDDEN", ""},
+		{"anonymous delete", http.MethodDelete, "/posts/p1", "", "", http.StatusForbidden, "FORBIDDEN", ""},
+		{"unknown body field", http.MethodPost, "/users", "", `{"name": "X", "email": "x@example.com", "admin": true}`, http.StatusBadRequest, "BAD_REQUEST", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, h, tt.method, Prefix+tt.path, tt.as, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var body errorBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error.Code != tt.code || body.Error.Field != tt.field {
+				t.Errorf("error = %+v, want code %s field %q", body.Error, tt.code, tt.field)
+			}
+		})
+	}
+}
+
+// TestWriteServiceError covers the mappings no REST route currently reaches,
+// such as conflicts, and checks unexpected errors are not leaked
+func TestWriteServiceError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("get user: %w", repository.ErrNotFound), http.StatusNotFound, "NOT_FOUND"},
+		{fmt.Errorf("create user: %w", repository.ErrAlreadyExists), http.StatusConflict, "CONFLICT"},
+		{fmt.Errorf("update post: %w", repository.ErrConflict), http.StatusConflict, "CONFLICT"},
+		{&service.ValidationError{Field: "title", Message: "title is required"}, http.StatusUnprocessableEntity, "VALIDATION_FAILED"},
+		{fmt.Errorf("only the author: %w", service.ErrForbidden), http.StatusForbidden, "FORBIDDEN"},
+		{tenant.ErrNoTenant, http.StatusBadRequest, "TENANT_REQUIRED"},
+		{errors.New("connection refused to 10.0.0.5"), http.StatusInternalServerError, "INTERNAL"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeServiceError(rec, tt.err)
+		if rec.Code != tt.status || errorCode(t, rec) != tt.code {
+			t.Errorf("%v: status %d %s, want %d %s", tt.err, rec.Code, errorCode(t, rec), tt.status, tt.code)
+		}
+		if tt.code == "INTERNAL" && strings.Contains(rec.Body.String(), "10.0.0.5") {
+			t.Errorf("internal error leaked its details: %s", rec.Body)
+		}
+	}
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body errorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("not an error body: %s", rec.Body)
+	}
+	return body.Error.Code
+}
@@ -0,0 +1,206 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// route describes one endpoint. The same table registers the handlers and
+// generates the OpenAPI document, so the two cannot drift apart.
+type route struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	paginated   bool
+	request     string // component schema of the JSON body, if any
+	response    string // component schema of the success body
+	list        bool   // response is an array of the schema
+	status      int
+	handler     http.HandlerFunc
+}
+
+func (h *Handler) routes() []route {
+	return []route{
+		{method: http.MethodGet, path: "/users", operationID: "listUsers", summary: "List users",
+			paginated: true, response: "User", list: true, status: http.StatusOK, handler: h.listUsers},
+		{method: http.MethodPost, path: "/users", operationID: "createUser", summary: "Create a user",
+			request: "NewUser", response: "User", status: http.StatusCreated, handler: h.createUser},
+		{method: http.MethodGet, path: "/users/{id}", operationID: "getUser", summary: "Get a user",
+			response: "User", status: http.StatusOK, handler: h.getUser},
+		{method: http.MethodGet, path: "/users/{id}/posts", operationID: "listUserPosts", summary: "List a user's posts",
+			paginated: true, response: "Post", list: true, status: http.StatusOK, handler: h.listUserPosts},
+		{method: http.MethodGet, path: "/posts", operationID: "listPosts", summary: "List posts",
+			paginated: true, response: "Post", list: true, status: http.StatusOK, handler: h.listPosts},
+		{method: http.MethodPost, path: "/posts", operationID: "createPost", summary: "Create a post",
+			request: "NewPost", response: "Post", status: http.StatusCreated, handler: h.createPost},
+		{method: http.MethodGet, path: "/posts/{id}", operationID: "getPost", summary: "Get a post",
+			response: "Post", status: http.StatusOK, handler: h.getPost},
+		{method: http.MethodDelete, path: "/posts/{id}", operationID: "deletePost", summary: "Delete a post",
+			response: "Post", status: http.StatusOK, handler: h.deletePost},
+	}
+}
+
+type object = map[string]any
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+var schemas = object{
+	"User": object{
+		"type":     "object",
+		"required": []string{"id", "name", "email"},
+		"properties": object{
+			"id":    object{"type": "string"},
+			"name":  object{"type": "string"},
+			"email": object{"type": "string", "format": "email"},
+		},
+	},
+	"Post": object{
+		"type":     "object",
+		"required": []string{"id", "title", "authorId", "status", "createdAt"},
+		"properties": object{
+			"id":          object{"type": "string"},
+			"title":       object{"type": "string"},
+			"content":     object{"type": "string"},
+			"authorId":    object{"type": "string"},
+			"status":      object{"type": "string", "enum": []string{"DRAFT", "SCHEDULED", "PUBLISHED", "ARCHIVED"}},
+			"publishAt":   object{"type": "string", "format": "date-time"},
+			"publishedAt": object{"type": "string", "format": "date-time"},
+			"createdAt":   object{"type": "string", "format": "date-time"},
+		},
+	},
+	"NewUser": object{
+		"type":     "object",
+		"required": []string{"name", "email"},
+		"properties": object{
+			"name":  object{"type": "string"},
+			"email": object{"type": "string", "format": "email"},
+		},
+	},
+	"NewPost": object{
+		"type":     "object",
+		"required": []string{"title", "authorId"},
+		"properties": object{
+			"title":    object{"type": "string"},
+			"content":  object{"type": "string"},
+			"authorId": object{"type": "string"},
+		},
+	},
+	"Error": object{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": object{
+			"error": object{
+				"type":     "object",
+				"required": []string{"code", "message"},
+				"properties": object{
+					"code":    object{"type": "string", "example": "NOT_FOUND"},
+					"message": object{"type": "string"},
+					"field":   object{"type": "string"},
+				},
+			},
+		},
+	},
+}
+
+// OpenAPI builds the OpenAPI 3 document for the routes served by h
+func (h *Handler) OpenAPI() map[string]any {
+	paths := object{}
+	for _, rt := range h.routes() {
+		op := object{
+			"operationId": rt.operationID,
+			"summary":     rt.summary,
+			"responses":   responses(rt),
+		}
+
+		var params []object
+		for _, segment := range strings.Split(rt.path, "/") {
+			if name, ok := strings.CutPrefix(segment, "{"); ok {
+				params = append(params, object{
+					"name": strings.TrimSuffix(name, "}"), "in": "path", "required": true,
+					"schema": object{"type": "string"},
+				})
+			}
+		}
+		if rt.paginated {
+			params = append(params,
+				object{"name": "limit", "in": "query", "schema": object{"type": "integer", "minimum": 1, "maximum": maxLimit, "default": defaultLimit}},
+				object{"name": "offset", "in": "query", "schema": object{"type": "integer", "minimum": 0, "default": 0}},
+			)
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if rt.request != "" {
+			op["requestBody"] = object{
+				"required": true,
+				"content":  object{"application/json": object{"schema": ref(rt.request)}},
+			}
+		}
+
+		item, _ := paths[rt.path].(object)
+		if item == nil {
+			item = object{}
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = op
+	}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "API Hub REST API",
+			"version":     "1.0.0",
+			"description": "REST view of the GraphQL service. Send X-Tenant-ID to select a workspace.",
+		},
+		"servers":    []object{{"url": Prefix}},
+		"paths":      paths,
+		"components": object{"schemas": schemas},
+	}
+}
+
+func responses(rt route) object {
+	body := ref(rt.response)
+	if rt.list {
+		body = object{"type": "array", "items": body}
+	}
+	success := object{
+		"description": http.StatusText(rt.status),
+		"content":     object{"application/json": object{"schema": body}},
+	}
+	if rt.paginated {
+		success["headers"] = object{
+			"X-Total-Count": object{"description": "Total number of items", "schema": object{"type": "integer"}},
+			"Link":          object{"description": "RFC 8288 next/prev page links", "schema": object{"type": "string"}},
+		}
+	}
+
+	errorResponse := func(description string) object {
+		return object{
+			"description": description,
+			"content":     object{"application/json": object{"schema": ref("Error")}},
+		}
+	}
+
+	out := object{
+		strconv.Itoa(rt.status): success,
+		"default":               errorResponse("Unexpected error"),
+	}
+	if rt.request != "" {
+		out["400"] = errorResponse("Malformed request body")
+		out["422"] = errorResponse("Validation failed")
+	}
+	if strings.Contains(rt.path, "{id}") {
+		out["404"] = errorResponse("Not found")
+	}
+	return out
+}
+
+// ServeOpenAPI serves the document as JSON. It needs no tenant, so it is mounted
+// outside the tenant-scoped routes.
+func (h *Handler) ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.OpenAPI())
+}
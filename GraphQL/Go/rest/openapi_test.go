@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestOpenAPI_MatchesRoutes checks the document lists exactly the routes in the
+// table and that each one it lists is served
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	h, served := newTestHandler(t, 1)
+
+	var want []string
+	for _, rt := range h.routes() {
+		want = append(want, rt.method+" "+rt.path)
+	}
+	var got []string
+	paths := h.OpenAPI()["paths"].(object)
+	for path, item := range paths {
+		for method := range item.(object) {
+			got = append(got, strings.ToUpper(method)+" "+path)
+		}
+	}
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Fatalf("document lists %q, routes are %q", got, want)
+	}
+
+	// The mux answers unknown routes with plain text; the handlers always with JSON
+	for _, op := range got {
+		method, path, _ := strings.Cut(op, " ")
+		rec := do(t, served, method, Prefix+strings.ReplaceAll(path, "{id}", "missing"), "", "{}")
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s is documented but not served: %d %s", op, rec.Code, rec.Body)
+		}
+	}
+	if rec := do(t, served, http.MethodPut, Prefix+"/posts/p1", "", "{}"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("undocumented PUT /posts/{id}: status %d, want 405", rec.Code)
+	}
+}
+
+func TestOpenAPI_Responses(t *testing.T) {
+	h, _ := newTestHandler(t, 0)
+	paths := h.OpenAPI()["paths"].(object)
+	for _, rt := range h.routes() {
+		op := paths[rt.path].(object)[strings.ToLower(rt.method)].(object)
+		codes := slices.Sorted(maps.Keys(op["responses"].(object)))
+
+		_, hasParams := op["parameters"]
+		if wantParams := rt.paginated || strings.Contains(rt.path, "{id}"); hasParams != wantParams {
+			t.Errorf("%s %s: parameters listed = %v, want %v", rt.method, rt.path, hasParams, wantParams)
+		}
+		if !slices.Contains(codes, "default") {
+			t.Errorf("%s %s: no default error response in %v", rt.method, rt.path, codes)
+		}
+		if strings.Contains(rt.path, "{id}") && !slices.Contains(codes, "404") {
+			t.Errorf("%s %s: no 404 in %v", rt.method, rt.path, codes)
+		}
+		if rt.request != "" && !slices.Contains(codes, "422") {
+			t.Errorf("%s %s: no 422 in %v", rt.method, rt.path, codes)
+		}
+	}
+}
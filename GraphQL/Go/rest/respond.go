@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// errorBody is the JSON shape of every non-2xx response
+type errorBody struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message, field string) {
+	writeJSON(w, status, errorBody{Error: apiError{Code: code, Message: message, Field: field}})
+}
+
+// writeServiceError maps service and repository errors onto HTTP statuses.
+// Unexpected errors are reported without their details.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var validation *service.ValidationError
+	switch {
+	case errors.As(err, &validation):
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_FAILED", validation.Error(), validation.Field)
+	case errors.Is(err, repository.ErrNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), "")
+	case errors.Is(err, service.ErrForbidden):
+		writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error(), "")
+	case errors.Is(err, repository.ErrAlreadyExists), errors.Is(err, repository.ErrConflict):
+		writeError(w, http.StatusConflict, "CONFLICT", err.Error(), "")
+	case errors.Is(err, tenant.ErrNoTenant):
+		writeError(w, http.StatusBadRequest, "TENANT_REQUIRED", err.Error(), "")
+	default:
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error", "")
+	}
+}
+
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// page is the limit/offset window requested by the client
+type page struct {
+	limit  int
+	offset int
+}
+
+func parsePage(q url.Values) (page, error) {
+	p := page{limit: defaultLimit}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			return p, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		p.limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("offset must be a non-negative integer")
+		}
+		p.offset = n
+	}
+	return p, nil
+}
+
+// paginate slices items to the page and sets X-Total-Count and RFC 8288 Link headers
+func paginate[T any](w http.ResponseWriter, r *http.Request, p page, items []T) []T {
+	total := len(items)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	var links []string
+	link := func(rel string, offset int) {
+		u := *r.URL
+		q := u.Query()
+		q.Set("limit", strconv.Itoa(p.limit))
+		q.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel))
+	}
+	if p.offset+p.limit < total {
+		link("next", p.offset+p.limit)
+	}
+	if p.offset > 0 {
+		link("prev", max(p.offset-p.limit, 0))
+	}
+	for _, l := range links {
+		w.Header().Add("Link", l)
+	}
+
+	if p.offset >= total {
+		return []T{}
+	}
+	return items[p.offset:min(p.offset+p.limit, total)]
+}
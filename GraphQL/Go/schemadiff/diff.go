@@ -0,0 +1,322 @@
+// Package schemadiff compares two versions of a GraphQL schema and checks
+// stored client operations against a schema, so changes that would break
+// existing clients are caught before they ship.
+package schemadiff
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Severity says how a change affects existing clients
+type Severity string
+
+const (
+	// Breaking changes make some valid operations invalid or change what they return
+	Breaking Severity = "BREAKING"
+	// Dangerous changes keep operations valid but can change their behaviour,
+	// e.g. a new enum value a client does not handle
+	Dangerous Severity = "DANGEROUS"
+	Safe      Severity = "SAFE"
+)
+
+// Change is one difference between the schemas
+type Change struct {
+	Severity Severity `json:"severity"`
+	// Kind is a stable code such as FIELD_REMOVED
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Compare lists the differences from old to new, breaking changes first
+func Compare(old, new *ast.Schema) []Change {
+	d := &differ{}
+	for name, oldType := range old.Types {
+		if oldType.BuiltIn {
+			continue
+		}
+		newType, ok := new.Types[name]
+		if !ok {
+			d.add(Breaking, "TYPE_REMOVED", name, "type %s was removed", name)
+			continue
+		}
+		if oldType.Kind != newType.Kind {
+			d.add(Breaking, "TYPE_KIND_CHANGED", name, "%s changed from %s to %s", name, kindName(oldType.Kind), kindName(newType.Kind))
+			continue
+		}
+		d.compareType(oldType, newType)
+	}
+	for name, newType := range new.Types {
+		if _, ok := old.Types[name]; !ok && !newType.BuiltIn {
+			d.add(Safe, "TYPE_ADDED", name, "%s %s was added", kindName(newType.Kind), name)
+		}
+	}
+
+	for name, oldDir := range old.Directives {
+		newDir, ok := new.Directives[name]
+		if !ok {
+			d.add(Breaking, "DIRECTIVE_REMOVED", "@"+name, "directive @%s was removed", name)
+			continue
+		}
+		d.compareDirective(oldDir, newDir)
+	}
+	for name := range new.Directives {
+		if _, ok := old.Directives[name]; !ok {
+			d.add(Safe, "DIRECTIVE_ADDED", "@"+name, "directive @%s was added", name)
+		}
+	}
+
+	d.compareRoot("query", old.Query, new.Query)
+	d.compareRoot("mutation", old.Mutation, new.Mutation)
+	d.compareRoot("subscription", old.Subscription, new.Subscription)
+
+	slices.SortFunc(d.changes, func(a, b Change) int {
+		return cmp.Or(
+			cmp.Compare(severityRank(a.Severity), severityRank(b.Severity)),
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(a.Kind, b.Kind),
+		)
+	})
+	return d.changes
+}
+
+// HasBreaking reports whether any change is breaking
+func HasBreaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Severity == Breaking })
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(severity Severity, kind, path, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Severity: severity,
+		Kind:     kind,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) compareType(old, new *ast.Definition) {
+	switch old.Kind {
+	case ast.Object, ast.Interface:
+		d.compareFields(old, new)
+		d.compareMembers(old.Name, "INTERFACE", old.Interfaces, new.Interfaces)
+	case ast.InputObject:
+		d.compareInputFields(old, new)
+	case ast.Enum:
+		d.compareEnum(old, new)
+	case ast.Union:
+		d.compareMembers(old.Name, "UNION_MEMBER", old.Types, new.Types)
+	}
+}
+
+func (d *differ) compareFields(old, new *ast.Definition) {
+	for _, oldField := range old.Fields {
+		path := old.Name + "." + oldField.Name
+		newField := new.Fields.ForName(oldField.Name)
+		if newField == nil {
+			kind := "FIELD_REMOVED"
+			if oldField.Directives.ForName("deprecated") != nil {
+				kind = "DEPRECATED_FIELD_REMOVED"
+			}
+			d.add(Breaking, kind, path, "field %s was removed", path)
+			continue
+		}
+
+		if !safeOutputChange(oldField.Type, newField.Type) {
+			d.add(Breaking, "FIELD_TYPE_CHANGED", path, "field %s changed type from %s to %s", path, oldField.Type, newField.Type)
+		} else if oldField.Type.String() != newField.Type.String() {
+			d.add(Safe, "FIELD_TYPE_CHANGED", path, "field %s changed type from %s to %s", path, oldField.Type, newField.Type)
+		}
+		if oldField.Directives.ForName("deprecated") == nil && newField.Directives.ForName("deprecated") != nil {
+			d.add(Safe, "FIELD_DEPRECATED", path, "field %s was deprecated", path)
+		}
+		d.compareArguments(path, oldField.Arguments, newField.Arguments)
+	}
+	for _, newField := range new.Fields {
+		if old.Fields.ForName(newField.Name) == nil {
+			path := new.Name + "." + newField.Name
+			d.add(Safe, "FIELD_ADDED", path, "field %s was added", path)
+		}
+	}
+}
+
+func (d *differ) compareArguments(owner string, old, new ast.ArgumentDefinitionList) {
+	for _, oldArg := range old {
+		path := owner + "(" + oldArg.Name + ")"
+		newArg := new.ForName(oldArg.Name)
+		if newArg == nil {
+			d.add(Breaking, "ARG_REMOVED", path, "argument %s was removed", path)
+			continue
+		}
+		d.compareInput(path, "ARG", oldArg.Type, newArg.Type, oldArg.DefaultValue, newArg.DefaultValue)
+	}
+	for _, newArg := range new {
+		if old.ForName(newArg.Name) != nil {
+			continue
+		}
+		path := owner + "(" + newArg.Name + ")"
+		if required(newArg.Type, newArg.DefaultValue) {
+			d.add(Breaking, "REQUIRED_ARG_ADDED", path, "required argument %s was added", path)
+		} else {
+			d.add(Dangerous, "OPTIONAL_ARG_ADDED", path, "optional argument %s was added", path)
+		}
+	}
+}
+
+func (d *differ) compareInputFields(old, new *ast.Definition) {
+	for _, oldField := range old.Fields {
+		path := old.Name + "." + oldField.Name
+		newField := new.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(Breaking, "INPUT_FIELD_REMOVED", path, "input field %s was removed", path)
+			continue
+		}
+		d.compareInput(path, "INPUT_FIELD", oldField.Type, newField.Type, oldField.DefaultValue, newField.DefaultValue)
+	}
+	for _, newField := range new.Fields {
+		if old.Fields.ForName(newField.Name) != nil {
+			continue
+		}
+		path := new.Name + "." + newField.Name
+		if required(newField.Type, newField.DefaultValue) {
+			d.add(Breaking, "REQUIRED_INPUT_FIELD_ADDED", path, "required input field %s was added", path)
+		} else {
+			d.add(Dangerous, "OPTIONAL_INPUT_FIELD_ADDED", path, "optional input field %s was added", path)
+		}
+	}
+}
+
+// compareInput handles arguments and input fields, where clients send values:
+// making a type stricter breaks them, relaxing it does not
+func (d *differ) compareInput(path, what string, oldType, newType *ast.Type, oldDefault, newDefault *ast.Value) {
+	switch {
+	case !safeInputChange(oldType, newType):
+		d.add(Breaking, what+"_TYPE_CHANGED", path, "%s changed type from %s to %s", path, oldType, newType)
+	case oldType.String() != newType.String():
+		d.add(Safe, what+"_TYPE_CHANGED", path, "%s changed type from %s to %s", path, oldType, newType)
+	}
+	if valueString(oldDefault) != valueString(newDefault) {
+		d.add(Dangerous, what+"_DEFAULT_CHANGED", path, "%s default changed from %s to %s", path, valueString(oldDefault), valueString(newDefault))
+	}
+}
+
+func (d *differ) compareEnum(old, new *ast.Definition) {
+	for _, value := range old.EnumValues {
+		if new.EnumValues.ForName(value.Name) == nil {
+			path := old.Name + "." + value.Name
+			d.add(Breaking, "ENUM_VALUE_REMOVED", path, "enum value %s was removed", path)
+		}
+	}
+	for _, value := range new.EnumValues {
+		if old.EnumValues.ForName(value.Name) == nil {
+			path := new.Name + "." + value.Name
+			d.add(Dangerous, "ENUM_VALUE_ADDED", path, "enum value %s was added; clients may not handle it", path)
+		}
+	}
+}
+
+// compareMembers handles union members and implemented interfaces. Removing
+// one breaks fragments on it; adding one can surprise exhaustive clients.
+func (d *differ) compareMembers(owner, what string, old, new []string) {
+	for _, name := range old {
+		if !slices.Contains(new, name) {
+			d.add(Breaking, what+"_REMOVED", owner, "%s no longer includes %s", owner, name)
+		}
+	}
+	for _, name := range new {
+		if !slices.Contains(old, name) {
+			d.add(Dangerous, what+"_ADDED", owner, "%s now includes %s", owner, name)
+		}
+	}
+}
+
+func (d *differ) compareDirective(old, new *ast.DirectiveDefinition) {
+	path := "@" + old.Name
+	for _, loc := range old.Locations {
+		if !slices.Contains(new.Locations, loc) {
+			d.add(Breaking, "DIRECTIVE_LOCATION_REMOVED", path, "directive %s can no longer be used on %s", path, loc)
+		}
+	}
+	d.compareArguments(path, old.Arguments, new.Arguments)
+}
+
+func (d *differ) compareRoot(operation string, old, new *ast.Definition) {
+	switch {
+	case old != nil && new == nil:
+		d.add(Breaking, "ROOT_TYPE_REMOVED", operation, "the schema no longer supports %s operations", operation)
+	case old != nil && old.Name != new.Name:
+		d.add(Breaking, "ROOT_TYPE_CHANGED", operation, "the %s root type changed from %s to %s", operation, old.Name, new.Name)
+	}
+}
+
+// safeOutputChange reports whether every value of type new is also a valid
+// value of type old: the same shape, and nothing that was non-null is now nullable
+func safeOutputChange(old, new *ast.Type) bool {
+	if old.NonNull && !new.NonNull {
+		return false
+	}
+	if (old.Elem == nil) != (new.Elem == nil) {
+		return false
+	}
+	if old.Elem != nil {
+		return safeOutputChange(old.Elem, new.Elem)
+	}
+	return old.NamedType == new.NamedType
+}
+
+// safeInputChange reports whether every value clients could send for old is
+// still accepted by new: the same shape, and nothing nullable is now required
+func safeInputChange(old, new *ast.Type) bool {
+	if new.NonNull && !old.NonNull {
+		return false
+	}
+	if (old.Elem == nil) != (new.Elem == nil) {
+		return false
+	}
+	if old.Elem != nil {
+		return safeInputChange(old.Elem, new.Elem)
+	}
+	return old.NamedType == new.NamedType
+}
+
+// required reports whether clients must supply a value
+func required(t *ast.Type, defaultValue *ast.Value) bool {
+	return t.NonNull && defaultValue == nil
+}
+
+func valueString(v *ast.Value) string {
+	if v == nil {
+		return "none"
+	}
+	return v.String()
+}
+
+func kindName(kind ast.DefinitionKind) string {
+	switch kind {
+	case ast.Object:
+		return "type"
+	case ast.InputObject:
+		return "input"
+	default:
+		return strings.ToLower(string(kind))
+	}
+}
+
+func severityRank(s Severity) int {
+	switch s {
+	case Breaking:
+		return 0
+	case Dangerous:
+		return 1
+	default:
+		return 2
+	}
+}
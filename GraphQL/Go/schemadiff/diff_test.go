@@ -0,0 +1,172 @@
+package schemadiff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func mustLoad(t *testing.T, sdl string) *ast.Schema {
+	t.Helper()
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: t.Name(), Input: sdl})
+	if err != nil {
+		t.Fatalf("load %q: %v", sdl, err)
+	}
+	return schema
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Change // only Severity, Kind and Path are compared
+	}{
+		{
+			name: "no changes",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a: String }`,
+		},
+		{
+			name: "field removed",
+			old:  `type Query { a: String b: String }`,
+			new:  `type Query { a: String }`,
+			want: []Change{{Severity: Breaking, Kind: "FIELD_REMOVED", Path: "Query.b"}},
+		},
+		{
+			name: "deprecated field removed",
+			old:  `type Query { a: String b: String @deprecated }`,
+			new:  `type Query { a: String }`,
+			want: []Change{{Severity: Breaking, Kind: "DEPRECATED_FIELD_REMOVED", Path: "Query.b"}},
+		},
+		{
+			name: "field added",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a: String b: Int }`,
+			want: []Change{{Severity: Safe, Kind: "FIELD_ADDED", Path: "Query.b"}},
+		},
+		{
+			name: "output made non-null",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a: String! }`,
+			want: []Change{{Severity: Safe, Kind: "FIELD_TYPE_CHANGED", Path: "Query.a"}},
+		},
+		{
+			name: "output made nullable",
+			old:  `type Query { a: String! }`,
+			new:  `type Query { a: String }`,
+			want: []Change{{Severity: Breaking, Kind: "FIELD_TYPE_CHANGED", Path: "Query.a"}},
+		},
+		{
+			name: "argument made required",
+			old:  `type Query { a(x: Int): String }`,
+			new:  `type Query { a(x: Int!): String }`,
+			want: []Change{{Severity: Breaking, Kind: "ARG_TYPE_CHANGED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "argument made optional",
+			old:  `type Query { a(x: Int!): String }`,
+			new:  `type Query { a(x: Int): String }`,
+			want: []Change{{Severity: Safe, Kind: "ARG_TYPE_CHANGED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "required argument added",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a(x: Int!): String }`,
+			want: []Change{{Severity: Breaking, Kind: "REQUIRED_ARG_ADDED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "optional argument added",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a(x: Int): String }`,
+			want: []Change{{Severity: Dangerous, Kind: "OPTIONAL_ARG_ADDED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "non-null argument with a default added",
+			old:  `type Query { a: String }`,
+			new:  `type Query { a(x: Int! = 1): String }`,
+			want: []Change{{Severity: Dangerous, Kind: "OPTIONAL_ARG_ADDED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "argument default changed",
+			old:  `type Query { a(x: Int = 1): String }`,
+			new:  `type Query { a(x: Int = 2): String }`,
+			want: []Change{{Severity: Dangerous, Kind: "ARG_DEFAULT_CHANGED", Path: "Query.a(x)"}},
+		},
+		{
+			name: "input field default removed",
+			old:  `type Query { a(in: In): String } input In { x: Int = 1 }`,
+			new:  `type Query { a(in: In): String } input In { x: Int }`,
+			want: []Change{{Severity: Dangerous, Kind: "INPUT_FIELD_DEFAULT_CHANGED", Path: "In.x"}},
+		},
+		{
+			name: "required input field added",
+			old:  `type Query { a(in: In): String } input In { x: Int }`,
+			new:  `type Query { a(in: In): String } input In { x: Int y: Int! }`,
+			want: []Change{{Severity: Breaking, Kind: "REQUIRED_INPUT_FIELD_ADDED", Path: "In.y"}},
+		},
+		{
+			name: "enum values removed and added",
+			old:  `type Query { c: Color } enum Color { RED BLUE }`,
+			new:  `type Query { c: Color } enum Color { RED GREEN }`,
+			want: []Change{
+				{Severity: Breaking, Kind: "ENUM_VALUE_REMOVED", Path: "Color.BLUE"},
+				{Severity: Dangerous, Kind: "ENUM_VALUE_ADDED", Path: "Color.GREEN"},
+			},
+		},
+		{
+			name: "type removed",
+			old:  `type Query { a: String } type Extra { b: String }`,
+			new:  `type Query { a: String }`,
+			want: []Change{{Severity: Breaking, Kind: "TYPE_REMOVED", Path: "Extra"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Change
+			for _, c := range Compare(mustLoad(t, tt.old), mustLoad(t, tt.new)) {
+				got = append(got, Change{Severity: c.Severity, Kind: c.Kind, Path: c.Path})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("changes = %+v, want %+v", got, tt.want)
+			}
+			if HasBreaking(got) != slices.ContainsFunc(tt.want, func(c Change) bool { return c.Severity == Breaking }) {
+				t.Errorf("HasBreaking = %v", HasBreaking(got))
+			}
+		})
+	}
+}
+
+func TestSafeTypeChange(t *testing.T) {
+	// fieldType reads the type of Query.f from a one-field schema
+	fieldType := func(t *testing.T, typ string) *ast.Type {
+		return mustLoad(t, `type Query { f: `+typ+` }`).Query.Fields.ForName("f").Type
+	}
+	tests := []struct {
+		old, new      string
+		output, input bool
+	}{
+		{"String", "String", true, true},
+		{"String", "String!", true, false},
+		{"String!", "String", false, true},
+		{"String", "Int", false, false},
+		{"[String]", "String", false, false},
+		{"String", "[String]", false, false},
+		{"[String]", "[String!]", true, false},
+		{"[String!]", "[String]", false, true},
+		{"[String]!", "[String]", false, true},
+		{"[String]", "[String]!", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.old+" to "+tt.new, func(t *testing.T) {
+			old, new := fieldType(t, tt.old), fieldType(t, tt.new)
+			if got := safeOutputChange(old, new); got != tt.output {
+				t.Errorf("safeOutputChange = %v, want %v", got, tt.output)
+			}
+			if got := safeInputChange(old, new); got != tt.input {
+				t.Errorf("safeInputChange = %v, want %v", got, tt.input)
+			}
+		})
+	}
+}
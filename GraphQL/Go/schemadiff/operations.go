@@ -0,0 +1,91 @@
+package schemadiff
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// LoadSchema parses SDL files into a schema, the same way gqlgen does
+func LoadSchema(paths ...string) (*ast.Schema, error) {
+	var sources []*ast.Source
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, &ast.Source{Name: path, Input: string(data)})
+	}
+	schema, err := gqlparser.LoadSchema(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("load schema: %w", err)
+	}
+	return schema, nil
+}
+
+// OperationFailure is a stored operation that does not validate against the schema
+type OperationFailure struct {
+	File   string   `json:"file"`
+	Errors []string `json:"errors"`
+}
+
+// CheckOperations validates every .graphql and .gql file under dir against
+// schema, each file on its own. It returns how many files it checked.
+func CheckOperations(schema *ast.Schema, dir string) (int, []OperationFailure, error) {
+	checked := 0
+	var failures []OperationFailure
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".graphql" && ext != ".gql" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		checked++
+
+		rel, _ := filepath.Rel(dir, path)
+		if errs := checkOperation(schema, path, string(data)); len(errs) > 0 {
+			failures = append(failures, OperationFailure{File: rel, Errors: errs})
+		}
+		return nil
+	})
+	return checked, failures, err
+}
+
+func checkOperation(schema *ast.Schema, name, query string) []string {
+	doc, err := parser.ParseQuery(&ast.Source{Name: name, Input: query})
+	if err != nil {
+		if gqlErr, ok := err.(*gqlerror.Error); ok {
+			return messages(gqlerror.List{gqlErr})
+		}
+		return []string{err.Error()}
+	}
+	return messages(validator.Validate(schema, doc))
+}
+
+func messages(errs gqlerror.List) []string {
+	out := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msg := err.Message
+		if len(err.Locations) > 0 {
+			msg = fmt.Sprintf("%d:%d: %s", err.Locations[0].Line, err.Locations[0].Column, msg)
+		}
+		out = append(out, msg)
+	}
+	return out
+}
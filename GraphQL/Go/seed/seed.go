@@ -0,0 +1,218 @@
+package seed
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultFixture []byte
+
+// Fixture describes the users and posts to load into one tenant
+type Fixture struct {
+	// Tenant defaults to the workspace passed to Apply
+	Tenant string `json:"tenant" yaml:"tenant"`
+	Users  []User `json:"users" yaml:"users"`
+	Posts  []Post `json:"posts" yaml:"posts"`
+}
+
+// User is a fixture user. Key is a fixture-local name that posts use to reference
+// their author, since real IDs are assigned by the service.
+type User struct {
+	Key   string `json:"key" yaml:"key"`
+	Name  string `json:"name" yaml:"name"`
+	Email string `json:"email" yaml:"email"`
+}
+
+// Post is a fixture post; Author is the Key of a user in the same fixture.
+// Posts are published unless Draft is set.
+type Post struct {
+	Title   string  `json:"title" yaml:"title"`
+	Content *string `json:"content" yaml:"content"`
+	Author  string  `json:"author" yaml:"author"`
+	Draft   bool    `json:"draft" yaml:"draft"`
+}
+
+// Result counts what Apply created and what already existed
+type Result struct {
+	UsersCreated int
+	UsersSkipped int
+	PostsCreated int
+	PostsSkipped int
+}
+
+// Default returns the built-in sample fixture (Alice and Bob)
+func Default() (*Fixture, error) {
+	return parse(defaultFixture, ".yaml")
+}
+
+// LoadFile reads a YAML (.yaml/.yml) or JSON (.json) fixture
+func LoadFile(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	f, err := parse(data, strings.ToLower(filepath.Ext(path)))
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func parse(data []byte, ext string) (*Fixture, error) {
+	var f Fixture
+	switch ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(strings.NewReader(string(data)))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	case ".json":
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q (want .yaml, .yml or .json)", ext)
+	}
+	return &f, f.validate()
+}
+
+// validate checks references inside the fixture before anything is written
+func (f *Fixture) validate() error {
+	keys := make(map[string]bool, len(f.Users))
+	for i, u := range f.Users {
+		if u.Key == "" {
+			return fmt.Errorf("users[%d]: key is required", i)
+		}
+		if keys[u.Key] {
+			return fmt.Errorf("users[%d]: duplicate key %q", i, u.Key)
+		}
+		keys[u.Key] = true
+	}
+	for i, p := range f.Posts {
+		if !keys[p.Author] {
+			return fmt.Errorf("posts[%d]: unknown author %q", i, p.Author)
+		}
+	}
+	return nil
+}
+
+// Seeder writes fixtures through the service layer so validation still applies
+type Seeder struct {
+	users service.UserService
+	posts service.PostService
+}
+
+// NewSeeder creates a seeder with injected services
+func NewSeeder(users service.UserService, posts service.PostService) *Seeder {
+	return &Seeder{users: users, posts: posts}
+}
+
+// Apply loads f into its tenant (or defaultTenant). It is idempotent: users are
+// matched by email and each fixture post by author and title, so re-running
+// creates nothing new and a run that stopped part way is completed.
+func (s *Seeder) Apply(ctx context.Context, f *Fixture, defaultTenant string) (Result, error) {
+	var res Result
+
+	tenantID := f.Tenant
+	if tenantID == "" {
+		tenantID = defaultTenant
+	}
+	ctx = tenant.WithID(ctx, tenantID)
+
+	existing, err := s.users.GetAllUsers(ctx)
+	if err != nil {
+		return res, err
+	}
+	byEmail := make(map[string]*model.User, len(existing))
+	for _, u := range existing {
+		byEmail[repository.NormalizeEmail(u.Email)] = u
+	}
+
+	byKey := make(map[string]*model.User, len(f.Users))
+	for _, fu := range f.Users {
+		if u, ok := byEmail[repository.NormalizeEmail(fu.Email)]; ok {
+			byKey[fu.Key] = u
+			res.UsersSkipped++
+			continue
+		}
+		u, err := s.users.CreateUser(ctx, model.NewUser{Name: fu.Name, Email: fu.Email})
+		if err != nil {
+			return res, fmt.Errorf("seed user %q: %w", fu.Key, err)
+		}
+		byEmail[repository.NormalizeEmail(u.Email)] = u
+		byKey[fu.Key] = u
+		res.UsersCreated++
+	}
+
+	// Each fixture post claims one existing post with its author and title, so
+	// a fixture may repeat a title and an interrupted run is completed post by
+	// post: missing posts are created and ones left as drafts are published.
+	existingPosts := map[string]map[string][]*model.Post{}
+	for _, fp := range f.Posts {
+		author := byKey[fp.Author]
+		// Posts are written as their author, who alone may publish them
+		authorCtx := auth.WithClaims(ctx, &auth.Claims{Subject: author.ID})
+		if existingPosts[author.ID] == nil {
+			posts, err := s.posts.GetPostsByUser(authorCtx, author.ID)
+			if err != nil {
+				return res, err
+			}
+			existingPosts[author.ID] = make(map[string][]*model.Post, len(posts))
+			for _, p := range posts {
+				existingPosts[author.ID][p.Title] = append(existingPosts[author.ID][p.Title], p)
+			}
+		}
+
+		post := claim(existingPosts[author.ID], fp.Title)
+		if post != nil {
+			res.PostsSkipped++
+		} else {
+			created, err := s.posts.CreatePost(authorCtx, model.NewPost{Title: fp.Title, Content: fp.Content, AuthorID: author.ID})
+			if err != nil {
+				return res, fmt.Errorf("seed post %q: %w", fp.Title, err)
+			}
+			post = created
+			res.PostsCreated++
+		}
+		if !fp.Draft && post.Status == model.PostStatusDraft {
+			if _, err := s.posts.PublishPost(authorCtx, post.ID); err != nil {
+				return res, fmt.Errorf("publish seeded post %q: %w", fp.Title, err)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// claim removes and returns one of the posts titled title, preferring one
+// already published so a draft is only published when no copy is
+func claim(byTitle map[string][]*model.Post, title string) *model.Post {
+	posts := byTitle[title]
+	if len(posts) == 0 {
+		return nil
+	}
+	i := slices.IndexFunc(posts, func(p *model.Post) bool { return p.Status != model.PostStatusDraft })
+	if i < 0 {
+		i = 0
+	}
+	post := posts[i]
+	byTitle[title] = slices.Delete(posts, i, i+1)
+	return post
+}
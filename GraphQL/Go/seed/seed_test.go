@@ -0,0 +1,140 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func text(s string) *string { return &s }
+
+// fixture repeats a title for one author, as a fixture may
+var fixture = &Fixture{
+	Users: []User{
+		{Key: "alice", Name: "Alice Johnson", Email: "alice@example.com"},
+		{Key: "bob", Name: "Bob Smith", Email: "bob@example.com"},
+	},
+	Posts: []Post{
+		{Title: "Hello", Content: text("First"), Author: "alice"},
+		{Title: "Weekly notes", Content: text("Week 1"), Author: "alice"},
+		{Title: "Weekly notes", Content: text("Week 2"), Author: "alice"},
+		{Title: "Ideas", Author: "alice", Draft: true},
+		{Title: "Resolvers", Author: "bob"},
+	},
+}
+
+// failingPosts stops the run at a chosen point, like a crash part way through
+type failingPosts struct {
+	service.PostService
+	createsLeft   int // CreatePost calls that succeed; negative for all
+	failPublishes bool
+}
+
+var errInterrupted = errors.New("interrupted")
+
+func (p *failingPosts) CreatePost(ctx context.Context, input model.NewPost) (*model.Post, error) {
+	if p.createsLeft == 0 {
+		return nil, errInterrupted
+	}
+	p.createsLeft--
+	return p.PostService.CreatePost(ctx, input)
+}
+
+func (p *failingPosts) PublishPost(ctx context.Context, id string) (*model.Post, error) {
+	if p.failPublishes {
+		return nil, errInterrupted
+	}
+	return p.PostService.PublishPost(ctx, id)
+}
+
+// statuses counts each author's posts by title and status
+func statuses(t *testing.T, services app.Services) map[string]int {
+	t.Helper()
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	users, err := services.Users.GetAllUsers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]int{}
+	for _, u := range users {
+		posts, err := services.Posts.GetPostsByUser(auth.WithClaims(ctx, &auth.Claims{Subject: u.ID}), u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range posts {
+			out[u.Name+"/"+p.Title+"/"+string(p.Status)]++
+		}
+	}
+	return out
+}
+
+var want = map[string]int{
+	"Alice Johnson/Hello/PUBLISHED":        1,
+	"Alice Johnson/Weekly notes/PUBLISHED": 2,
+	"Alice Johnson/Ideas/DRAFT":            1,
+	"Bob Smith/Resolvers/PUBLISHED":        1,
+}
+
+func TestApply_Twice(t *testing.T) {
+	services := app.NewServices(app.NewRepositories(), app.Options{})
+	seeder := NewSeeder(services.Users, services.Posts)
+
+	first, err := seeder.Apply(context.Background(), fixture, tenant.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != (Result{UsersCreated: 2, PostsCreated: 5}) {
+		t.Errorf("first run = %+v", first)
+	}
+	second, err := seeder.Apply(context.Background(), fixture, tenant.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != (Result{UsersSkipped: 2, PostsSkipped: 5}) {
+		t.Errorf("second run = %+v", second)
+	}
+	if got := statuses(t, services); !maps.Equal(got, want) {
+		t.Errorf("posts = %v, want %v", got, want)
+	}
+}
+
+func TestApply_ResumesPartialRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		interrupted failingPosts
+		resumed     Result
+	}{
+		// The second "Weekly notes" is missing and must not be taken for the first
+		{"stopped while creating", failingPosts{createsLeft: 2}, Result{UsersSkipped: 2, PostsCreated: 3, PostsSkipped: 2}},
+		// "Hello" was created but left a draft
+		{"stopped while publishing", failingPosts{createsLeft: -1, failPublishes: true}, Result{UsersSkipped: 2, PostsCreated: 4, PostsSkipped: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := app.NewServices(app.NewRepositories(), app.Options{})
+			interrupted := tt.interrupted
+			interrupted.PostService = services.Posts
+			if _, err := NewSeeder(services.Users, &interrupted).Apply(context.Background(), fixture, tenant.Default); !errors.Is(err, errInterrupted) {
+				t.Fatalf("interrupted run: %v", err)
+			}
+
+			res, err := NewSeeder(services.Users, services.Posts).Apply(context.Background(), fixture, tenant.Default)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != tt.resumed {
+				t.Errorf("resumed run = %+v, want %+v", res, tt.resumed)
+			}
+			if got := statuses(t, services); !maps.Equal(got, want) {
+				t.Errorf("posts = %v, want %v", got, want)
+			}
+		})
+	}
+}
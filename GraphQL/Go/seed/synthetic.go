@@ -0,0 +1,46 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+var words = strings.Fields(`graph query schema resolver field mutation subscription
+	cursor edge node tenant latency cache index shard replica stream batch token`)
+
+// Synthetic generates n users with postsPerUser posts each for load testing.
+// The same n and seed always produce the same fixture, so re-seeding is idempotent.
+func Synthetic(n, postsPerUser int, seed uint64) *Fixture {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	f := &Fixture{
+		Users: make([]User, 0, n),
+		Posts: make([]Post, 0, n*postsPerUser),
+	}
+
+	for i := range n {
+		key := fmt.Sprintf("synthetic-%d", i)
+		f.Users = append(f.Users, User{
+			Key:   key,
+			Name:  fmt.Sprintf("Synthetic User %d", i),
+			Email: fmt.Sprintf("synthetic-%d@example.test", i),
+		})
+		for j := range postsPerUser {
+			content := sentence(rng, 12+rng.IntN(40))
+			f.Posts = append(f.Posts, Post{
+				Title:   fmt.Sprintf("%s #%d", sentence(rng, 3), j),
+				Content: &content,
+				Author:  key,
+			})
+		}
+	}
+	return f
+}
+
+func sentence(rng *rand.Rand, n int) string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = words[rng.IntN(len(words))]
+	}
+	return strings.Join(out, " ")
+}
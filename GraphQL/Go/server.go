@@ -1,45 +1,443 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/cachecontrol"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/config"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/health"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/metrics"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/middleware"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/mock"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/outbox"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/persisted"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/ratelimit"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/requestlog"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/rest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/seed"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/service"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/stream"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tracing"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/transfer"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/webhook"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
-const defaultPort = "8080"
-
 func main() {
-    port := os.Getenv("PORT")
-    if port == "" {
-        port = defaultPort
-    }
-
-    // Initialize repositories (data layer)
-    userRepo := repository.NewInMemoryUserRepository()
-    postRepo := repository.NewInMemoryPostRepository()
-
-    // Initialize services (business logic layer)
-    userService := service.NewUserService(userRepo)
-    postService := service.NewPostService(postRepo, userRepo)
-
-    // Initialize resolver with dependency injection
-    resolver := graph.NewResolver(userService, postService)
-
-    // Create GraphQL server
-    srv := handler.NewDefaultServer(
-        graph.NewExecutableSchema(graph.Config{Resolvers: resolver}),
-    )
-
-    // Setup routes
-    http.Handle("/", playground.Handler("GraphQL Playground", "/query"))
-    http.Handle("/query", srv)
-
-    log.Printf("Connect to http://localhost:%s/ for GraphQL playground", port)
-    log.Fatal(http.ListenAndServe(":"+port, nil))
-}
\ No newline at end of file
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	flag.StringVar(&cfg.SeedFile, "seed", cfg.SeedFile, `fixture file to load at startup (YAML or JSON), or "none"`)
+	flag.IntVar(&cfg.SeedSynthetic, "seed-synthetic", cfg.SeedSynthetic, "number of synthetic users (with 5 posts each) to generate")
+	flag.BoolVar(&cfg.Mock, "mock", cfg.Mock, "answer every GraphQL field with generated data instead of the repositories")
+	flag.Parse()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	// Requests without a tenant header or claim fall back to the sample workspace
+	// unless a tenant is required
+	defaultTenant := tenant.Default
+	if cfg.RequireTenant {
+		defaultTenant = ""
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), "graphql-api", cfg.TracesExporter)
+	if err != nil {
+		log.Fatalf("tracing: %v", err)
+	}
+
+	// Initialize repositories (data layer). User and post writes record their
+	// events in the outbox as part of the same write.
+	outboxStore := repository.NewInMemoryOutboxRepository()
+	users := repository.NewInMemoryUserRepository()
+	users.UseOutbox(outboxStore)
+	posts := repository.NewInMemoryPostRepository()
+	posts.UseOutbox(outboxStore)
+	outboxRepo := repository.NewTracedOutboxRepository(outboxStore)
+	userRepo := repository.NewTracedUserRepository(users)
+	postRepo := repository.NewTracedPostRepository(posts)
+	revisionRepo := repository.NewTracedRevisionRepository(repository.NewInMemoryRevisionRepository())
+	followRepo := repository.NewTracedFollowRepository(repository.NewInMemoryFollowRepository())
+	webhookRepo := repository.NewTracedWebhookRepository(repository.NewInMemoryWebhookRepository())
+	verificationRepo := repository.NewTracedVerificationRepository(repository.NewInMemoryVerificationRepository())
+
+	// Initialize services (business logic layer)
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Options{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseDelay:   time.Duration(cfg.WebhookRetryDelay),
+	})
+	services := app.NewServices(app.Repositories{
+		Users:         userRepo,
+		Posts:         postRepo,
+		Revisions:     revisionRepo,
+		Follows:       followRepo,
+		Webhooks:      webhookRepo,
+		Verifications: verificationRepo,
+	}, app.Options{
+		Dispatcher: dispatcher,
+		// There is no mail server yet, so verification tokens are logged
+		Mail:            service.LogEmailSender{},
+		VerificationTTL: time.Duration(cfg.EmailVerificationTTL),
+		Traced:          true,
+	})
+
+	// Load fixtures through the services so validation still applies
+	if err := seedData(context.Background(), seed.NewSeeder(services.Users, services.Posts), cfg); err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+
+	// Publish scheduled posts in the background until shutdown
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go service.NewScheduler(services.Posts, postRepo, time.Duration(cfg.SchedulerInterval)).Run(schedulerCtx)
+
+	// Relay outbox events to the webhook service (and any external publisher)
+	// and deliver webhooks in the background; stopped after in-flight requests finish
+	publisher, closePublisher := eventPublisher(cfg, services.Webhooks)
+	defer closePublisher()
+	relay := outbox.NewRelay(outboxRepo, publisher, outbox.RelayOptions{Interval: time.Duration(cfg.OutboxRelayInterval)})
+	deliveryCtx, stopDelivery := context.WithCancel(context.Background())
+	defer stopDelivery()
+	var delivering sync.WaitGroup
+	delivering.Go(func() { relay.Run(deliveryCtx) })
+	delivering.Go(func() { dispatcher.Run(deliveryCtx) })
+	delivered := make(chan struct{})
+	go func() {
+		delivering.Wait()
+		close(delivered)
+	}()
+
+	// Create GraphQL server
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	schema := services.Schema()
+	srv := newGraphQLServer(schema, cfg)
+	if cfg.Mock || len(cfg.MockFields) > 0 {
+		mocks, err := mockExtension(schema, cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		srv.Use(mocks)
+	}
+	srv.Use(metrics.New(registry))
+	srv.Use(tracing.Extension{})
+	if cfg.RequestLog {
+		srv.Use(requestlog.New(slog.New(slog.NewJSONHandler(os.Stdout, nil)), cfg.LogRedactFields))
+	}
+
+	limits, err := rateLimitStore(cfg)
+	if err != nil {
+		log.Fatalf("rate limit store: %v", err)
+	}
+	srv.Use(&ratelimit.Limiter{
+		Store:     limits,
+		Queries:   ratelimit.Limit{Points: cfg.RateLimitQueryPoints, Period: time.Duration(cfg.RateLimitPeriod)},
+		Mutations: ratelimit.Limit{Points: cfg.RateLimitMutationPoints, Period: time.Duration(cfg.RateLimitPeriod)},
+	})
+
+	queries, err := persistedQueries(cfg, persisted.NewMetrics(registry))
+	if err != nil {
+		log.Fatalf("persisted queries: %v", err)
+	}
+	srv.Use(queries)
+
+	checks := map[string]health.Checker{
+		"users":     userRepo,
+		"posts":     postRepo,
+		"revisions": revisionRepo,
+		"follows":   followRepo,
+		"webhooks":  webhookRepo,
+		"outbox":    outboxRepo,
+	}
+	if shared, ok := limits.(health.Checker); ok {
+		checks["ratelimit"] = shared
+	}
+	probes := health.NewHandler(checks)
+
+	// Tenant resolution runs after token verification so a claim can override the header
+	drainer := middleware.NewDrainer()
+	authenticate := auth.Middleware(auth.Options{
+		Secret:          []byte(cfg.JWTSecret),
+		TrustUserHeader: cfg.TrustUserHeader,
+		Admins:          cfg.AdminUsers,
+	})
+	api := func(h http.Handler) http.Handler {
+		return chain(h,
+			requestlog.Middleware,
+			tracing.Middleware,
+			middleware.CORS(cfg.AllowedOrigins),
+			middleware.MaxBodyBytes(cfg.MaxBodyBytes),
+			drainer.Middleware,
+			authenticate,
+			tenant.Middleware(tenant.Options{Default: defaultTenant}),
+		)
+	}
+
+	// REST API for integrators that cannot speak GraphQL, backed by the same services
+	restAPI := rest.NewHandler(services.Users, services.Posts)
+
+	// Setup routes
+	mux := http.NewServeMux()
+	if cfg.Playground {
+		mux.Handle("/", playground.Handler("GraphQL Playground", "/query"))
+	}
+	limitCallers := ratelimit.Middleware(ratelimit.Options{
+		APIKeys:           cfg.APIKeys,
+		TrustForwardedFor: cfg.TrustForwardedFor,
+	})
+	mux.Handle("/query", api(limitCallers(cachecontrol.Middleware(srv))))
+	mux.Handle(rest.Prefix+"/", api(restAPI))
+	mux.HandleFunc("GET "+rest.Prefix+"/openapi.json", restAPI.ServeOpenAPI)
+	// Exports and imports of the caller's tenant for datactl -server. Admins
+	// only; imports are not bounded by MaxBodyBytes.
+	mux.Handle(transfer.Path, chain(transfer.Handler(transfer.Repositories{
+		Users:     userRepo,
+		Posts:     postRepo,
+		Revisions: revisionRepo,
+		Follows:   followRepo,
+	}), requestlog.Middleware, tracing.Middleware, authenticate, tenant.Middleware(tenant.Options{Default: defaultTenant})))
+	mux.HandleFunc("/healthz", probes.Live)
+	mux.HandleFunc("/readyz", probes.Ready)
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	httpServer := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      mux,
+		ReadTimeout:  time.Duration(cfg.ReadTimeout),
+		WriteTimeout: time.Duration(cfg.WriteTimeout),
+		IdleTimeout:  time.Duration(cfg.IdleTimeout),
+	}
+
+	go func() {
+		scheme := "http"
+		if cfg.TLSEnabled() {
+			scheme = "https"
+		}
+		if cfg.Playground {
+			log.Printf("Connect to %s://localhost:%s/ for GraphQL playground", scheme, cfg.Port)
+		} else {
+			log.Printf("Serving GraphQL at %s://localhost:%s/query", scheme, cfg.Port)
+		}
+
+		var err error
+		if cfg.TLSEnabled() {
+			err = httpServer.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server failed: %v", err)
+		}
+	}()
+
+	// Graceful shutdown: stop accepting connections, let in-flight requests finish
+	// and close subscriptions before exiting
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	probes.SetDraining()
+	stopScheduler()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("HTTP shutdown: %v", err)
+	}
+	if err := drainer.Drain(ctx); err != nil {
+		log.Printf("subscription drain: %v", err)
+	}
+	stopDelivery()
+	select {
+	case <-delivered:
+	case <-ctx.Done():
+		log.Printf("event delivery: %v", ctx.Err())
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("trace flush: %v", err)
+	}
+	log.Println("Server stopped")
+}
+
+// seedData loads the configured fixture (the built-in sample by default) and
+// any synthetic records. Re-running against existing data creates nothing new.
+func seedData(ctx context.Context, seeder *seed.Seeder, cfg config.Config) error {
+	var fixtures []*seed.Fixture
+	switch cfg.SeedFile {
+	case "none":
+	case "":
+		f, err := seed.Default()
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, f)
+	default:
+		f, err := seed.LoadFile(cfg.SeedFile)
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, f)
+	}
+	if cfg.SeedSynthetic > 0 {
+		fixtures = append(fixtures, seed.Synthetic(cfg.SeedSynthetic, 5, 1))
+	}
+
+	for _, f := range fixtures {
+		res, err := seeder.Apply(ctx, f, tenant.Default)
+		if err != nil {
+			return err
+		}
+		log.Printf("Seeded %d users and %d posts (%d users and %d posts already present)",
+			res.UsersCreated, res.PostsCreated, res.UsersSkipped, res.PostsSkipped)
+	}
+	return nil
+}
+
+// eventPublisher returns where the outbox relay sends events: always the
+// in-process sinks, plus the configured external publisher
+func eventPublisher(cfg config.Config, sinks ...events.Sink) (outbox.EventPublisher, func()) {
+	local := outbox.InProcess(sinks)
+	switch cfg.EventPublisher {
+	case "http":
+		log.Printf("Publishing events to %s", cfg.EventHTTPURL)
+		return outbox.NewMulti(local, outbox.NewHTTP(cfg.EventHTTPURL)), func() {}
+	case "mqtt":
+		host, _ := os.Hostname()
+		mq := outbox.NewMQTT(cfg.EventMQTTBroker, "api-hub-"+host, cfg.EventMQTTTopic)
+		log.Printf("Publishing events to %s under %s", cfg.EventMQTTBroker, cfg.EventMQTTTopic)
+		return outbox.NewMulti(local, mq), mq.Close
+	default:
+		return local, func() {}
+	}
+}
+
+// newGraphQLServer mirrors handler.NewDefaultServer but only enables
+// introspection when configured and restricts websocket origins
+func newGraphQLServer(es graphql.ExecutableSchema, cfg config.Config) *handler.Server {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: middleware.Origins(cfg.AllowedOrigins).CheckOrigin,
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	// Must precede POST, which would otherwise claim the same JSON requests.
+	// Clients opt into @defer and @stream by sending Accept: multipart/mixed.
+	srv.AddTransport(stream.MultipartMixed{Boundary: "graphql"})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: cfg.MaxBodyBytes,
+		MaxMemory:     cfg.MaxBodyBytes,
+	})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	if cfg.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	// Works out each query's @cacheControl policy for cachecontrol.Middleware
+	srv.Use(&cachecontrol.Extension{})
+
+	return srv
+}
+
+// persistedQueries returns the APQ extension backed by the configured store or,
+// when an allowlist manifest is configured, the strict mode extension instead
+func persistedQueries(cfg config.Config, m *persisted.Metrics) (graphql.HandlerExtension, error) {
+	if cfg.QueryAllowlist != "" {
+		manifest, err := persisted.LoadManifest(cfg.QueryAllowlist)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Strict mode: only the %d operations in %s may run", manifest.Len(), cfg.QueryAllowlist)
+		return persisted.Allowlist{Store: m.Instrument("allowlist", manifest)}, nil
+	}
+
+	var store persisted.Store
+	switch cfg.PersistedQueryStore {
+	case "file":
+		fileStore, err := persisted.NewFileStore(cfg.PersistedQueryDir, cfg.PersistedQueryCacheSize)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		store = persisted.NewLRUStore(cfg.PersistedQueryCacheSize)
+	}
+	return extension.AutomaticPersistedQuery{Cache: m.Instrument(cfg.PersistedQueryStore, store)}, nil
+}
+
+// mockExtension serves generated data for every field in mock mode, or for
+// the configured fields and unimplemented resolvers otherwise
+func mockExtension(schema graphql.ExecutableSchema, cfg config.Config) (*mock.Extension, error) {
+	opts := mock.Options{Seed: cfg.MockSeed, ListSize: cfg.MockListSize, PageSizes: service.MaxPageSizes}
+	if cfg.MockFixtures != "" {
+		fixtures, err := mock.LoadFixtures(cfg.MockFixtures)
+		if err != nil {
+			return nil, err
+		}
+		opts.Fixtures = fixtures
+	}
+	gen, err := mock.NewGenerator(schema.Schema(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Mock {
+		log.Printf("Mock mode: GraphQL responses are generated (seed %d)", cfg.MockSeed)
+	} else {
+		log.Printf("Mocking %s", strings.Join(cfg.MockFields, ", "))
+	}
+	return mock.NewExtension(gen, reflect.TypeFor[graph.ResolverRoot](), cfg.Mock, cfg.MockFields)
+}
+
+// rateLimitStore returns the configured store for rate limit budgets
+func rateLimitStore(cfg config.Config) (ratelimit.Store, error) {
+	if cfg.RateLimitStore == "redis" {
+		log.Printf("Sharing rate limits through %s", cfg.RateLimitRedisURL)
+		return ratelimit.NewRedisStore(cfg.RateLimitRedisURL, "api-hub:ratelimit:")
+	}
+	return ratelimit.NewMemoryStore(), nil
+}
+
+// chain wraps h so the first middleware listed is the outermost
+func chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
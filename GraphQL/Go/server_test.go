@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/app"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/cachecontrol"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/config"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// payload is one part of a multipart/mixed response: the initial result or a
+// batch of incremental results
+type payload struct {
+	Data        map[string]any `json:"data"`
+	Errors      []any          `json:"errors"`
+	HasNext     bool           `json:"hasNext"`
+	Incremental []struct {
+		Data    map[string]any `json:"data"`
+		Items   []any          `json:"items"`
+		Label   string         `json:"label"`
+		Path    []any          `json:"path"`
+		HasNext bool           `json:"hasNext"`
+	} `json:"incremental"`
+}
+
+// newTestServer serves /query over in-memory repositories holding one author
+// with two published posts
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+
+	repos := app.NewRepositories()
+	services := app.NewServices(repos, app.Options{RenderCacheSize: 16})
+
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	if err := repos.Users.Create(ctx, &model.User{ID: "1", Name: "Alice Johnson", Email: "alice@example.com"}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	ctx = auth.WithClaims(ctx, &auth.Claims{Subject: "1"})
+	for _, title := range []string{"First", "Second"} {
+		post, err := services.Posts.CreatePost(ctx, model.NewPost{Title: title, AuthorID: "1"})
+		if err != nil {
+			t.Fatalf("create post: %v", err)
+		}
+		if _, err := services.Posts.PublishPost(ctx, post.ID); err != nil {
+			t.Fatalf("publish post: %v", err)
+		}
+	}
+
+	srv := newGraphQLServer(services.Schema(), config.Development())
+	return tenant.Middleware(tenant.Options{Default: tenant.Default})(srv)
+}
+
+// postIncremental sends query asking for multipart/mixed and returns every payload in order
+func postIncremental(t *testing.T, h http.Handler, query string) []payload {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "multipart/mixed")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, want multipart/mixed", rec.Header().Get("Content-Type"))
+	}
+
+	var payloads []payload
+	reader := multipart.NewReader(rec.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		if ct := part.Header.Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("part Content-Type = %q, want application/json", ct)
+		}
+		var p payload
+		if err := json.NewDecoder(part).Decode(&p); err != nil {
+			t.Fatalf("decode part: %v", err)
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads
+}
+
+func TestDefer_DeliversDeferredFieldsIncrementally(t *testing.T) {
+	payloads := postIncremental(t, newTestServer(t), `{
+		user(id: "1") {
+			name
+			... @defer(label: "activity") {
+				postCount
+				posts { title }
+			}
+		}
+	}`)
+
+	if len(payloads) != 2 {
+		t.Fatalf("got %d payloads, want initial + 1 incremental: %+v", len(payloads), payloads)
+	}
+
+	initial := payloads[0]
+	if !initial.HasNext {
+		t.Error("initial payload hasNext = false, want true")
+	}
+	user := initial.Data["user"].(map[string]any)
+	if user["name"] != "Alice Johnson" {
+		t.Errorf("initial name = %v, want Alice Johnson", user["name"])
+	}
+	if user["posts"] != nil || user["postCount"] != nil {
+		t.Errorf("deferred fields resolved in the initial payload: %v", user)
+	}
+
+	last := payloads[1]
+	if last.HasNext {
+		t.Error("last payload hasNext = true, want false")
+	}
+	if len(last.Incremental) != 1 {
+		t.Fatalf("got %d incremental results, want 1", len(last.Incremental))
+	}
+	inc := last.Incremental[0]
+	if inc.Label != "activity" {
+		t.Errorf("label = %q, want activity", inc.Label)
+	}
+	if len(inc.Path) != 1 || inc.Path[0] != "user" {
+		t.Errorf("path = %v, want [user]", inc.Path)
+	}
+	if inc.Data["postCount"] != float64(2) {
+		t.Errorf("postCount = %v, want 2", inc.Data["postCount"])
+	}
+	if posts := inc.Data["posts"].([]any); len(posts) != 2 {
+		t.Errorf("got %d posts, want 2", len(posts))
+	}
+}
+
+func TestDefer_AggregateFields(t *testing.T) {
+	srv := newTestServer(t)
+	// Stats are admin-only
+	asAdmin := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), &auth.Claims{Subject: "1", Role: auth.RoleAdmin})))
+	})
+	payloads := postIncremental(t, asAdmin, `{
+		stats {
+			totalUsers
+			... @defer(label: "authors") { topAuthors { postCount } }
+		}
+	}`)
+
+	if len(payloads) != 2 {
+		t.Fatalf("got %d payloads, want 2: %+v", len(payloads), payloads)
+	}
+	if stats := payloads[0].Data["stats"].(map[string]any); stats["totalUsers"] != float64(1) {
+		t.Errorf("initial totalUsers = %v, want 1", stats["totalUsers"])
+	}
+	inc := payloads[1].Incremental[0]
+	top := inc.Data["topAuthors"].([]any)
+	if inc.Label != "authors" || len(top) != 1 {
+		t.Fatalf("incremental = %+v, want label authors with one author", inc)
+	}
+	if n := top[0].(map[string]any)["postCount"]; n != float64(2) {
+		t.Errorf("topAuthors[0].postCount = %v, want 2", n)
+	}
+}
+
+func TestStream_SendsItemsAfterInitialCount(t *testing.T) {
+	payloads := postIncremental(t, newTestServer(t), `{
+		user(id: "1") {
+			name
+			posts @stream(initialCount: 1, label: "posts") { title }
+		}
+	}`)
+
+	if len(payloads) != 2 {
+		t.Fatalf("got %d payloads, want 2: %+v", len(payloads), payloads)
+	}
+	initial := payloads[0]
+	if !initial.HasNext {
+		t.Error("initial payload should announce the streamed items")
+	}
+	posts := initial.Data["user"].(map[string]any)["posts"].([]any)
+	if len(posts) != 1 {
+		t.Errorf("initial payload has %d posts, want initialCount 1", len(posts))
+	}
+
+	if payloads[1].HasNext {
+		t.Error("last payload should not have hasNext")
+	}
+	if len(payloads[1].Incremental) != 1 {
+		t.Fatalf("got %d incremental entries, want 1", len(payloads[1].Incremental))
+	}
+	streamed := payloads[1].Incremental[0]
+	if len(streamed.Items) != 1 || streamed.Label != "posts" {
+		t.Errorf("items payload = %+v, want 1 item labelled posts", streamed)
+	}
+	if want := []any{"user", "posts", float64(1)}; !reflect.DeepEqual(streamed.Path, want) {
+		t.Errorf("items path = %v, want %v", streamed.Path, want)
+	}
+}
+
+func TestStream_SplitsListInsideDeferredPayload(t *testing.T) {
+	payloads := postIncremental(t, newTestServer(t), `{
+		user(id: "1") {
+			name
+			... @defer { posts @stream(initialCount: 0) { title } }
+		}
+	}`)
+
+	if len(payloads) != 3 {
+		t.Fatalf("got %d payloads, want initial, deferred and items: %+v", len(payloads), payloads)
+	}
+	if !payloads[1].HasNext || payloads[2].HasNext {
+		t.Errorf("hasNext = %v, %v; want true, false", payloads[1].HasNext, payloads[2].HasNext)
+	}
+	if posts := payloads[1].Incremental[0].Data["posts"].([]any); len(posts) != 0 {
+		t.Errorf("deferred payload has %d posts, want none", len(posts))
+	}
+	streamed := payloads[2].Incremental[0]
+	if len(streamed.Items) != 2 {
+		t.Errorf("got %d streamed posts, want 2", len(streamed.Items))
+	}
+	if want := []any{"user", "posts", float64(0)}; !reflect.DeepEqual(streamed.Path, want) {
+		t.Errorf("items path = %v, want %v", streamed.Path, want)
+	}
+}
+
+func TestStream_DisabledSendsWholeList(t *testing.T) {
+	payloads := postIncremental(t, newTestServer(t), `{
+		user(id: "1") { posts @stream(if: false, initialCount: 1) { title } }
+	}`)
+
+	if len(payloads) != 1 {
+		t.Fatalf("got %d payloads, want 1: %+v", len(payloads), payloads)
+	}
+	posts := payloads[0].Data["user"].(map[string]any)["posts"].([]any)
+	if len(posts) != 2 {
+		t.Errorf("got %d posts, want the full list of 2", len(posts))
+	}
+}
+
+func TestStream_RejectsNonListField(t *testing.T) {
+	payloads := postIncremental(t, newTestServer(t), `{ user(id: "1") { name @stream } }`)
+
+	if len(payloads) != 1 || len(payloads[0].Errors) == 0 {
+		t.Fatalf("payloads = %+v, want a single payload with an error", payloads)
+	}
+}
+
+// getQuery sends query as a GET request through the cache middleware, signed
+// in as viewer unless it is empty
+func getQuery(t *testing.T, h http.Handler, query, viewer string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/query?query="+url.QueryEscape(query), nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if viewer != "" {
+		req = req.WithContext(auth.WithClaims(req.Context(), &auth.Claims{Subject: viewer}))
+	}
+	rec := httptest.NewRecorder()
+	cachecontrol.Middleware(h).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheControl_Headers(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		viewer string
+		want   string
+	}{
+		{"public query", `{ user(id: "1") { name } }`, "", "public, max-age=300"},
+		{"lowest maxAge wins", `{ user(id: "1") { name postCount } }`, "", "public, max-age=60"},
+		{"private field", `{ feed { edges { node { title } } } }`, "1", "private, max-age=30"},
+		{"signed-in caller", `{ user(id: "1") { name } }`, "1", "private, max-age=300"},
+		{"error response", `{ stats { totalUsers } }`, "", "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getQuery(t, srv, tt.query, tt.viewer, nil)
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q; body %s", got, tt.want, rec.Body)
+			}
+			if tt.want == "no-store" && rec.Header().Get("ETag") != "" {
+				t.Error("uncacheable response has an ETag")
+			}
+		})
+	}
+}
+
+func TestCacheControl_ConditionalGet(t *testing.T) {
+	srv := newTestServer(t)
+	const query = `{ user(id: "1") { name } }`
+
+	first := getQuery(t, srv, query, "", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first GET: status %d, ETag %q", first.Code, etag)
+	}
+
+	again := getQuery(t, srv, query, "", http.Header{"If-None-Match": {etag}})
+	if again.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", again.Code)
+	}
+	if again.Body.Len() != 0 {
+		t.Errorf("304 response has a body: %s", again.Body)
+	}
+	if again.Header().Get("ETag") != etag {
+		t.Errorf("ETag = %q, want %q", again.Header().Get("ETag"), etag)
+	}
+
+	stale := getQuery(t, srv, query, "", http.Header{"If-None-Match": {`"stale"`}})
+	if stale.Code != http.StatusOK {
+		t.Errorf("status with a stale ETag = %d, want 200", stale.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+)
+
+// Cursors are opaque to clients: a kind prefix keeps a cursor from one
+// connection being accepted by another
+
+func encodeCursor(kind, value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + ":" + value))
+}
+
+func decodeCursor(kind, cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", &ValidationError{Field: "after", Message: "malformed cursor"}
+	}
+	value, ok := strings.CutPrefix(string(raw), kind+":")
+	if !ok {
+		return "", &ValidationError{Field: "after", Message: fmt.Sprintf("cursor is not a %s cursor", kind)}
+	}
+	return value, nil
+}
+
+// Feed cursors hold the post's position (publishedAt and ID) rather than an
+// offset, so posts published while a client pages do not repeat or vanish
+
+func encodeFeedCursor(post *model.Post) string {
+	return encodeCursor("feed", fmt.Sprintf("%d:%s", post.PublishedAt.UnixNano(), post.ID))
+}
+
+func decodeFeedCursor(cursor string) (*repository.FeedPosition, error) {
+	value, err := decodeCursor("feed", cursor)
+	if err != nil {
+		return nil, err
+	}
+	nanos, id, ok := strings.Cut(value, ":")
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil || id == "" {
+		return nil, &ValidationError{Field: "after", Message: "malformed cursor"}
+	}
+	return &repository.FeedPosition{PublishedAt: time.Unix(0, n), ID: id}, nil
+}
+
+// Page size limits for list arguments
+const (
+	maxPageSize           = 100
+	maxDeliveriesPageSize = 500
+)
+
+// MaxPageSizes is the largest first or limit each list field accepts, by
+// Object.field, so the mocks can answer within the same bounds
+var MaxPageSizes = map[string]int{
+	"User.followers":          maxPageSize,
+	"User.following":          maxPageSize,
+	"Post.revisions":          maxPageSize,
+	"Stats.topAuthors":        maxPageSize,
+	"Query.feed":              maxPageSize,
+	"Query.webhookDeliveries": maxDeliveriesPageSize,
+}
+
+// pageSize validates a connection's first argument
+func pageSize(first *int32, fallback, limit int) (int, error) {
+	if first == nil {
+		return fallback, nil
+	}
+	if *first < 0 || int(*first) > limit {
+		return 0, &ValidationError{Field: "first", Message: fmt.Sprintf("first must be between 0 and %d", limit)}
+	}
+	return int(*first), nil
+}
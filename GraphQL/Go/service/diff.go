@@ -0,0 +1,142 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+)
+
+// Diffing costs O((N+M)·D) time and O(D²) space for N and M lines that
+// differ by D edits, so both are bounded: maxDiffLines counts the lines left
+// after trimming the shared prefix and suffix, maxDiffEdits the inserted plus
+// deleted lines
+const (
+	maxDiffLines = 20000
+	maxDiffEdits = 1000
+)
+
+// errDiffTooLarge is returned when revisions exceed maxDiffLines or maxDiffEdits
+func errDiffTooLarge() error {
+	return &ValidationError{Field: "to", Message: "revisions too large to diff"}
+}
+
+// lineDiff returns a line-level diff that turns a into b, computed with Myers'
+// algorithm after trimming the shared prefix and suffix
+func lineDiff(a, b string) ([]*model.DiffLine, error) {
+	x, y := splitLines(a), splitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	xm, ym := x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]
+	if len(xm)+len(ym) > maxDiffLines {
+		return nil, errDiffTooLarge()
+	}
+	middle, ok := myers(xm, ym, maxDiffEdits)
+	if !ok {
+		return nil, errDiffTooLarge()
+	}
+
+	out := make([]*model.DiffLine, 0, prefix+len(middle)+suffix)
+	for _, line := range x[:prefix] {
+		out = append(out, &model.DiffLine{Op: model.DiffOpEqual, Text: line})
+	}
+	out = append(out, middle...)
+	for _, line := range x[len(x)-suffix:] {
+		out = append(out, &model.DiffLine{Op: model.DiffOpEqual, Text: line})
+	}
+	return out, nil
+}
+
+// myers returns a shortest edit script turning x into y, or false when it
+// needs more than maxEdits insertions and deletions
+func myers(x, y []string, maxEdits int) ([]*model.DiffLine, bool) {
+	n, m := len(x), len(y)
+
+	// trace[d][k+d] is the furthest x reached on diagonal k = x-y with d edits
+	var trace [][]int
+	for d := 0; ; d++ {
+		if d > maxEdits {
+			return nil, false
+		}
+		v := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var i int
+			switch {
+			case d == 0:
+				i = 0
+			case k == -d || (k != d && trace[d-1][k-1+d-1] < trace[d-1][k+1+d-1]):
+				i = trace[d-1][k+1+d-1] // insertion: move down from diagonal k+1
+			default:
+				i = trace[d-1][k-1+d-1] + 1 // deletion: move right from diagonal k-1
+			}
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[k+d] = i
+			if i >= n && j >= m {
+				trace = append(trace, v)
+				return backtrack(x, y, trace), true
+			}
+		}
+		trace = append(trace, v)
+	}
+}
+
+// backtrack walks the trace from the end of both inputs back to the start
+func backtrack(x, y []string, trace [][]int) []*model.DiffLine {
+	var out []*model.DiffLine
+	i, j := len(x), len(y)
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := i - j
+		var pk int
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		pi := prev[pk+d-1]
+		pj := pi - pk
+		insert := pk == k+1
+
+		// The snake of equal lines that followed the edit
+		snakeStart := pi + 1
+		if insert {
+			snakeStart = pi
+		}
+		for i > snakeStart {
+			i--
+			j--
+			out = append(out, &model.DiffLine{Op: model.DiffOpEqual, Text: x[i]})
+		}
+		if insert {
+			out = append(out, &model.DiffLine{Op: model.DiffOpInsert, Text: y[pj]})
+		} else {
+			out = append(out, &model.DiffLine{Op: model.DiffOpDelete, Text: x[pi]})
+		}
+		i, j = pi, pj
+	}
+	for i > 0 {
+		i--
+		out = append(out, &model.DiffLine{Op: model.DiffOpEqual, Text: x[i]})
+	}
+	slices.Reverse(out)
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
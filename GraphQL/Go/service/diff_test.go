@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+)
+
+// apply rebuilds both sides of a diff
+func apply(diff []*model.DiffLine) (from, to string) {
+	var a, b []string
+	for _, l := range diff {
+		if l.Op != model.DiffOpInsert {
+			a = append(a, l.Text)
+		}
+		if l.Op != model.DiffOpDelete {
+			b = append(b, l.Text)
+		}
+	}
+	return strings.Join(a, "\n"), strings.Join(b, "\n")
+}
+
+func edits(diff []*model.DiffLine) int {
+	n := 0
+	for _, l := range diff {
+		if l.Op != model.DiffOpEqual {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int
+	}{
+		{"equal", "a\nb\nc", "a\nb\nc", 0},
+		{"both empty", "", "", 0},
+		{"from empty", "", "a\nb", 2},
+		{"to empty", "a\nb", "", 2},
+		{"insert in middle", "a\nc", "a\nb\nc", 1},
+		{"delete in middle", "a\nb\nc", "a\nc", 1},
+		{"replace line", "a\nb\nc", "a\nx\nc", 2},
+		{"classic", "a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+		{"reorder", "1\n2\n3\n4", "4\n3\n2\n1", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := lineDiff(tt.a, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a, b := apply(diff); a != tt.a || b != tt.b {
+				t.Fatalf("diff rebuilds %q -> %q, want %q -> %q", a, b, tt.a, tt.b)
+			}
+			if got := edits(diff); got != tt.edits {
+				t.Fatalf("%d edits, want %d", got, tt.edits)
+			}
+		})
+	}
+}
+
+func numbered(n int, format string) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(format, i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLineDiff_LargeInputs(t *testing.T) {
+	// Few edits in long revisions are cheap, wherever they are
+	a := numbered(maxDiffLines/2-1, "line %d")
+	b := "first\n" + strings.Replace(a, "line 5000\n", "", 1) + "\nlast"
+	diff, err := lineDiff(a, b)
+	if err != nil {
+		t.Fatalf("small change to a long revision: %v", err)
+	}
+	if got := edits(diff); got != 3 {
+		t.Fatalf("%d edits, want 3", got)
+	}
+
+	tooLarge := []struct {
+		name string
+		a, b string
+	}{
+		// Roughly what fits in a 1 MiB request: every line differs
+		{"too many lines", numbered(250000, "a%d"), numbered(250000, "b%d")},
+		{"too many edits", numbered(maxDiffEdits, "a%d"), numbered(maxDiffEdits, "b%d")},
+	}
+	for _, tt := range tooLarge {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			_, err := lineDiff(tt.a, tt.b)
+			var validation *ValidationError
+			if !errors.As(err, &validation) || validation.Message != "revisions too large to diff" {
+				t.Fatalf("got %v, want revisions too large to diff", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("refusing took %v", elapsed)
+			}
+		})
+	}
+}
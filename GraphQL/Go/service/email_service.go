@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// EmailService changes a user's email address once they prove they can read
+// mail sent to the new one
+type EmailService interface {
+	// RequestEmailChange sends a verification token to email for the caller,
+	// replacing any change they have pending. The address stays unchanged
+	// until VerifyEmail is called with the token.
+	RequestEmailChange(ctx context.Context, email string) (*model.EmailChangeRequest, error)
+	// VerifyEmail applies the change the token was issued for. Tokens work
+	// once and expire; whoever holds one may use it, signed in or not.
+	VerifyEmail(ctx context.Context, token string) (*model.User, error)
+}
+
+// EmailSender delivers verification tokens to the address being verified
+type EmailSender interface {
+	SendVerification(ctx context.Context, to, token string, expiresAt time.Time) error
+}
+
+// LogEmailSender writes verification tokens to the log instead of sending
+// mail. It is meant for development, where there is no mail server.
+type LogEmailSender struct{}
+
+func (LogEmailSender) SendVerification(ctx context.Context, to, token string, expiresAt time.Time) error {
+	tenantID, _ := tenant.FromContext(ctx)
+	log.Printf("email: verification token for %s in tenant %s: %s (expires %s)", to, tenantID, token, expiresAt.Format(time.RFC3339))
+	return nil
+}
+
+type emailService struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.VerificationRepository
+	sender           EmailSender
+	ttl              time.Duration
+	now              func() time.Time
+}
+
+// NewEmailService issues tokens that stay valid for ttl
+func NewEmailService(userRepo repository.UserRepository, verificationRepo repository.VerificationRepository, sender EmailSender, ttl time.Duration) EmailService {
+	return &emailService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		sender:           sender,
+		ttl:              ttl,
+		now:              time.Now,
+	}
+}
+
+func (s *emailService) RequestEmailChange(ctx context.Context, email string) (*model.EmailChangeRequest, error) {
+	viewer, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, fmt.Errorf("sign in to change your email: %w", ErrForbidden)
+	}
+	user, err := s.userRepo.GetByID(ctx, viewer)
+	if err != nil {
+		return nil, fmt.Errorf("caller %s is not a user in this tenant: %w", viewer, ErrForbidden)
+	}
+	email, err = validateEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if email == user.Email {
+		return nil, &ValidationError{Field: "email", Message: "email is unchanged"}
+	}
+	// Checked again on verification, since someone may take the address meanwhile
+	if owner, err := s.userRepo.GetByEmail(ctx, email); err == nil && owner.ID != user.ID {
+		return nil, &ValidationError{Field: "email", Message: "email is already in use"}
+	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	token := newVerificationToken()
+	expiresAt := s.now().Add(s.ttl)
+	err = s.verificationRepo.Create(ctx, &repository.EmailVerification{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: hashVerificationToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store verification: %w", err)
+	}
+	if err := s.sender.SendVerification(ctx, email, token, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return &model.EmailChangeRequest{Email: email, ExpiresAt: expiresAt}, nil
+}
+
+func (s *emailService) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	v, err := s.verificationRepo.Take(ctx, hashVerificationToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &ValidationError{Field: "token", Message: "verification token is invalid"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.now().Before(v.ExpiresAt) {
+		return nil, &ValidationError{Field: "token", Message: "verification token has expired; request a new one"}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, v.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &ValidationError{Field: "token", Message: "verification token is invalid"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Users are shared with concurrent readers, so the change goes on a copy
+	updated := *user
+	updated.Email = v.Email
+	if err := s.userRepo.Update(ctx, &updated); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, &ValidationError{Field: "email", Message: "email is already in use"}
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return &updated, nil
+}
+
+// newVerificationToken returns a random token for the user to send back
+func newVerificationToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// hashVerificationToken is what the repository stores in place of the token
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+)
+
+// ValidationError reports input that breaks a business rule.
+// Transports map it to a client error rather than a server failure.
+type ValidationError struct {
+	Field   string
+	Message string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrForbidden is returned when the caller may not act on a resource
+var ErrForbidden = errors.New("forbidden")
+
+// requireAdmin rejects callers without the admin role; action completes
+// "only admins may ..."
+func requireAdmin(ctx context.Context, action string) error {
+	if !auth.IsAdmin(ctx) {
+		return fmt.Errorf("only admins may %s: %w", action, ErrForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// newEvent describes a change for the repository to record in the outbox with
+// the write itself. The event names its tenant so it can be published after
+// the request is gone, and its ID lets consumers drop repeated deliveries.
+func newEvent(ctx context.Context, eventType string, data any) events.Event {
+	tenantID, _ := tenant.FromContext(ctx)
+	return events.Event{
+		ID:         newID(),
+		Type:       eventType,
+		Tenant:     tenantID,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+}
+
+// postEvent is the data of a post event. Subscribers are outside the author's
+// audience, so a post that is not published is described without its title
+// and content; post.published brings those once it is.
+type postEvent struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title,omitempty"`
+	Content     *string          `json:"content,omitempty"`
+	AuthorID    string           `json:"authorId"`
+	Status      model.PostStatus `json:"status"`
+	PublishedAt *time.Time       `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+func newPostEvent(post *model.Post) postEvent {
+	e := postEvent{
+		ID:          post.ID,
+		AuthorID:    post.AuthorID,
+		Status:      post.Status,
+		PublishedAt: post.PublishedAt,
+		CreatedAt:   post.CreatedAt,
+	}
+	if post.Status == model.PostStatusPublished {
+		e.Title, e.Content = post.Title, post.Content
+	}
+	return e
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var lastID atomic.Int64
+
+// newID returns a time-ordered numeric ID that is unique within the process,
+// even when many records are created in the same nanosecond (e.g. bulk seeding)
+func newID() string {
+	for {
+		last := lastID.Load()
+		next := max(time.Now().UnixNano(), last+1)
+		if lastID.CompareAndSwap(last, next) {
+			return strconv.FormatInt(next, 10)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
 )
@@ -13,56 +17,511 @@ type PostService interface {
 	GetAllPosts(ctx context.Context) ([]*model.Post, error)
 	GetPostsByUser(ctx context.Context, userID string) ([]*model.Post, error)
 	CreatePost(ctx context.Context, input model.NewPost) (*model.Post, error)
+	GetPostByID(ctx context.Context, id string) (*model.Post, error)
+	// CountPostsByUser counts the user's posts the caller can see
+	CountPostsByUser(ctx context.Context, userID string) (int, error)
+	// DeletePost removes a post. Only its author or an admin may.
 	DeletePost(ctx context.Context, id string) (*model.Post, error)
+	// ReassignPost makes another user the post's author. Admins only.
+	ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error)
+
+	// Publishing workflow; only the author may change a post's status
+	PublishPost(ctx context.Context, id string) (*model.Post, error)
+	SchedulePost(ctx context.Context, id string, publishAt time.Time) (*model.Post, error)
+	ArchivePost(ctx context.Context, id string) (*model.Post, error)
+	// PublishDuePosts publishes the tenant's scheduled posts whose time has come
+	PublishDuePosts(ctx context.Context) (int, error)
+
+	// Editing and revision history; every title or content change is stored as a revision
+	UpdatePost(ctx context.Context, id string, input model.UpdatePost) (*model.Post, error)
+	RevertPost(ctx context.Context, postID string, revision int32) (*model.Post, error)
+	ListRevisions(ctx context.Context, postID string, first *int32, after *string) (*model.PostRevisionConnection, error)
+	DiffRevisions(ctx context.Context, postID string, from, to int32) (*model.RevisionDiff, error)
+
+	// Feed pages through published posts by the users the caller follows, newest first
+	Feed(ctx context.Context, first *int32, after *string) (*model.PostConnection, error)
+}
+
+// transitions lists the statuses a post may move to from each status
+var transitions = map[model.PostStatus][]model.PostStatus{
+	model.PostStatusDraft:     {model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived},
+	model.PostStatusScheduled: {model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived},
+	model.PostStatusPublished: {model.PostStatusArchived},
+	model.PostStatusArchived:  {},
 }
 
 type postService struct {
-	postRepo repository.PostRepository
-	userRepo repository.UserRepository
+	postRepo     repository.PostRepository
+	userRepo     repository.UserRepository
+	revisionRepo repository.RevisionRepository
+	followRepo   repository.FollowRepository
+	now          func() time.Time
 }
 
-func NewPostService(postRepo repository.PostRepository, userRepo repository.UserRepository) PostService {
+func NewPostService(postRepo repository.PostRepository, userRepo repository.UserRepository, revisionRepo repository.RevisionRepository, followRepo repository.FollowRepository) PostService {
 	return &postService{
-		postRepo: postRepo,
-		userRepo: userRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		revisionRepo: revisionRepo,
+		followRepo:   followRepo,
+		now:          time.Now,
 	}
 }
 
 func (s *postService) GetAllPosts(ctx context.Context) ([]*model.Post, error) {
-	return s.postRepo.GetAll(ctx)
+	posts, err := s.postRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return visible(ctx, posts), nil
 }
 
 func (s *postService) GetPostsByUser(ctx context.Context, userID string) ([]*model.Post, error) {
-	return s.postRepo.GetByAuthorID(ctx, userID)
+	posts, err := s.postRepo.GetByAuthorID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return visible(ctx, posts), nil
+}
+
+func (s *postService) GetPostByID(ctx context.Context, id string) (*model.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Unpublished posts are reported as missing so their IDs do not leak
+	if !canSee(ctx, post) {
+		return nil, fmt.Errorf("post with id %s %w", id, repository.ErrNotFound)
+	}
+	return post, nil
+}
+
+func (s *postService) CountPostsByUser(ctx context.Context, userID string) (int, error) {
+	// Authors see their own drafts, as do admins; everyone else only what is published
+	if viewer, ok := auth.UserID(ctx); (ok && viewer == userID) || auth.IsAdmin(ctx) {
+		return s.postRepo.CountByAuthor(ctx, userID, nil)
+	}
+	published := model.PostStatusPublished
+	return s.postRepo.CountByAuthor(ctx, userID, &published)
 }
 
 func (s *postService) CreatePost(ctx context.Context, input model.NewPost) (*model.Post, error) {
-	// Business logic: verify author exists
-	author, err := s.userRepo.GetByID(ctx, input.AuthorID)
+	// Business logic: verify author exists, and keep them from being
+	// deleted until the post and its first revision are stored
+	var post *model.Post
+	found := false
+	err := s.userRepo.WithUser(ctx, input.AuthorID, func(author *model.User) error {
+		found = true
+
+		// Validate content
+		if input.Title == "" {
+			return &ValidationError{Field: "title", Message: "title is required"}
+		}
+
+		post = &model.Post{
+			ID:        newID(),
+			Title:     input.Title,
+			Content:   input.Content,
+			AuthorID:  author.ID,
+			Status:    model.PostStatusDraft,
+			CreatedAt: s.now(),
+		}
+
+		if err := s.postRepo.Create(ctx, post, newEvent(ctx, events.PostCreated, newPostEvent(post))); err != nil {
+			return fmt.Errorf("failed to create post: %w", err)
+		}
+
+		// Revision 1 is the post as created
+		if err := s.revisionRepo.Append(ctx, post.ID, &model.PostRevision{
+			Number:    1,
+			Title:     post.Title,
+			Content:   post.Content,
+			EditorID:  author.ID,
+			CreatedAt: post.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to record revision: %w", err)
+		}
+		return nil
+	})
+	if !found {
+		return nil, &ValidationError{Field: "authorId", Message: "author not found", Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func (s *postService) DeletePost(ctx context.Context, id string) (*model.Post, error) {
+	post, err := s.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Admins may remove any post; everyone else only their own
+	if !auth.IsAdmin(ctx) {
+		if err := s.requireAuthor(ctx, post); err != nil {
+			return nil, err
+		}
+	}
+	deleted, err := s.postRepo.Delete(ctx, id, newEvent(ctx, events.PostDeleted, newPostEvent(post)))
+	if err != nil {
+		return nil, err
+	}
+	// The history goes with the post, so an ID imported again later starts afresh
+	if err := s.revisionRepo.DeleteByPost(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete revisions: %w", err)
+	}
+	return deleted, nil
+}
+
+func (s *postService) ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error) {
+	if err := requireAdmin(ctx, "reassign posts"); err != nil {
+		return nil, err
+	}
+	post, err := s.postRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("author not found: %w", err)
+		return nil, err
 	}
+	// The new author cannot be deleted while the post moves to them
+	var updated *model.Post
+	found := false
+	err = s.userRepo.WithUser(ctx, authorID, func(author *model.User) error {
+		found = true
+		updated, err = s.postRepo.Modify(ctx, post.ID, func(p *model.Post) ([]events.Event, error) {
+			p.AuthorID = author.ID
+			return nil, nil
+		})
+		return err
+	})
+	if !found {
+		return nil, &ValidationError{Field: "authorId", Message: "author not found", Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+	return updated, nil
+}
 
-	// Validate content
-	if input.Title == "" {
-		return nil, fmt.Errorf("title is required")
+func (s *postService) PublishPost(ctx context.Context, id string) (*model.Post, error) {
+	return s.transition(ctx, id, model.PostStatusPublished, func(p *model.Post) {
+		now := s.now()
+		p.PublishAt = nil
+		p.PublishedAt = &now
+	})
+}
+
+func (s *postService) SchedulePost(ctx context.Context, id string, publishAt time.Time) (*model.Post, error) {
+	if !publishAt.After(s.now()) {
+		return nil, &ValidationError{Field: "publishAt", Message: "publishAt must be in the future"}
 	}
+	return s.transition(ctx, id, model.PostStatusScheduled, func(p *model.Post) {
+		p.PublishAt = &publishAt
+	})
+}
+
+func (s *postService) ArchivePost(ctx context.Context, id string) (*model.Post, error) {
+	return s.transition(ctx, id, model.PostStatusArchived, func(p *model.Post) {
+		p.PublishAt = nil
+	})
+}
 
-	post := &model.Post{
-		ID:      fmt.Sprintf("%d", time.Now().UnixNano()),
-		Title:   input.Title,
-		Content: input.Content,
-		Author:  author,
+func (s *postService) PublishDuePosts(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.postRepo.GetDueScheduled(ctx, now)
+	if err != nil {
+		return 0, err
 	}
 
-	if err := s.postRepo.Create(ctx, post); err != nil {
-		return nil, fmt.Errorf("failed to create post: %w", err)
+	published := 0
+	for _, post := range due {
+		_, err := s.postRepo.Modify(ctx, post.ID, func(p *model.Post) ([]events.Event, error) {
+			// The author may have archived or rescheduled it since it was listed
+			if p.Status != model.PostStatusScheduled || p.PublishAt == nil || p.PublishAt.After(now) {
+				return nil, fmt.Errorf("post %s %w", p.ID, repository.ErrConflict)
+			}
+			p.Status = model.PostStatusPublished
+			p.PublishedAt = p.PublishAt
+			p.PublishAt = nil
+			return []events.Event{newEvent(ctx, events.PostPublished, newPostEvent(p))}, nil
+		})
+		if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return published, fmt.Errorf("failed to publish post %s: %w", post.ID, err)
+		}
+		published++
 	}
+	return published, nil
+}
 
-	return post, nil
+func (s *postService) UpdatePost(ctx context.Context, id string, input model.UpdatePost) (*model.Post, error) {
+	if input.Title != nil && *input.Title == "" {
+		return nil, &ValidationError{Field: "title", Message: "title is required"}
+	}
+	return s.edit(ctx, id, input.Title, input.Content, nil)
 }
 
-func (s *postService) DeletePost(ctx context.Context, id string) (*model.Post, error) {
-	// Business logic: check permissions, cascade deletes, etc.
-	return s.postRepo.Delete(ctx, id)
+func (s *postService) RevertPost(ctx context.Context, postID string, revision int32) (*model.Post, error) {
+	if _, err := s.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	rev, err := s.revisionRepo.Get(ctx, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.edit(ctx, postID, &rev.Title, rev.Content, &revision)
+}
+
+func (s *postService) ListRevisions(ctx context.Context, postID string, first *int32, after *string) (*model.PostRevisionConnection, error) {
+	limit, err := pageSize(first, 10, maxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	var afterNumber int32
+	if after != nil {
+		value, err := decodeCursor("revision", *after)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, &ValidationError{Field: "after", Message: "malformed cursor"}
+		}
+		afterNumber = int32(n)
+	}
+
+	total, err := s.revisionRepo.Count(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.ListByPost(ctx, postID, afterNumber, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &model.PostRevisionConnection{
+		Edges:      make([]*model.PostRevisionEdge, 0, len(revisions)),
+		PageInfo:   &model.PageInfo{},
+		TotalCount: int32(total),
+	}
+	for _, rev := range revisions {
+		cursor := encodeCursor("revision", strconv.Itoa(int(rev.Number)))
+		conn.Edges = append(conn.Edges, &model.PostRevisionEdge{Cursor: cursor, Node: rev})
+		conn.PageInfo.EndCursor = &cursor
+	}
+	if n := len(revisions); n > 0 {
+		conn.PageInfo.HasNextPage = int(revisions[n-1].Number) < total
+	}
+	return conn, nil
+}
+
+func (s *postService) DiffRevisions(ctx context.Context, postID string, from, to int32) (*model.RevisionDiff, error) {
+	// Only callers who can see the post may read its history
+	if _, err := s.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	a, err := s.revisionRepo.Get(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.revisionRepo.Get(ctx, postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := lineDiff(a.Title, b.Title)
+	if err != nil {
+		return nil, err
+	}
+	content, err := lineDiff(deref(a.Content), deref(b.Content))
+	if err != nil {
+		return nil, err
+	}
+	return &model.RevisionDiff{
+		PostID:  postID,
+		From:    from,
+		To:      to,
+		Title:   title,
+		Content: content,
+	}, nil
+}
+
+func (s *postService) Feed(ctx context.Context, first *int32, after *string) (*model.PostConnection, error) {
+	viewer, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, fmt.Errorf("sign in to read your feed: %w", ErrForbidden)
+	}
+	limit, err := pageSize(first, 20, maxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	var position *repository.FeedPosition
+	if after != nil {
+		if position, err = decodeFeedCursor(*after); err != nil {
+			return nil, err
+		}
+	}
+
+	followed, err := s.followRepo.FollowingIDs(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	// One extra post tells us whether another page exists
+	posts, err := s.postRepo.GetPublishedByAuthors(ctx, followed, position, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &model.PostConnection{
+		Edges:    make([]*model.PostEdge, 0, min(limit, len(posts))),
+		PageInfo: &model.PageInfo{HasNextPage: len(posts) > limit},
+	}
+	for _, post := range posts[:min(limit, len(posts))] {
+		cursor := encodeFeedCursor(post)
+		conn.Edges = append(conn.Edges, &model.PostEdge{Cursor: cursor, Node: post})
+		conn.PageInfo.EndCursor = &cursor
+	}
+	return conn, nil
+}
+
+// edit applies a title/content change as the author and records it as a new
+// revision. Omitted (nil) fields keep their value, except on revert where a nil
+// content means the reverted revision had none. The revision is appended while
+// the post is locked, so the history and the stored post change together and
+// concurrent edits each build on the other's result.
+func (s *postService) edit(ctx context.Context, id string, title, content *string, revertedFrom *int32) (*model.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAuthor(ctx, post); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.postRepo.Modify(ctx, id, func(p *model.Post) ([]events.Event, error) {
+		if err := s.requireAuthor(ctx, p); err != nil {
+			return nil, err
+		}
+		before := *p
+		if title != nil {
+			p.Title = *title
+		}
+		if content != nil || revertedFrom != nil {
+			p.Content = content
+		}
+		if p.Title == before.Title && sameContent(p.Content, before.Content) {
+			return nil, errUnchanged
+		}
+
+		latest, err := s.revisionRepo.Count(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.revisionRepo.Append(ctx, id, &model.PostRevision{
+			Number:       int32(latest + 1),
+			Title:        p.Title,
+			Content:      p.Content,
+			EditorID:     p.AuthorID,
+			CreatedAt:    s.now(),
+			RevertedFrom: revertedFrom,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to record revision: %w", err)
+		}
+		return nil, nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return s.postRepo.GetByID(ctx, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+	return updated, nil
+}
+
+// errUnchanged aborts a Modify that would store the post as it already is
+var errUnchanged = errors.New("post unchanged")
+
+// requireAuthor checks that the calling user wrote post
+func (s *postService) requireAuthor(ctx context.Context, post *model.Post) error {
+	viewer, ok := auth.UserID(ctx)
+	if !ok || post.AuthorID != viewer {
+		return fmt.Errorf("only the author may change post %s: %w", post.ID, ErrForbidden)
+	}
+	return nil
+}
+
+func sameContent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// transition moves a post to status after checking that the caller is the author
+// and that the move is legal. apply sets the timestamps on a copy of the post.
+// If the post's status changes between the check and the write, nothing is
+// written and the error wraps repository.ErrConflict.
+func (s *postService) transition(ctx context.Context, id string, to model.PostStatus, apply func(*model.Post)) (*model.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAuthor(ctx, post); err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, next := range transitions[post.Status] {
+		allowed = allowed || next == to
+	}
+	if !allowed {
+		return nil, &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("cannot move post %s from %s to %s", id, post.Status, to),
+		}
+	}
+
+	updated, err := s.postRepo.Modify(ctx, id, func(p *model.Post) ([]events.Event, error) {
+		if p.Status != post.Status || p.AuthorID != post.AuthorID {
+			return nil, fmt.Errorf("post %s %w", id, repository.ErrConflict)
+		}
+		p.Status = to
+		apply(p)
+		if to == model.PostStatusPublished {
+			return []events.Event{newEvent(ctx, events.PostPublished, newPostEvent(p))}, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+	return updated, nil
+}
+
+// canSee reports whether the caller may read post: published posts are public,
+// every other status is visible to its author and to admins
+func canSee(ctx context.Context, post *model.Post) bool {
+	if post.Status == model.PostStatusPublished || auth.IsAdmin(ctx) {
+		return true
+	}
+	viewer, ok := auth.UserID(ctx)
+	return ok && post.AuthorID == viewer
+}
+
+func visible(ctx context.Context, posts []*model.Post) []*model.Post {
+	out := posts[:0:0]
+	for _, post := range posts {
+		if canSee(ctx, post) {
+			out = append(out, post)
+		}
+	}
+	return out
 }
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestPostService_CreatePostRejectsAuthorFromAnotherTenant(t *testing.T) {
+	userRepo := repository.NewInMemoryUserRepository()
+	postRepo := repository.NewInMemoryPostRepository()
+	follows := repository.NewInMemoryFollowRepository()
+	posts := NewPostService(postRepo, userRepo, repository.NewInMemoryRevisionRepository(), follows)
+	users := NewUserService(userRepo, postRepo, repository.NewInMemoryRevisionRepository(), follows)
+
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+
+	author, err := users.CreateUser(acme, model.NewUser{Name: "Wile", Email: "wile@acme.test"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	if _, err := posts.CreatePost(globex, model.NewPost{Title: "Hijack", AuthorID: author.ID}); err == nil {
+		t.Fatal("globex created a post authored by an acme user")
+	}
+
+	if _, err := posts.CreatePost(acme, model.NewPost{Title: "Anvils", AuthorID: author.ID}); err != nil {
+		t.Fatalf("acme could not post as its own user: %v", err)
+	}
+}
+
+// racingPosts changes a post right after the service reads it, as a
+// concurrent request would
+type racingPosts struct {
+	repository.PostRepository
+	race func(ctx context.Context, post *model.Post)
+}
+
+func (r *racingPosts) GetByID(ctx context.Context, id string) (*model.Post, error) {
+	post, err := r.PostRepository.GetByID(ctx, id)
+	if err == nil && r.race != nil {
+		race := r.race
+		r.race = nil
+		race(ctx, post)
+	}
+	return post, err
+}
+
+func newRacingService(t *testing.T) (context.Context, *racingPosts, PostService, *model.Post) {
+	t.Helper()
+	userRepo := repository.NewInMemoryUserRepository()
+	postRepo := &racingPosts{PostRepository: repository.NewInMemoryPostRepository()}
+	posts := NewPostService(postRepo, userRepo, repository.NewInMemoryRevisionRepository(), repository.NewInMemoryFollowRepository())
+
+	ctx := tenant.WithID(context.Background(), "acme")
+	if err := userRepo.Create(ctx, &model.User{ID: "u1", Name: "Wile", Email: "wile@acme.test"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx = auth.WithClaims(ctx, &auth.Claims{Subject: "u1"})
+	post, err := posts.CreatePost(ctx, model.NewPost{Title: "Anvils", AuthorID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx, postRepo, posts, post
+}
+
+func TestPostService_TransitionConflictsWithConcurrentChange(t *testing.T) {
+	ctx, repo, posts, post := newRacingService(t)
+	repo.race = func(ctx context.Context, p *model.Post) {
+		archived := *p
+		archived.Status = model.PostStatusArchived
+		if err := repo.Update(ctx, &archived); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := posts.PublishPost(ctx, post.ID); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("publish = %v, want a conflict", err)
+	}
+	if got, _ := repo.GetByID(ctx, post.ID); got.Status != model.PostStatusArchived {
+		t.Fatalf("status = %s, want the concurrent ARCHIVED kept", got.Status)
+	}
+}
+
+func TestPostService_EditKeepsConcurrentEdit(t *testing.T) {
+	ctx, repo, posts, post := newRacingService(t)
+	repo.race = func(ctx context.Context, p *model.Post) {
+		title := "Anvils, revised"
+		if _, err := posts.UpdatePost(ctx, p.ID, model.UpdatePost{Title: &title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content := "Heavy."
+	updated, err := posts.UpdatePost(ctx, post.ID, model.UpdatePost{Content: &content})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != "Anvils, revised" || deref(updated.Content) != "Heavy." {
+		t.Fatalf("post = %q %q, want both edits", updated.Title, deref(updated.Content))
+	}
+
+	// Each edit is a revision and the latest matches the stored post
+	conn, err := posts.ListRevisions(ctx, post.ID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.TotalCount != 3 {
+		t.Fatalf("%d revisions, want 3", conn.TotalCount)
+	}
+	latest := conn.Edges[len(conn.Edges)-1].Node
+	if latest.Title != updated.Title || !sameContent(latest.Content, updated.Content) {
+		t.Fatalf("latest revision %q %q differs from the post", latest.Title, deref(latest.Content))
+	}
+}
+
+func TestPostService_PublishDuePostsSkipsChangedPosts(t *testing.T) {
+	ctx, repo, posts, post := newRacingService(t)
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	posts.(*postService).now = func() time.Time { return start }
+	if _, err := posts.SchedulePost(ctx, post.ID, start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// The author archives the post while the scheduler is publishing it
+	repo.PostRepository = &archiveOnScan{PostRepository: repo.PostRepository}
+
+	posts.(*postService).now = func() time.Time { return start.Add(2 * time.Hour) }
+	n, err := posts.PublishDuePosts(ctx)
+	if err != nil || n != 0 {
+		t.Fatalf("published %d, %v; want 0 and no error", n, err)
+	}
+	if got, _ := repo.GetByID(ctx, post.ID); got.Status != model.PostStatusArchived {
+		t.Fatalf("status = %s, want ARCHIVED", got.Status)
+	}
+}
+
+// archiveOnScan archives each due post after listing it
+type archiveOnScan struct {
+	repository.PostRepository
+}
+
+func (r *archiveOnScan) GetDueScheduled(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	due, err := r.PostRepository.GetDueScheduled(ctx, now)
+	for _, p := range due {
+		archived := *p
+		archived.Status = model.PostStatusArchived
+		archived.PublishAt = nil
+		if err := r.Update(ctx, &archived); err != nil {
+			return nil, err
+		}
+	}
+	return due, err
+}
+
+func TestPostService_DeletePostDeletesRevisions(t *testing.T) {
+	ctx, _, posts, post := newRacingService(t)
+	title := "Anvils, revised"
+	if _, err := posts.UpdatePost(ctx, post.ID, model.UpdatePost{Title: &title}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := posts.DeletePost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions := posts.(*postService).revisionRepo
+	if n, err := revisions.Count(ctx, post.ID); err != nil || n != 0 {
+		t.Fatalf("%d revisions left (%v), want none", n, err)
+	}
+}
+
+func TestPostService_CreatePostRacesDeleteUser(t *testing.T) {
+	userRepo := repository.NewInMemoryUserRepository()
+	postRepo := repository.NewInMemoryPostRepository()
+	revisionRepo := repository.NewInMemoryRevisionRepository()
+	follows := repository.NewInMemoryFollowRepository()
+	posts := NewPostService(postRepo, userRepo, revisionRepo, follows)
+	users := NewUserService(userRepo, postRepo, revisionRepo, follows)
+	ctx := auth.WithClaims(tenant.WithID(context.Background(), "acme"), &auth.Claims{Subject: "admin", Role: auth.RoleAdmin})
+
+	// Either the post lands first and the delete is refused, or the
+	// author is gone before the post is stored; never both
+	for i := range 50 {
+		user, err := users.CreateUser(ctx, model.NewUser{Name: "Wile", Email: fmt.Sprintf("wile%d@acme.test", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var createErr, deleteErr error
+		var wg sync.WaitGroup
+		wg.Go(func() { _, createErr = posts.CreatePost(ctx, model.NewPost{Title: "Anvils", AuthorID: user.ID}) })
+		wg.Go(func() { _, deleteErr = users.DeleteUser(ctx, user.ID) })
+		wg.Wait()
+
+		if (createErr == nil) == (deleteErr == nil) {
+			t.Fatalf("create: %v, delete: %v; want exactly one to succeed", createErr, deleteErr)
+		}
+	}
+}
+
+func TestPostService_EventsHideDrafts(t *testing.T) {
+	userRepo := repository.NewInMemoryUserRepository()
+	postRepo := repository.NewInMemoryPostRepository()
+	outbox := repository.NewInMemoryOutboxRepository()
+	postRepo.UseOutbox(outbox)
+	posts := NewPostService(postRepo, userRepo, repository.NewInMemoryRevisionRepository(), repository.NewInMemoryFollowRepository())
+
+	ctx := tenant.WithID(context.Background(), "acme")
+	if err := userRepo.Create(ctx, &model.User{ID: "u1", Name: "Wile", Email: "wile@acme.test"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx = auth.WithClaims(ctx, &auth.Claims{Subject: "u1"})
+	content := "Secret plans."
+	post, err := posts.CreatePost(ctx, model.NewPost{Title: "Anvils", Content: &content, AuthorID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := posts.PublishPost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := outbox.Pending(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("%d events, want post.created and post.published", len(entries))
+	}
+	created, published := entries[0].Event, entries[1].Event
+	if created.Type != events.PostCreated || published.Type != events.PostPublished {
+		t.Fatalf("events = %s, %s", created.Type, published.Type)
+	}
+	if data := string(created.Data.(json.RawMessage)); strings.Contains(data, "Anvils") || strings.Contains(data, "Secret") {
+		t.Errorf("post.created carries the draft: %s", data)
+	}
+	if data := string(published.Data.(json.RawMessage)); !strings.Contains(data, "Anvils") || !strings.Contains(data, "Secret plans.") {
+		t.Errorf("post.published lacks the post: %s", data)
+	}
+}
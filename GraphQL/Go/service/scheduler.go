@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Scheduler publishes SCHEDULED posts once their publishAt time has passed
+type Scheduler struct {
+	posts    PostService
+	tenants  repository.PostRepository
+	interval time.Duration
+}
+
+// NewScheduler creates a scheduler that checks every tenant once per interval
+func NewScheduler(posts PostService, postRepo repository.PostRepository, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		posts:    posts,
+		tenants:  postRepo,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, publishing due posts on every tick
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	tenants, err := s.tenants.Tenants(ctx)
+	if err != nil {
+		log.Printf("scheduler: list tenants: %v", err)
+		return
+	}
+
+	for _, id := range tenants {
+		n, err := s.posts.PublishDuePosts(tenant.WithID(ctx, id))
+		if err != nil {
+			log.Printf("scheduler: tenant %s: %v", id, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("scheduler: published %d scheduled posts in tenant %s", n, id)
+		}
+	}
+}
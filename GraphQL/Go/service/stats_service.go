@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+)
+
+// maxStatsDays bounds postsPerDay so one query cannot ask for an unbounded series
+const maxStatsDays = 366
+
+// StatsService computes tenant-wide aggregates for dashboards. The counting is
+// delegated to repository aggregates; nothing here loads whole collections.
+// The aggregates include drafts, so only admins may read them.
+type StatsService interface {
+	// Stats returns the object the aggregates hang off, once the caller is known to be an admin
+	Stats(ctx context.Context) (*model.Stats, error)
+	CountUsers(ctx context.Context) (int, error)
+	CountPosts(ctx context.Context) (int, error)
+	// PostsPerDay returns one entry per UTC day in [from, to), including days
+	// without posts. from is rounded down to midnight UTC, so every entry covers a whole day.
+	PostsPerDay(ctx context.Context, from, to time.Time) ([]*model.DailyPostCount, error)
+	TopAuthors(ctx context.Context, limit *int32) ([]*model.AuthorPostCount, error)
+}
+
+type statsService struct {
+	userRepo repository.UserRepository
+	postRepo repository.PostRepository
+}
+
+func NewStatsService(userRepo repository.UserRepository, postRepo repository.PostRepository) StatsService {
+	return &statsService{
+		userRepo: userRepo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *statsService) Stats(ctx context.Context) (*model.Stats, error) {
+	if err := requireAdmin(ctx, "view stats"); err != nil {
+		return nil, err
+	}
+	// Every Stats field has its own resolver, so unselected aggregates are never computed
+	return &model.Stats{}, nil
+}
+
+func (s *statsService) CountUsers(ctx context.Context) (int, error) {
+	if err := requireAdmin(ctx, "view stats"); err != nil {
+		return 0, err
+	}
+	return s.userRepo.Count(ctx)
+}
+
+func (s *statsService) CountPosts(ctx context.Context) (int, error) {
+	if err := requireAdmin(ctx, "view stats"); err != nil {
+		return 0, err
+	}
+	return s.postRepo.Count(ctx)
+}
+
+func (s *statsService) PostsPerDay(ctx context.Context, from, to time.Time) ([]*model.DailyPostCount, error) {
+	if err := requireAdmin(ctx, "view stats"); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, &ValidationError{Field: "to", Message: "to must be after from"}
+	}
+	if to.Sub(from) > maxStatsDays*24*time.Hour {
+		return nil, &ValidationError{Field: "to", Message: fmt.Sprintf("range may span at most %d days", maxStatsDays)}
+	}
+
+	// Buckets are whole UTC days, so a from partway through a day still counts
+	// that day's earlier posts rather than under-reporting the first bucket
+	from = from.UTC().Truncate(24 * time.Hour)
+	counts, err := s.postRepo.CountPerDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[time.Time]int, len(counts))
+	for _, c := range counts {
+		byDay[c.Day] = c.Count
+	}
+
+	// The store omits empty days; charts want a continuous series
+	var series []*model.DailyPostCount
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		series = append(series, &model.DailyPostCount{
+			Date:  day.Format(time.DateOnly),
+			Count: int32(byDay[day]),
+		})
+	}
+	return series, nil
+}
+
+func (s *statsService) TopAuthors(ctx context.Context, limit *int32) ([]*model.AuthorPostCount, error) {
+	if err := requireAdmin(ctx, "view stats"); err != nil {
+		return nil, err
+	}
+	n := 5
+	if limit != nil {
+		if *limit < 1 || *limit > maxPageSize {
+			return nil, &ValidationError{Field: "limit", Message: fmt.Sprintf("limit must be between 1 and %d", maxPageSize)}
+		}
+		n = int(*limit)
+	}
+	counts, err := s.postRepo.TopAuthors(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	top := make([]*model.AuthorPostCount, 0, len(counts))
+	for _, c := range counts {
+		author, err := s.userRepo.GetByID(ctx, c.AuthorID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue // posts outlived their author
+		}
+		if err != nil {
+			return nil, err
+		}
+		top = append(top, &model.AuthorPostCount{Author: author, PostCount: int32(c.Count)})
+	}
+	return top, nil
+}
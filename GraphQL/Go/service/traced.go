@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/Krushnal121/API-Hub/GraphQL/Go/service")
+
+// startSpan opens a child span for a service call
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span before ending it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedUserService wraps a UserService with a span per call
+type tracedUserService struct {
+	next UserService
+}
+
+// NewTracedUserService decorates a UserService with OpenTelemetry spans
+func NewTracedUserService(next UserService) UserService {
+	return &tracedUserService{next: next}
+}
+
+func (s *tracedUserService) GetAllUsers(ctx context.Context) ([]*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.GetAllUsers")
+	users, err := s.next.GetAllUsers(ctx)
+	span.SetAttributes(attribute.Int("user.count", len(users)))
+	endSpan(span, err)
+	return users, err
+}
+
+func (s *tracedUserService) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.GetUserByID", attribute.String("user.id", id))
+	user, err := s.next.GetUserByID(ctx, id)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.GetUserByEmail")
+	user, err := s.next.GetUserByEmail(ctx, email)
+	if user != nil {
+		span.SetAttributes(attribute.String("user.id", user.ID))
+	}
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.CreateUser")
+	user, err := s.next.CreateUser(ctx, input)
+	if user != nil {
+		span.SetAttributes(attribute.String("user.id", user.ID))
+	}
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) DeleteUser(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.DeleteUser", attribute.String("user.id", id))
+	user, err := s.next.DeleteUser(ctx, id)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) Follow(ctx context.Context, userID string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.Follow", attribute.String("user.id", userID))
+	user, err := s.next.Follow(ctx, userID)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) Unfollow(ctx context.Context, userID string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "UserService.Unfollow", attribute.String("user.id", userID))
+	user, err := s.next.Unfollow(ctx, userID)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) ListFollowers(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error) {
+	ctx, span := startSpan(ctx, "UserService.ListFollowers", attribute.String("user.id", userID))
+	conn, err := s.next.ListFollowers(ctx, userID, first, after)
+	endSpan(span, err)
+	return conn, err
+}
+
+func (s *tracedUserService) ListFollowing(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error) {
+	ctx, span := startSpan(ctx, "UserService.ListFollowing", attribute.String("user.id", userID))
+	conn, err := s.next.ListFollowing(ctx, userID, first, after)
+	endSpan(span, err)
+	return conn, err
+}
+
+// tracedPostService wraps a PostService with a span per call
+type tracedPostService struct {
+	next PostService
+}
+
+// NewTracedPostService decorates a PostService with OpenTelemetry spans
+func NewTracedPostService(next PostService) PostService {
+	return &tracedPostService{next: next}
+}
+
+func (s *tracedPostService) GetAllPosts(ctx context.Context) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.GetAllPosts")
+	posts, err := s.next.GetAllPosts(ctx)
+	span.SetAttributes(attribute.Int("post.count", len(posts)))
+	endSpan(span, err)
+	return posts, err
+}
+
+func (s *tracedPostService) GetPostsByUser(ctx context.Context, userID string) ([]*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.GetPostsByUser", attribute.String("user.id", userID))
+	posts, err := s.next.GetPostsByUser(ctx, userID)
+	endSpan(span, err)
+	return posts, err
+}
+
+func (s *tracedPostService) GetPostByID(ctx context.Context, id string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.GetPostByID", attribute.String("post.id", id))
+	post, err := s.next.GetPostByID(ctx, id)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) CountPostsByUser(ctx context.Context, userID string) (int, error) {
+	ctx, span := startSpan(ctx, "PostService.CountPostsByUser", attribute.String("user.id", userID))
+	n, err := s.next.CountPostsByUser(ctx, userID)
+	endSpan(span, err)
+	return n, err
+}
+
+func (s *tracedPostService) CreatePost(ctx context.Context, input model.NewPost) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.CreatePost", attribute.String("author.id", input.AuthorID))
+	post, err := s.next.CreatePost(ctx, input)
+	if post != nil {
+		span.SetAttributes(attribute.String("post.id", post.ID))
+	}
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) DeletePost(ctx context.Context, id string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.DeletePost", attribute.String("post.id", id))
+	post, err := s.next.DeletePost(ctx, id)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) ReassignPost(ctx context.Context, id, authorID string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.ReassignPost", attribute.String("post.id", id), attribute.String("author.id", authorID))
+	post, err := s.next.ReassignPost(ctx, id, authorID)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) PublishPost(ctx context.Context, id string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.PublishPost", attribute.String("post.id", id))
+	post, err := s.next.PublishPost(ctx, id)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) SchedulePost(ctx context.Context, id string, publishAt time.Time) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.SchedulePost",
+		attribute.String("post.id", id),
+		attribute.String("post.publish_at", publishAt.Format(time.RFC3339)),
+	)
+	post, err := s.next.SchedulePost(ctx, id, publishAt)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) ArchivePost(ctx context.Context, id string) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.ArchivePost", attribute.String("post.id", id))
+	post, err := s.next.ArchivePost(ctx, id)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) PublishDuePosts(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "PostService.PublishDuePosts")
+	n, err := s.next.PublishDuePosts(ctx)
+	span.SetAttributes(attribute.Int("post.published", n))
+	endSpan(span, err)
+	return n, err
+}
+
+func (s *tracedPostService) UpdatePost(ctx context.Context, id string, input model.UpdatePost) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.UpdatePost", attribute.String("post.id", id))
+	post, err := s.next.UpdatePost(ctx, id, input)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) RevertPost(ctx context.Context, postID string, revision int32) (*model.Post, error) {
+	ctx, span := startSpan(ctx, "PostService.RevertPost",
+		attribute.String("post.id", postID),
+		attribute.Int("revision.number", int(revision)),
+	)
+	post, err := s.next.RevertPost(ctx, postID, revision)
+	endSpan(span, err)
+	return post, err
+}
+
+func (s *tracedPostService) ListRevisions(ctx context.Context, postID string, first *int32, after *string) (*model.PostRevisionConnection, error) {
+	ctx, span := startSpan(ctx, "PostService.ListRevisions", attribute.String("post.id", postID))
+	conn, err := s.next.ListRevisions(ctx, postID, first, after)
+	endSpan(span, err)
+	return conn, err
+}
+
+func (s *tracedPostService) DiffRevisions(ctx context.Context, postID string, from, to int32) (*model.RevisionDiff, error) {
+	ctx, span := startSpan(ctx, "PostService.DiffRevisions",
+		attribute.String("post.id", postID),
+		attribute.Int("revision.from", int(from)),
+		attribute.Int("revision.to", int(to)),
+	)
+	diff, err := s.next.DiffRevisions(ctx, postID, from, to)
+	endSpan(span, err)
+	return diff, err
+}
+
+func (s *tracedPostService) Feed(ctx context.Context, first *int32, after *string) (*model.PostConnection, error) {
+	ctx, span := startSpan(ctx, "PostService.Feed")
+	conn, err := s.next.Feed(ctx, first, after)
+	if conn != nil {
+		span.SetAttributes(attribute.Int("post.count", len(conn.Edges)))
+	}
+	endSpan(span, err)
+	return conn, err
+}
+
+// tracedStatsService wraps a StatsService with a span per call
+type tracedStatsService struct {
+	next StatsService
+}
+
+// NewTracedStatsService decorates a StatsService with OpenTelemetry spans
+func NewTracedStatsService(next StatsService) StatsService {
+	return &tracedStatsService{next: next}
+}
+
+// Stats only checks the caller's role, so it gets no span of its own
+func (s *tracedStatsService) Stats(ctx context.Context) (*model.Stats, error) {
+	return s.next.Stats(ctx)
+}
+
+func (s *tracedStatsService) CountUsers(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "StatsService.CountUsers")
+	n, err := s.next.CountUsers(ctx)
+	endSpan(span, err)
+	return n, err
+}
+
+func (s *tracedStatsService) CountPosts(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "StatsService.CountPosts")
+	n, err := s.next.CountPosts(ctx)
+	endSpan(span, err)
+	return n, err
+}
+
+func (s *tracedStatsService) PostsPerDay(ctx context.Context, from, to time.Time) ([]*model.DailyPostCount, error) {
+	ctx, span := startSpan(ctx, "StatsService.PostsPerDay",
+		attribute.String("range.from", from.Format(time.RFC3339)),
+		attribute.String("range.to", to.Format(time.RFC3339)),
+	)
+	series, err := s.next.PostsPerDay(ctx, from, to)
+	endSpan(span, err)
+	return series, err
+}
+
+func (s *tracedStatsService) TopAuthors(ctx context.Context, limit *int32) ([]*model.AuthorPostCount, error) {
+	ctx, span := startSpan(ctx, "StatsService.TopAuthors")
+	top, err := s.next.TopAuthors(ctx, limit)
+	endSpan(span, err)
+	return top, err
+}
+
+// tracedWebhookService wraps a WebhookService with a span per call
+type tracedWebhookService struct {
+	next WebhookService
+}
+
+// NewTracedWebhookService decorates a WebhookService with OpenTelemetry spans
+func NewTracedWebhookService(next WebhookService) WebhookService {
+	return &tracedWebhookService{next: next}
+}
+
+func (s *tracedWebhookService) Emit(ctx context.Context, event events.Event) {
+	ctx, span := startSpan(ctx, "WebhookService.Emit",
+		attribute.String("event.id", event.ID),
+		attribute.String("event.type", event.Type),
+	)
+	s.next.Emit(ctx, event)
+	endSpan(span, nil)
+}
+
+func (s *tracedWebhookService) ListWebhooks(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.ListWebhooks")
+	hooks, err := s.next.ListWebhooks(ctx)
+	endSpan(span, err)
+	return hooks, err
+}
+
+func (s *tracedWebhookService) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.CreatedWebhook, error) {
+	ctx, span := startSpan(ctx, "WebhookService.CreateWebhook")
+	created, err := s.next.CreateWebhook(ctx, input)
+	if created != nil {
+		span.SetAttributes(attribute.String("webhook.id", created.Subscription.ID))
+	}
+	endSpan(span, err)
+	return created, err
+}
+
+func (s *tracedWebhookService) DeleteWebhook(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.DeleteWebhook", attribute.String("webhook.id", id))
+	hook, err := s.next.DeleteWebhook(ctx, id)
+	endSpan(span, err)
+	return hook, err
+}
+
+func (s *tracedWebhookService) ListDeliveries(ctx context.Context, subscriptionID string, first *int32) ([]*model.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookService.ListDeliveries", attribute.String("webhook.id", subscriptionID))
+	deliveries, err := s.next.ListDeliveries(ctx, subscriptionID, first)
+	endSpan(span, err)
+	return deliveries, err
+}
+
+func (s *tracedWebhookService) ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error) {
+	ctx, span := startSpan(ctx, "WebhookService.ListDeadLetters")
+	letters, err := s.next.ListDeadLetters(ctx)
+	endSpan(span, err)
+	return letters, err
+}
+
+func (s *tracedWebhookService) RedeliverDeadLetter(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "WebhookService.RedeliverDeadLetter", attribute.String("dead_letter.id", id))
+	err := s.next.RedeliverDeadLetter(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// tracedEmailService wraps an EmailService with a span per call
+type tracedEmailService struct {
+	next EmailService
+}
+
+// NewTracedEmailService decorates an EmailService with OpenTelemetry spans
+func NewTracedEmailService(next EmailService) EmailService {
+	return &tracedEmailService{next: next}
+}
+
+func (s *tracedEmailService) RequestEmailChange(ctx context.Context, email string) (*model.EmailChangeRequest, error) {
+	ctx, span := startSpan(ctx, "EmailService.RequestEmailChange")
+	req, err := s.next.RequestEmailChange(ctx, email)
+	endSpan(span, err)
+	return req, err
+}
+
+func (s *tracedEmailService) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	ctx, span := startSpan(ctx, "EmailService.VerifyEmail")
+	user, err := s.next.VerifyEmail(ctx, token)
+	if user != nil {
+		span.SetAttributes(attribute.String("user.id", user.ID))
+	}
+	endSpan(span, err)
+	return user, err
+}
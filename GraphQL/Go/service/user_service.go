@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"net/mail"
+	"strconv"
+	"strings"
 
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
 	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
 )
@@ -13,17 +18,35 @@ import (
 type UserService interface {
 	GetAllUsers(ctx context.Context) ([]*model.User, error)
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	// GetUserByEmail finds a user by email regardless of case; nil if there is none
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	// CreateUser rejects an email another user in the tenant already has
 	CreateUser(ctx context.Context, input model.NewUser) (*model.User, error)
+	// DeleteUser removes a user who has no posts left and is named on no
+	// revision, with their follow edges. Admins only.
+	DeleteUser(ctx context.Context, id string) (*model.User, error)
+
+	// Follow graph; the caller is always the follower
+	Follow(ctx context.Context, userID string) (*model.User, error)
+	Unfollow(ctx context.Context, userID string) (*model.User, error)
+	ListFollowers(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error)
+	ListFollowing(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo     repository.UserRepository
+	postRepo     repository.PostRepository
+	revisionRepo repository.RevisionRepository
+	followRepo   repository.FollowRepository
 }
 
 // NewUserService creates a new user service with dependency injection
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, postRepo repository.PostRepository, revisionRepo repository.RevisionRepository, followRepo repository.FollowRepository) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		postRepo:     postRepo,
+		revisionRepo: revisionRepo,
+		followRepo:   followRepo,
 	}
 }
 
@@ -41,23 +64,231 @@ func (s *userService) GetUserByID(ctx context.Context, id string) (*model.User,
 	return user, nil
 }
 
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
 func (s *userService) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
-	// Business logic: validate email, check duplicates, etc.
-	if input.Email == "" {
-		return nil, fmt.Errorf("email is required")
+	email, err := validateEmail(input.Email)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate unique ID (in production, use UUID)
 	user := &model.User{
-		ID:    fmt.Sprintf("%d", time.Now().UnixNano()),
+		ID:    newID(),
 		Name:  input.Name,
-		Email: input.Email,
-		Posts: []*model.Post{},
+		Email: email,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	// The repository's email index decides duplicates, so concurrent signups cannot both win
+	if err := s.userRepo.Create(ctx, user, newEvent(ctx, events.UserCreated, user)); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, &ValidationError{Field: "email", Message: "email is already in use"}
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return user, nil
 }
+
+func (s *userService) DeleteUser(ctx context.Context, id string) (*model.User, error) {
+	if err := requireAdmin(ctx, "delete users"); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posts keep their author and revisions their editor, so both have to go
+	// (or move) first. The checks run inside Delete, so a post created for
+	// the user meanwhile either lands before them or fails to find the user.
+	err = s.userRepo.Delete(ctx, id, func() error {
+		n, err := s.postRepo.CountByAuthor(ctx, id, nil)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &ValidationError{
+				Field:   "id",
+				Message: fmt.Sprintf("user %s still has %d posts; reassign or delete them first", id, n),
+			}
+		}
+		n, err = s.revisionRepo.CountByEditor(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &ValidationError{
+				Field:   "id",
+				Message: fmt.Sprintf("user %s is the editor of %d revisions; delete those posts first", id, n),
+			}
+		}
+		if err := s.removeFollows(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove follows: %w", err)
+		}
+		return nil
+	})
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete user: %w", err)
+	}
+	return user, nil
+}
+
+// validateEmail trims email and checks it is a bare address, without a
+// display name or angle brackets
+func validateEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", &ValidationError{Field: "email", Message: "email is required"}
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", &ValidationError{Field: "email", Message: "email is not a valid address"}
+	}
+	return email, nil
+}
+
+// removeFollows deletes every edge into and out of userID
+func (s *userService) removeFollows(ctx context.Context, userID string) error {
+	for {
+		followers, err := s.followRepo.ListFollowers(ctx, userID, 0, 100)
+		if err != nil {
+			return err
+		}
+		following, err := s.followRepo.ListFollowing(ctx, userID, 0, 100)
+		if err != nil {
+			return err
+		}
+		if len(followers) == 0 && len(following) == 0 {
+			return nil
+		}
+		for _, f := range append(followers, following...) {
+			if err := s.followRepo.Unfollow(ctx, f.FollowerID, f.FolloweeID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+		}
+	}
+}
+
+func (s *userService) Follow(ctx context.Context, userID string) (*model.User, error) {
+	viewer, target, err := s.followPair(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.followRepo.Follow(ctx, viewer, userID); err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, fmt.Errorf("failed to follow user: %w", err)
+	}
+	return target, nil
+}
+
+func (s *userService) Unfollow(ctx context.Context, userID string) (*model.User, error) {
+	viewer, target, err := s.followPair(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.followRepo.Unfollow(ctx, viewer, userID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return target, nil
+}
+
+func (s *userService) ListFollowers(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error) {
+	return s.followConnection(ctx, "follower", userID, first, after,
+		s.followRepo.ListFollowers, s.followRepo.CountFollowers,
+		func(f *repository.Follow) string { return f.FollowerID },
+	)
+}
+
+func (s *userService) ListFollowing(ctx context.Context, userID string, first *int32, after *string) (*model.UserConnection, error) {
+	return s.followConnection(ctx, "following", userID, first, after,
+		s.followRepo.ListFollowing, s.followRepo.CountFollowing,
+		func(f *repository.Follow) string { return f.FolloweeID },
+	)
+}
+
+// followPair resolves the calling user and the user they want to (un)follow
+func (s *userService) followPair(ctx context.Context, userID string) (string, *model.User, error) {
+	viewer, ok := auth.UserID(ctx)
+	if !ok {
+		return "", nil, fmt.Errorf("sign in to follow users: %w", ErrForbidden)
+	}
+	if _, err := s.userRepo.GetByID(ctx, viewer); err != nil {
+		return "", nil, fmt.Errorf("caller %s is not a user in this tenant: %w", viewer, ErrForbidden)
+	}
+	if viewer == userID {
+		return "", nil, &ValidationError{Field: "userId", Message: "users cannot follow themselves"}
+	}
+	target, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	return viewer, target, nil
+}
+
+// followConnection pages through one side of a user's follow edges. Cursors
+// carry the edge sequence number, so unfollows never shift later pages.
+func (s *userService) followConnection(
+	ctx context.Context,
+	kind, userID string,
+	first *int32,
+	after *string,
+	list func(ctx context.Context, userID string, before int64, limit int) ([]*repository.Follow, error),
+	count func(ctx context.Context, userID string) (int, error),
+	other func(*repository.Follow) string,
+) (*model.UserConnection, error) {
+	limit, err := pageSize(first, 20, maxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	var before int64
+	if after != nil {
+		value, err := decodeCursor(kind, *after)
+		if err != nil {
+			return nil, err
+		}
+		if before, err = strconv.ParseInt(value, 10, 64); err != nil || before < 1 {
+			return nil, &ValidationError{Field: "after", Message: "malformed cursor"}
+		}
+	}
+
+	total, err := count(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// One extra edge tells us whether another page exists
+	edges, err := list(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &model.UserConnection{
+		Edges:      make([]*model.UserEdge, 0, min(limit, len(edges))),
+		PageInfo:   &model.PageInfo{HasNextPage: len(edges) > limit},
+		TotalCount: int32(total),
+	}
+	for _, edge := range edges[:min(limit, len(edges))] {
+		cursor := encodeCursor(kind, strconv.FormatInt(edge.Seq, 10))
+		conn.PageInfo.EndCursor = &cursor
+		user, err := s.userRepo.GetByID(ctx, other(edge))
+		if errors.Is(err, repository.ErrNotFound) {
+			continue // deleted since the follow; keep the cursor moving past it
+		}
+		if err != nil {
+			return nil, err
+		}
+		conn.Edges = append(conn.Edges, &model.UserEdge{Cursor: cursor, Node: user})
+	}
+	return conn, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/events"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/webhook"
+)
+
+// webhookEvents maps the GraphQL enum to the event types services emit
+var webhookEvents = map[model.WebhookEvent]string{
+	model.WebhookEventUserCreated:   events.UserCreated,
+	model.WebhookEventPostCreated:   events.PostCreated,
+	model.WebhookEventPostPublished: events.PostPublished,
+	model.WebhookEventPostDeleted:   events.PostDeleted,
+}
+
+// WebhookService manages webhook subscriptions and, as an event sink, queues
+// a delivery for every subscription that wants the event. Only admins may
+// read or change subscriptions.
+type WebhookService interface {
+	events.Sink
+
+	ListWebhooks(ctx context.Context) ([]*model.WebhookSubscription, error)
+	CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.CreatedWebhook, error)
+	DeleteWebhook(ctx context.Context, id string) (*model.WebhookSubscription, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, first *int32) ([]*model.WebhookDelivery, error)
+	ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error)
+	// RedeliverDeadLetter queues a dead-lettered event again, starting a fresh round of attempts
+	RedeliverDeadLetter(ctx context.Context, id string) error
+}
+
+type webhookService struct {
+	repo       repository.WebhookRepository
+	dispatcher *webhook.Dispatcher
+}
+
+func NewWebhookService(repo repository.WebhookRepository, dispatcher *webhook.Dispatcher) WebhookService {
+	return &webhookService{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+
+func (s *webhookService) Emit(ctx context.Context, event events.Event) {
+	hooks, err := s.repo.ListWebhooks(tenant.WithID(ctx, event.Tenant))
+	if err != nil {
+		log.Printf("webhook: list subscriptions for %s: %v", event.Type, err)
+		return
+	}
+
+	var payload []byte
+	for _, hook := range hooks {
+		i := slices.IndexFunc(hook.Subscription.Events, func(e model.WebhookEvent) bool {
+			return webhookEvents[e] == event.Type
+		})
+		if i < 0 {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				log.Printf("webhook: encode %s %s: %v", event.Type, event.ID, err)
+				return
+			}
+		}
+		s.dispatcher.Enqueue(webhook.Job{
+			Tenant:         event.Tenant,
+			SubscriptionID: hook.Subscription.ID,
+			URL:            hook.Subscription.URL,
+			Secret:         hook.Secret,
+			EventID:        event.ID,
+			Event:          hook.Subscription.Events[i],
+			EventType:      event.Type,
+			Payload:        payload,
+		})
+	}
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	hooks, err := s.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]*model.WebhookSubscription, len(hooks))
+	for i, hook := range hooks {
+		subscriptions[i] = hook.Subscription
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.CreatedWebhook, error) {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return nil, &ValidationError{Field: "url", Message: "url must be an absolute http or https URL"}
+	}
+	if len(input.Events) == 0 {
+		return nil, &ValidationError{Field: "events", Message: "subscribe to at least one event"}
+	}
+	secret := webhook.NewSecret()
+	if input.Secret != nil {
+		if len(*input.Secret) < 16 {
+			return nil, &ValidationError{Field: "secret", Message: "secret must be at least 16 characters"}
+		}
+		secret = *input.Secret
+	}
+
+	subscribed := slices.Clone(input.Events)
+	slices.Sort(subscribed)
+	hook := &repository.Webhook{
+		Subscription: &model.WebhookSubscription{
+			ID:        newID(),
+			URL:       target.String(),
+			Events:    slices.Compact(subscribed),
+			CreatedAt: time.Now(),
+		},
+		Secret: secret,
+	}
+	if err := s.repo.CreateWebhook(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return &model.CreatedWebhook{Subscription: hook.Subscription, Secret: secret}, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	hook, err := s.repo.DeleteWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return hook.Subscription, nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID string, first *int32) ([]*model.WebhookDelivery, error) {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first, 50, maxDeliveriesPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, limit)
+}
+
+func (s *webhookService) ListDeadLetters(ctx context.Context) ([]*model.WebhookDeadLetter, error) {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeadLetters(ctx)
+}
+
+func (s *webhookService) RedeliverDeadLetter(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx, "manage webhooks"); err != nil {
+		return err
+	}
+	letters, err := s.repo.ListDeadLetters(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(letters, func(l *model.WebhookDeadLetter) bool { return l.ID == id })
+	if i < 0 {
+		return fmt.Errorf("dead letter with id %s %w", id, repository.ErrNotFound)
+	}
+	// The subscription may have been deleted since the event failed
+	hook, err := s.repo.GetWebhook(ctx, letters[i].SubscriptionID)
+	if err != nil {
+		return &ValidationError{Field: "deadLetterId", Message: "the webhook for this dead letter no longer exists", Err: err}
+	}
+	letter, err := s.repo.TakeDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	s.dispatcher.Enqueue(webhook.Job{
+		Tenant:         tenantID,
+		SubscriptionID: hook.Subscription.ID,
+		URL:            hook.Subscription.URL,
+		Secret:         hook.Secret,
+		EventID:        letter.EventID,
+		Event:          letter.Event,
+		EventType:      webhookEvents[letter.Event],
+		Payload:        []byte(letter.Payload),
+	})
+	return nil
+}
@@ -0,0 +1,233 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// node mirrors the operation's selections by response key, keeping only the
+// branches that lead to a streamed field
+type node struct {
+	children map[string]*node
+	stream   *streamArgs
+}
+
+type streamArgs struct {
+	initialCount int
+	label        string
+}
+
+// streamedFields collects the @stream fields of the operation rc executes
+func streamedFields(rc *graphql.OperationContext) *node {
+	root := &node{}
+	if rc.Operation != nil {
+		root.collect(rc.Operation.SelectionSet, rc.Variables)
+	}
+	root.prune()
+	return root
+}
+
+func (n *node) collect(set ast.SelectionSet, vars map[string]any) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			key := sel.Alias
+			if key == "" {
+				key = sel.Name
+			}
+			if n.children == nil {
+				n.children = map[string]*node{}
+			}
+			child := n.children[key]
+			if child == nil {
+				child = &node{}
+				n.children[key] = child
+			}
+			if d := sel.Directives.ForName("stream"); d != nil {
+				if args := streamArgsOf(d, vars); args != nil {
+					child.stream = args
+				}
+			}
+			child.collect(sel.SelectionSet, vars)
+		case *ast.InlineFragment:
+			n.collect(sel.SelectionSet, vars)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				n.collect(sel.Definition.SelectionSet, vars)
+			}
+		}
+	}
+}
+
+// streamArgsOf returns nil when the directive is turned off with if: false
+func streamArgsOf(d *ast.Directive, vars map[string]any) *streamArgs {
+	args := d.ArgumentMap(vars)
+	if on, ok := args["if"].(bool); ok && !on {
+		return nil
+	}
+	s := &streamArgs{}
+	switch v := args["initialCount"].(type) {
+	case int64:
+		s.initialCount = int(v)
+	case int:
+		s.initialCount = v
+	case json.Number:
+		n, _ := v.Int64()
+		s.initialCount = int(n)
+	}
+	if label, ok := args["label"].(string); ok {
+		s.label = label
+	}
+	return s
+}
+
+// prune drops the branches without a streamed field and reports whether n
+// still has one
+func (n *node) prune() bool {
+	for key, child := range n.children {
+		if !child.prune() {
+			delete(n.children, key)
+		}
+	}
+	return n.stream != nil || len(n.children) > 0
+}
+
+// split shortens the streamed lists in resp's data and returns an items
+// payload for each, in the order a client would read them
+func (n *node) split(resp *graphql.Response) []*items {
+	at := n
+	for _, el := range resp.Path {
+		name, ok := el.(ast.PathName)
+		if !ok {
+			continue
+		}
+		if at = at.children[string(name)]; at == nil {
+			return nil
+		}
+	}
+	if len(at.children) == 0 || len(resp.Data) == 0 {
+		return nil
+	}
+	data, out := at.splitObject(resp.Data, resp.Path)
+	resp.Data = data
+	return out
+}
+
+func (n *node) splitObject(raw json.RawMessage, path ast.Path) (json.RawMessage, []*items) {
+	members, ok := decodeObject(raw)
+	if !ok {
+		return raw, nil
+	}
+	var out []*items
+	for i, m := range members {
+		child := n.children[m.key]
+		if child == nil {
+			continue
+		}
+		var more []*items
+		members[i].value, more = child.splitValue(m.value, with(path, ast.PathName(m.key)))
+		out = append(out, more...)
+	}
+	return encodeObject(members), out
+}
+
+func (n *node) splitValue(raw json.RawMessage, path ast.Path) (json.RawMessage, []*items) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return raw, nil
+	}
+	switch trimmed[0] {
+	case '{':
+		return n.splitObject(raw, path)
+	case '[':
+	default:
+		return raw, nil
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return raw, nil
+	}
+	// Nested lists take the selections but not the @stream of the outer one
+	inner := &node{children: n.children}
+	var kept, rest []*items
+	for i := range elems {
+		var more []*items
+		elems[i], more = inner.splitValue(elems[i], with(path, ast.PathIndex(i)))
+		if n.stream != nil && i >= n.stream.initialCount {
+			rest = append(rest, more...)
+		} else {
+			kept = append(kept, more...)
+		}
+	}
+	if n.stream == nil || len(elems) <= n.stream.initialCount {
+		b, _ := json.Marshal(elems)
+		return b, kept
+	}
+
+	count := n.stream.initialCount
+	payload := &items{
+		Items: elems[count:],
+		Path:  with(path, ast.PathIndex(count)),
+		Label: n.stream.label,
+	}
+	b, _ := json.Marshal(elems[:count])
+	return b, append(append(kept, payload), rest...)
+}
+
+// with returns a copy of path extended by el
+func with(path ast.Path, el ast.PathElement) ast.Path {
+	out := make(ast.Path, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, el)
+}
+
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObject splits a JSON object into its members, keeping their order
+// since clients rely on fields arriving in selection order
+func decodeObject(raw json.RawMessage) ([]member, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	var members []member
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		members = append(members, member{key: key, value: value})
+	}
+	return members, true
+}
+
+func encodeObject(members []member) json.RawMessage {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(m.value)
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSplit_NestedStreams(t *testing.T) {
+	// users @stream(initialCount: 1) { name posts @stream(initialCount: 1) { id } }
+	root := &node{children: map[string]*node{
+		"users": {
+			stream: &streamArgs{initialCount: 1, label: "users"},
+			children: map[string]*node{
+				"posts": {stream: &streamArgs{initialCount: 1}},
+			},
+		},
+	}}
+	resp := &graphql.Response{Data: json.RawMessage(`{"total":2,"users":[` +
+		`{"name":"a","posts":[{"id":"1"},{"id":"2"}]},` +
+		`{"name":"b","posts":[{"id":"3"}]}]}`)}
+
+	out := root.split(resp)
+
+	if want := `{"total":2,"users":[{"name":"a","posts":[{"id":"1"}]}]}`; string(resp.Data) != want {
+		t.Errorf("data = %s, want %s", resp.Data, want)
+	}
+	want := []struct {
+		path  ast.Path
+		items string
+	}{
+		{ast.Path{ast.PathName("users"), ast.PathIndex(0), ast.PathName("posts"), ast.PathIndex(1)}, `[{"id":"2"}]`},
+		{ast.Path{ast.PathName("users"), ast.PathIndex(1)}, `[{"name":"b","posts":[{"id":"3"}]}]`},
+	}
+	if len(out) != len(want) {
+		t.Fatalf("got %d items payloads, want %d", len(out), len(want))
+	}
+	for i, w := range want {
+		items, _ := json.Marshal(out[i].Items)
+		if !reflect.DeepEqual(out[i].Path, w.path) || string(items) != w.items {
+			t.Errorf("payload %d = %v %s, want %v %s", i, out[i].Path, items, w.path, w.items)
+		}
+	}
+}
+
+func TestSplit_DeferredPayloadStartsAtItsPath(t *testing.T) {
+	root := &node{children: map[string]*node{
+		"user": {children: map[string]*node{
+			"posts": {stream: &streamArgs{initialCount: 0}},
+		}},
+	}}
+	resp := &graphql.Response{
+		Path: ast.Path{ast.PathName("user")},
+		Data: json.RawMessage(`{"posts":[{"id":"1"}]}`),
+	}
+
+	out := root.split(resp)
+
+	if string(resp.Data) != `{"posts":[]}` {
+		t.Errorf("data = %s, want an empty list", resp.Data)
+	}
+	if len(out) != 1 || !reflect.DeepEqual(out[0].Path, ast.Path{ast.PathName("user"), ast.PathName("posts"), ast.PathIndex(0)}) {
+		t.Errorf("items payloads = %+v", out)
+	}
+}
+
+func TestSplit_ShortListIsUntouched(t *testing.T) {
+	root := &node{children: map[string]*node{"posts": {stream: &streamArgs{initialCount: 5}}}}
+	resp := &graphql.Response{Data: json.RawMessage(`{"posts":[1,2]}`)}
+
+	if out := root.split(resp); len(out) != 0 {
+		t.Errorf("got %d items payloads, want none", len(out))
+	}
+	if string(resp.Data) != `{"posts":[1,2]}` {
+		t.Errorf("data = %s", resp.Data)
+	}
+}
@@ -0,0 +1,138 @@
+// Package stream delivers @stream lists incrementally over multipart/mixed.
+// gqlgen resolves every list in full, so the transport cuts each streamed list
+// after its initialCount items: those go out with the payload holding the list
+// and the rest follow in an items payload, as the incremental delivery RFC
+// (deferSpec=20220824) describes. Items are still resolved together with their
+// list, so @stream shrinks the payload a client waits for rather than the
+// server's work; combine it with @defer to move a slow list out of the way.
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// MultipartMixed replaces gqlgen's transport of the same name. It handles
+// POST requests whose Accept header includes multipart/mixed, sends @defer
+// payloads as they resolve and splits @stream lists.
+type MultipartMixed struct {
+	Boundary string
+}
+
+var _ graphql.Transport = MultipartMixed{}
+
+func (t MultipartMixed) Supports(r *http.Request) bool {
+	if !strings.Contains(r.Header.Get("Accept"), "multipart/mixed") || r.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+func (t MultipartMixed) Do(w http.ResponseWriter, r *http.Request, exec graphql.GraphExecutor) {
+	ctx := r.Context()
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	start := graphql.Now()
+	params := &graphql.RawParams{Headers: r.Header}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	err := dec.Decode(params)
+	params.ReadTime = graphql.TraceTiming{Start: start, End: graphql.Now()}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, exec.DispatchError(ctx, gqlerror.List{gqlerror.Errorf("json request body could not be decoded: %v", err)}))
+		return
+	}
+
+	rc, opErr := exec.CreateOperationContext(ctx, params)
+	ctx = graphql.WithOperationContext(ctx, rc)
+	if opErr != nil {
+		if errcode.GetErrorKind(opErr) == errcode.KindProtocol {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		writeJSON(w, exec.DispatchError(ctx, opErr))
+		return
+	}
+
+	boundary := t.Boundary
+	if boundary == "" {
+		boundary = "-"
+	}
+	w.Header().Set("Content-Type", fmt.Sprintf(`multipart/mixed;boundary="%s";deferSpec=20220824`, boundary))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	streams := streamedFields(rc)
+	responses, ctx := exec.DispatchOperation(ctx, rc)
+	initial := true
+	for resp := responses(ctx); resp != nil; resp = responses(ctx) {
+		more := resp.HasNext != nil && *resp.HasNext
+		items := streams.split(resp)
+		for i, part := range append([]any{resp}, itemsToAny(items)...) {
+			hasNext := more || i < len(items)
+			if i == 0 {
+				resp.HasNext = &hasNext
+			}
+			if i == 0 && initial {
+				writePart(w, boundary, resp)
+			} else {
+				writePart(w, boundary, incremental{Incremental: []any{part}, HasNext: hasNext})
+			}
+			flusher.Flush()
+		}
+		initial = false
+	}
+	fmt.Fprintf(w, "--%s--\r\n", boundary)
+	flusher.Flush()
+}
+
+// incremental wraps every payload after the initial one
+type incremental struct {
+	Incremental []any `json:"incremental"`
+	HasNext     bool  `json:"hasNext"`
+}
+
+// items carries the entries of a streamed list past its initialCount. Path
+// points at the first of them.
+type items struct {
+	Items []json.RawMessage `json:"items"`
+	Path  ast.Path          `json:"path"`
+	Label string            `json:"label,omitempty"`
+}
+
+func itemsToAny(in []*items) []any {
+	out := make([]any, len(in))
+	for i, it := range in {
+		out[i] = it
+	}
+	return out
+}
+
+func writePart(w io.Writer, boundary string, v any) {
+	fmt.Fprintf(w, "--%s\r\nContent-Type: application/json\r\n\r\n", boundary)
+	writeJSON(w, v)
+	io.WriteString(w, "\r\n")
+}
+
+func writeJSON(w io.Writer, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	w.Write(b)
+}
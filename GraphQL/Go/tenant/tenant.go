@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+)
+
+const (
+	// DefaultHeader is the request header clients use to select a workspace
+	DefaultHeader = "X-Tenant-ID"
+	// Default is the workspace used when a request names none
+	Default = "default"
+)
+
+// ErrNoTenant is returned by data access that runs without a tenant in its context
+var ErrNoTenant = errors.New("no tenant in context")
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type tenantKey struct{}
+
+// WithID returns a copy of ctx scoped to the given tenant
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant the request is scoped to, if any
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Require is FromContext for callers that must never run unscoped
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	return id, nil
+}
+
+// Options control how Middleware resolves the tenant for a request
+type Options struct {
+	// Header is read when the caller has no token claim (defaults to DefaultHeader)
+	Header string
+	// Default is used when neither a claim nor a header is present; empty rejects the request
+	Default string
+}
+
+// Middleware resolves the tenant from the verified token claim or the tenant header
+// and stores it in the request context. A token claim always wins, and a header that
+// contradicts it is rejected so callers cannot hop into another workspace.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	if opts.Header == "" {
+		opts.Header = DefaultHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, status, err := resolve(r, opts)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+		})
+	}
+}
+
+func resolve(r *http.Request, opts Options) (string, int, error) {
+	header := r.Header.Get(opts.Header)
+
+	if claims, ok := auth.ClaimsFromContext(r.Context()); ok && claims.Tenant != "" {
+		if header != "" && header != claims.Tenant {
+			return "", http.StatusForbidden, fmt.Errorf("tenant %q does not match token", header)
+		}
+		return claims.Tenant, 0, nil
+	}
+
+	id := header
+	if id == "" {
+		id = opts.Default
+	}
+	if id == "" {
+		return "", http.StatusBadRequest, fmt.Errorf("missing %s header", opts.Header)
+	}
+	if !validID.MatchString(id) {
+		return "", http.StatusBadRequest, fmt.Errorf("invalid tenant id %q", id)
+	}
+	return id, 0, nil
+}
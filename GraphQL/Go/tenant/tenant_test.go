@@ -0,0 +1,55 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		claim      string
+		def        string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "header", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "claim", claim: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "matching header and claim", header: "acme", claim: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "header contradicts claim", header: "globex", claim: "acme", wantStatus: http.StatusForbidden},
+		{name: "default", def: Default, wantStatus: http.StatusOK, wantTenant: Default},
+		{name: "missing", wantStatus: http.StatusBadRequest},
+		{name: "invalid", header: "../acme", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := Middleware(Options{Default: tt.def})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tt.header != "" {
+				req.Header.Set(DefaultHeader, tt.header)
+			}
+			if tt.claim != "" {
+				req = req.WithContext(auth.WithClaims(req.Context(), &auth.Claims{Tenant: tt.claim}))
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got != tt.wantTenant {
+				t.Fatalf("tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
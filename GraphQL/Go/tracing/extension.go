@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Krushnal121/API-Hub/GraphQL/Go/graph"
+
+// Extension opens one span per GraphQL operation and a child span for every
+// field backed by a resolver. Register it with srv.Use on the gqlgen handler.
+type Extension struct {
+	// Tracer defaults to the global provider; tests inject their own
+	Tracer trace.Tracer
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+func (e Extension) ExtensionName() string {
+	return "OpenTelemetryTracing"
+}
+
+func (e Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) tracer() trace.Tracer {
+	if e.Tracer != nil {
+		return e.Tracer
+	}
+	return otel.Tracer(instrumentationName)
+}
+
+// InterceptResponse wraps each response, so a subscription gets one span per event
+func (e Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+
+	name := oc.OperationName
+	opType := "unknown"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+	spanName := opType
+	if name != "" {
+		spanName = opType + " " + name
+	}
+
+	ctx, span := e.tracer().Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(oc.Stats.OperationStart),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.name", name),
+			attribute.String("graphql.operation.type", opType),
+		),
+	)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+		span.SetAttributes(attribute.Int("graphql.errors.count", len(resp.Errors)))
+	}
+	return resp
+}
+
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("graphql.field.path", fc.Path().String()),
+		attribute.String("graphql.field.parent", fc.Object),
+	}
+	// Entity IDs passed as arguments (user(id:), deletePost(id:), ...)
+	for name, value := range fc.Args {
+		if def := fc.Field.Definition; def != nil {
+			if arg := def.Arguments.ForName(name); arg != nil && isID(arg.Type) {
+				attrs = append(attrs, attribute.String("graphql.argument."+name, fmt.Sprint(value)))
+			}
+		}
+	}
+
+	ctx, span := e.tracer().Start(ctx, fc.Object+"."+fc.Field.Name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
+
+func isID(t *ast.Type) bool {
+	for t.Elem != nil {
+		t = t.Elem
+	}
+	return t.NamedType == "ID"
+}
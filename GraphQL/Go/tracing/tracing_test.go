@@ -0,0 +1,78 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/graphtest"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOperationTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "test"))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	users := repository.NewInMemoryUserRepository()
+	seedCtx := tenant.WithID(context.Background(), tenant.Default)
+	if err := users.Create(seedCtx, &model.User{ID: "1", Name: "Alice Johnson"}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	srv := graphtest.NewServer(graphtest.Repositories{
+		Users: repository.NewTracedUserRepository(users),
+		Posts: repository.NewTracedPostRepository(repository.NewInMemoryPostRepository()),
+	}, graphtest.Options{Extensions: []graphql.HandlerExtension{tracing.Extension{}}, Traced: true})
+	h := tracing.Middleware(srv.Handler())
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/query",
+		strings.NewReader(`{"query":"query GetUser { user(id: \"1\") { name } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "errors") {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		if got := span.SpanContext.TraceID().String(); got != traceID {
+			t.Errorf("span %q has trace ID %s, want the incoming %s", span.Name, got, traceID)
+		}
+		spans[span.Name] = span
+	}
+
+	// Each span must be a child of the one before it
+	chain := []string{"query GetUser", "Query.user", "UserService.GetUserByID", "UserRepository.GetByID"}
+	for i, name := range chain {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("missing span %q; got %v", name, exporter.GetSpans().Snapshots())
+		}
+		if i > 0 && span.Parent.SpanID() != spans[chain[i-1]].SpanContext.SpanID() {
+			t.Errorf("span %q is not a child of %q", name, chain[i-1])
+		}
+	}
+
+	var hasUserID bool
+	for _, attr := range spans["UserRepository.GetByID"].Attributes {
+		if attr.Key == "user.id" && attr.Value.AsString() == "1" {
+			hasUserID = true
+		}
+	}
+	if !hasUserID {
+		t.Error("repository span is missing the user.id attribute")
+	}
+}
@@ -0,0 +1,145 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Counts is the number of records of each kind written or imported
+type Counts struct {
+	Users     int `json:"users"`
+	Posts     int `json:"posts"`
+	Revisions int `json:"revisions"`
+	Follows   int `json:"follows"`
+}
+
+// Export writes the given tenants as NDJSON; with no tenants it exports every
+// tenant that holds users or posts
+func Export(ctx context.Context, repos Repositories, w io.Writer, tenants []string) (Counts, error) {
+	var counts Counts
+	if len(tenants) == 0 {
+		var err error
+		if tenants, err = allTenants(ctx, repos); err != nil {
+			return counts, err
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	if err := enc.Encode(Header{Kind: KindHeader, SchemaVersion: SchemaVersion, ExportedAt: time.Now().UTC()}); err != nil {
+		return counts, err
+	}
+	for _, id := range tenants {
+		if err := exportTenant(tenant.WithID(ctx, id), repos, enc, id, &counts); err != nil {
+			return counts, fmt.Errorf("export tenant %s: %w", id, err)
+		}
+	}
+	return counts, out.Flush()
+}
+
+func exportTenant(ctx context.Context, repos Repositories, enc *json.Encoder, tenantID string, counts *Counts) error {
+	users, err := repos.Users.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if err := enc.Encode(line{KindUser, tenantID, userRow{ID: u.ID, Name: u.Name, Email: u.Email}}); err != nil {
+			return err
+		}
+		counts.Users++
+	}
+
+	posts, err := repos.Posts.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range posts {
+		row := postRow{
+			ID:          p.ID,
+			Title:       p.Title,
+			Content:     p.Content,
+			AuthorID:    p.AuthorID,
+			Status:      string(p.Status),
+			PublishAt:   p.PublishAt,
+			PublishedAt: p.PublishedAt,
+			CreatedAt:   p.CreatedAt,
+		}
+		if err := enc.Encode(line{KindPost, tenantID, row}); err != nil {
+			return err
+		}
+		counts.Posts++
+	}
+
+	for _, p := range posts {
+		n, err := repos.Revisions.Count(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		revisions, err := repos.Revisions.ListByPost(ctx, p.ID, 0, n)
+		if err != nil {
+			return err
+		}
+		for _, rev := range revisions {
+			row := revisionRow{
+				PostID:       p.ID,
+				Number:       rev.Number,
+				Title:        rev.Title,
+				Content:      rev.Content,
+				EditorID:     rev.EditorID,
+				CreatedAt:    rev.CreatedAt,
+				RevertedFrom: rev.RevertedFrom,
+			}
+			if err := enc.Encode(line{KindRevision, tenantID, row}); err != nil {
+				return err
+			}
+			counts.Revisions++
+		}
+	}
+
+	for _, u := range users {
+		n, err := repos.Follows.CountFollowing(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		edges, err := repos.Follows.ListFollowing(ctx, u.ID, 0, n)
+		if err != nil {
+			return err
+		}
+		// Listed newest first; oldest first lets an import keep the original order
+		for i := len(edges) - 1; i >= 0; i-- {
+			row := followRow{FollowerID: edges[i].FollowerID, FolloweeID: edges[i].FolloweeID, CreatedAt: edges[i].CreatedAt}
+			if err := enc.Encode(line{KindFollow, tenantID, row}); err != nil {
+				return err
+			}
+			counts.Follows++
+		}
+	}
+	return nil
+}
+
+func allTenants(ctx context.Context, repos Repositories) ([]string, error) {
+	seen := map[string]bool{}
+	for _, list := range []func(context.Context) ([]string, error){repos.Users.Tenants, repos.Posts.Tenants} {
+		ids, err := list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			seen[id] = true
+		}
+	}
+
+	tenants := make([]string, 0, len(seen))
+	for id := range seen {
+		tenants = append(tenants, id)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
@@ -0,0 +1,80 @@
+// Package transfer moves data between environments as NDJSON. Export and
+// import go through the repository interfaces, so they work with any backend.
+//
+// The first line is a header carrying the schema version; every other line is
+// one record. Records are written parents first (users, posts, revisions,
+// follows) so a file can be checked for dangling references in one pass.
+package transfer
+
+import (
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+)
+
+// SchemaVersion is bumped whenever a record shape changes incompatibly
+const SchemaVersion = 1
+
+// Record kinds, in the order they are exported
+const (
+	KindHeader   = "header"
+	KindUser     = "user"
+	KindPost     = "post"
+	KindRevision = "revision"
+	KindFollow   = "follow"
+)
+
+// Repositories are the stores read by Export and written by Import
+type Repositories struct {
+	Users     repository.UserRepository
+	Posts     repository.PostRepository
+	Revisions repository.RevisionRepository
+	Follows   repository.FollowRepository
+}
+
+// Header is the first line of every export
+type Header struct {
+	Kind          string    `json:"kind"`
+	SchemaVersion int       `json:"schemaVersion"`
+	ExportedAt    time.Time `json:"exportedAt"`
+}
+
+// line is the envelope around one record; Data holds one of the row types below
+type line struct {
+	Kind   string `json:"kind"`
+	Tenant string `json:"tenant"`
+	Data   any    `json:"data"`
+}
+
+type userRow struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type postRow struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Content     *string    `json:"content,omitempty"`
+	AuthorID    string     `json:"authorId"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type revisionRow struct {
+	PostID       string    `json:"postId"`
+	Number       int32     `json:"number"`
+	Title        string    `json:"title"`
+	Content      *string   `json:"content,omitempty"`
+	EditorID     string    `json:"editorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	RevertedFrom *int32    `json:"revertedFrom,omitempty"`
+}
+
+type followRow struct {
+	FollowerID string    `json:"followerId"`
+	FolloweeID string    `json:"followeeId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
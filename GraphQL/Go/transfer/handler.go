@@ -0,0 +1,128 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Path is where the server mounts Handler
+const Path = "/admin/data"
+
+// Trailers sent after an export, since its status is written before the first record
+const (
+	TrailerCounts = "X-Export-Counts"
+	TrailerError  = "X-Export-Error"
+)
+
+// Handler moves the caller's tenant's data, for datactl -server. GET writes an
+// export of the tenant and reports the counts, or the error that cut it short,
+// in trailers. POST imports the NDJSON body into the tenant, whichever tenant
+// its records name, with ?dry-run and ?upsert as in Options, and answers with
+// the Report as JSON: 200 when it applied, 422 when the file has errors.
+//
+// Both need the admin role and the tenant tenant.Middleware resolves, so
+// auth.Middleware and then tenant.Middleware must run first. The admin role is
+// not tied to a tenant, so the tenant is never taken from the query: a token
+// naming a tenant reaches only that tenant, and there is no cross-tenant dump.
+func Handler(repos Repositories) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.IsAdmin(r.Context()) {
+			http.Error(w, "only admins may export or import data", http.StatusForbidden)
+			return
+		}
+		tenantID, err := tenant.Require(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Has("tenant") {
+			http.Error(w, "select the tenant with the "+tenant.DefaultHeader+" header, not ?tenant", http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			serveExport(w, r, repos, tenantID)
+		case http.MethodPost:
+			serveImport(w, r, repos, tenantID)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func serveExport(w http.ResponseWriter, r *http.Request, repos Repositories, tenantID string) {
+	w.Header().Set("Trailer", TrailerCounts+", "+TrailerError)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	counts, err := Export(r.Context(), repos, w, []string{tenantID})
+	if err != nil {
+		w.Header().Set(TrailerError, err.Error())
+		return
+	}
+	b, _ := json.Marshal(counts)
+	w.Header().Set(TrailerCounts, string(b))
+}
+
+func serveImport(w http.ResponseWriter, r *http.Request, repos Repositories, tenantID string) {
+	opts := Options{Tenant: tenantID}
+	q := r.URL.Query()
+	for name, dst := range map[string]*bool{"dry-run": &opts.DryRun, "upsert": &opts.Upsert} {
+		if v := q.Get(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				http.Error(w, name+" must be true or false", http.StatusBadRequest)
+				return
+			}
+			*dst = b
+		}
+	}
+
+	report, err := Import(r.Context(), repos, r.Body, opts)
+	switch {
+	case report == nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(report.Errors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// reportJSON is a Report on the wire, with each error replaced by its message
+type reportJSON struct {
+	Created Counts   `json:"created"`
+	Updated Counts   `json:"updated"`
+	Skipped Counts   `json:"skipped"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	out := reportJSON{Created: r.Created, Updated: r.Updated, Skipped: r.Skipped}
+	for _, err := range r.Errors {
+		out.Errors = append(out.Errors, err.Error())
+	}
+	return json.Marshal(out)
+}
+
+func (r *Report) UnmarshalJSON(data []byte) error {
+	var in reportJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = Report{Created: in.Created, Updated: in.Updated, Skipped: in.Skipped}
+	for _, msg := range in.Errors {
+		r.Errors = append(r.Errors, errors.New(msg))
+	}
+	return nil
+}
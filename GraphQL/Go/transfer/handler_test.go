@@ -0,0 +1,158 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/auth"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// serve runs Handler over repos, with every request signed in as claims and
+// its tenant read from the X-Tenant-ID header, which it requires
+func serve(t *testing.T, repos Repositories, claims *auth.Claims) *httptest.Server {
+	t.Helper()
+	h := tenant.Middleware(tenant.Options{})(Handler(repos))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims != nil {
+			r = r.WithContext(auth.WithClaims(r.Context(), claims))
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+var admin = &auth.Claims{Subject: "ops", Role: auth.RoleAdmin}
+
+// request sends a request to srv in tenantID
+func request(t *testing.T, srv *httptest.Server, method, query, tenantID, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+query, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(tenant.DefaultHeader, tenantID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHandler_RequiresAdmin(t *testing.T) {
+	for name, claims := range map[string]*auth.Claims{"anonymous": nil, "user": {Subject: "u1"}} {
+		t.Run(name, func(t *testing.T) {
+			resp := request(t, serve(t, newRepos(), claims), http.MethodGet, "", "t1", "")
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("status = %d, want 403", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHandler_ExportThenImport(t *testing.T) {
+	source := newRepos()
+	if _, err := Import(context.Background(), source, ndjson(alice, bob, alicePost, revision(1)), Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := request(t, serve(t, source, admin), http.MethodGet, "", "t1", "")
+	export, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := resp.Trailer.Get(TrailerError); msg != "" {
+		t.Fatalf("export failed: %s", msg)
+	}
+	var counts Counts
+	if err := json.Unmarshal([]byte(resp.Trailer.Get(TrailerCounts)), &counts); err != nil {
+		t.Fatalf("counts trailer %q: %v", resp.Trailer.Get(TrailerCounts), err)
+	}
+	if want := (Counts{Users: 2, Posts: 1, Revisions: 1}); counts != want {
+		t.Errorf("counts = %+v, want %+v", counts, want)
+	}
+
+	target := newRepos()
+	srv := serve(t, target, admin)
+	post := func(query, body string) (int, Report) {
+		t.Helper()
+		resp := request(t, srv, http.MethodPost, query, "t1", body)
+		var report Report
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("decode report: %v", err)
+		}
+		return resp.StatusCode, report
+	}
+
+	if status, report := post("?dry-run=true", string(export)); status != http.StatusOK || report.Created.Users != 2 {
+		t.Errorf("dry run: status %d, report %+v", status, report)
+	}
+	if status, report := post("", string(export)); status != http.StatusOK || report.Created.Posts != 1 {
+		t.Errorf("import: status %d, report %+v", status, report)
+	}
+	status, report := post("", string(export))
+	if status != http.StatusUnprocessableEntity || len(report.Errors) == 0 {
+		t.Errorf("repeat import: status %d, report %+v; want 422 with errors", status, report)
+	}
+}
+
+func TestHandler_RejectsUnreadableImport(t *testing.T) {
+	resp := request(t, serve(t, newRepos(), admin), http.MethodPost, "", "t1", "not json\n")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestHandler_ScopedToCallersTenant(t *testing.T) {
+	repos := newRepos()
+	t2 := strings.NewReplacer(`"tenant":"t1"`, `"tenant":"t2"`, "alice@", "carol@")
+	if _, err := Import(context.Background(), repos, ndjson(alice, t2.Replace(alice)), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	srv := serve(t, repos, admin)
+
+	// An export holds only the caller's tenant
+	resp := request(t, srv, http.MethodGet, "", "t1", "")
+	export, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(export), `"t2"`) || !strings.Contains(string(export), `"t1"`) {
+		t.Errorf("t1 export:\n%s", export)
+	}
+
+	// Records naming another tenant are imported into the caller's
+	bobInT2 := `{"kind":"user","tenant":"t2","data":{"id":"u2","name":"Bob","email":"bob@example.com"}}`
+	if resp := request(t, srv, http.MethodPost, "", "t1", header+"\n"+bobInT2+"\n"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("import: status %d", resp.StatusCode)
+	}
+	if _, err := repos.Users.GetByID(tenant.WithID(context.Background(), "t1"), "u2"); err != nil {
+		t.Errorf("bob was not imported into t1: %v", err)
+	}
+	if _, err := repos.Users.GetByID(tenant.WithID(context.Background(), "t2"), "u2"); err == nil {
+		t.Error("an import in t1 wrote to t2")
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		if resp := request(t, srv, method, "?tenant=t2", "t1", header+"\n"); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s with ?tenant: status %d, want 400", method, resp.StatusCode)
+		}
+	}
+	if resp := request(t, srv, http.MethodGet, "", "", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("export without a tenant: status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestHandler_TokenTenantCannotBeOverridden(t *testing.T) {
+	srv := serve(t, newRepos(), &auth.Claims{Subject: "ops", Role: auth.RoleAdmin, Tenant: "t1"})
+	if resp := request(t, srv, http.MethodGet, "", "t2", ""); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("t1 admin exporting t2: status %d, want 403", resp.StatusCode)
+	}
+}
@@ -0,0 +1,427 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// maxLineBytes bounds one record; post content is the only large field
+const maxLineBytes = 16 << 20
+
+// Options controls Import
+type Options struct {
+	// DryRun validates the file and reports what would change without writing
+	DryRun bool
+	// Upsert replaces users and posts that already exist instead of rejecting
+	// them; revisions and follows that already exist are skipped
+	Upsert bool
+	// Tenant, when set, imports every record into this tenant instead of the one it names
+	Tenant string
+}
+
+// Report is the outcome of an import. When Errors is not empty nothing was written.
+type Report struct {
+	Created Counts
+	Updated Counts
+	Skipped Counts
+	Errors  []error
+}
+
+// LineError is a problem with one line of the input
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Import reads an export and writes it through repos. The whole file is
+// validated first, including that every post author, revision post and editor,
+// and follow endpoint exists either earlier in the file or in the target store;
+// if anything is wrong nothing is written. The returned error is for input that
+// cannot be read at all, such as a missing header or an unsupported schema version.
+func Import(ctx context.Context, repos Repositories, r io.Reader, opts Options) (*Report, error) {
+	records, err := parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	plan := newPlanner(repos, opts)
+	for _, rec := range records {
+		if opts.Tenant != "" {
+			rec.tenant = opts.Tenant
+		}
+		if err := plan.add(tenant.WithID(ctx, rec.tenant), rec, report); err != nil {
+			report.Errors = append(report.Errors, &LineError{Line: rec.line, Err: err})
+		}
+	}
+	if len(report.Errors) > 0 || opts.DryRun {
+		return report, nil
+	}
+
+	for _, rec := range records {
+		if rec.skip {
+			continue
+		}
+		if err := apply(tenant.WithID(ctx, rec.tenant), repos, rec, report); err != nil {
+			// Validation passed, so this is the store failing; stop rather than leave gaps
+			return report, &LineError{Line: rec.line, Err: err}
+		}
+	}
+	return report, nil
+}
+
+// record is one parsed line; update and skip are decided while planning
+type record struct {
+	line   int
+	tenant string
+	row    any
+	update bool
+	skip   bool
+}
+
+func parse(r io.Reader) ([]*record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineBytes)
+
+	var records []*record
+	n := 0
+	for scanner.Scan() {
+		n++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if records == nil {
+			if err := checkHeader(data); err != nil {
+				return nil, &LineError{Line: n, Err: err}
+			}
+			records = []*record{}
+			continue
+		}
+		rec, err := decodeLine(data)
+		if err != nil {
+			return nil, &LineError{Line: n, Err: err}
+		}
+		rec.line = n
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read input: %w", err)
+	}
+	if records == nil {
+		return nil, errors.New("input is empty; expected a header line")
+	}
+	return records, nil
+}
+
+func checkHeader(data []byte) error {
+	var h Header
+	if err := json.Unmarshal(data, &h); err != nil || h.Kind != KindHeader {
+		return errors.New("first line must be a header record")
+	}
+	if h.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("schema version %d is not supported; this build reads version %d", h.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+func decodeLine(data []byte) (*record, error) {
+	var env struct {
+		Kind   string          `json:"kind"`
+		Tenant string          `json:"tenant"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rec := &record{tenant: env.Tenant}
+	switch env.Kind {
+	case KindUser:
+		rec.row = &userRow{}
+	case KindPost:
+		rec.row = &postRow{}
+	case KindRevision:
+		rec.row = &revisionRow{}
+	case KindFollow:
+		rec.row = &followRow{}
+	default:
+		return nil, fmt.Errorf("unknown record kind %q", env.Kind)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(env.Data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(rec.row); err != nil {
+		return nil, fmt.Errorf("invalid %s record: %w", env.Kind, err)
+	}
+	return rec, nil
+}
+
+// planner validates records in file order, remembering what earlier lines
+// will create so later references to them resolve
+type planner struct {
+	repos Repositories
+	opts  Options
+
+	users     map[string]map[string]bool    // tenant -> user IDs in the file
+	emails    map[string]map[string]string  // tenant -> normalized email -> user ID in the file
+	posts     map[string]map[string]string  // tenant -> post ID -> author ID
+	revisions map[string]map[string]int32   // tenant -> post ID -> latest revision number
+	follows   map[string]map[[2]string]bool // tenant -> follower/followee pairs in the file
+}
+
+func newPlanner(repos Repositories, opts Options) *planner {
+	return &planner{
+		repos:     repos,
+		opts:      opts,
+		users:     map[string]map[string]bool{},
+		emails:    map[string]map[string]string{},
+		posts:     map[string]map[string]string{},
+		revisions: map[string]map[string]int32{},
+		follows:   map[string]map[[2]string]bool{},
+	}
+}
+
+func (p *planner) add(ctx context.Context, rec *record, report *Report) error {
+	if rec.tenant == "" {
+		return errors.New("record has no tenant")
+	}
+	t := rec.tenant
+	if p.users[t] == nil {
+		p.users[t] = map[string]bool{}
+		p.emails[t] = map[string]string{}
+		p.posts[t] = map[string]string{}
+		p.revisions[t] = map[string]int32{}
+		p.follows[t] = map[[2]string]bool{}
+	}
+
+	switch row := rec.row.(type) {
+	case *userRow:
+		if row.ID == "" || row.Email == "" {
+			return errors.New("user needs an id and an email")
+		}
+		if p.users[t][row.ID] {
+			return fmt.Errorf("user %s appears twice", row.ID)
+		}
+		exists, err := p.userInStore(ctx, row.ID)
+		if err != nil {
+			return err
+		}
+		if err := p.existing(rec, exists, "user", row.ID); err != nil {
+			return err
+		}
+		if err := p.emailFree(ctx, t, row); err != nil {
+			return err
+		}
+		p.users[t][row.ID] = true
+		p.emails[t][repository.NormalizeEmail(row.Email)] = row.ID
+		tally(report, rec, &report.Created.Users, &report.Updated.Users)
+
+	case *postRow:
+		if row.ID == "" || row.Title == "" {
+			return errors.New("post needs an id and a title")
+		}
+		if !model.PostStatus(row.Status).IsValid() {
+			return fmt.Errorf("post %s has unknown status %q", row.ID, row.Status)
+		}
+		if _, dup := p.posts[t][row.ID]; dup {
+			return fmt.Errorf("post %s appears twice", row.ID)
+		}
+		if err := p.requireUser(ctx, t, row.AuthorID, "author of post "+row.ID); err != nil {
+			return err
+		}
+		stored, err := p.repos.Posts.GetByID(ctx, row.ID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		if stored != nil && stored.AuthorID != row.AuthorID {
+			return fmt.Errorf("post %s already exists with author %s; an import cannot change the author", row.ID, stored.AuthorID)
+		}
+		if err := p.existing(rec, stored != nil, "post", row.ID); err != nil {
+			return err
+		}
+		p.posts[t][row.ID] = row.AuthorID
+		tally(report, rec, &report.Created.Posts, &report.Updated.Posts)
+
+	case *revisionRow:
+		if _, inFile := p.posts[t][row.PostID]; !inFile {
+			if _, err := p.repos.Posts.GetByID(ctx, row.PostID); err != nil {
+				return fmt.Errorf("revision %d refers to unknown post %s", row.Number, row.PostID)
+			}
+		}
+		if err := p.requireUser(ctx, t, row.EditorID, fmt.Sprintf("editor of revision %d of post %s", row.Number, row.PostID)); err != nil {
+			return err
+		}
+		latest, seen := p.revisions[t][row.PostID]
+		if !seen {
+			n, err := p.repos.Revisions.Count(ctx, row.PostID)
+			if err != nil {
+				return err
+			}
+			latest = int32(n)
+		}
+		switch {
+		case row.Number >= 1 && row.Number <= latest && p.opts.Upsert:
+			// Revisions are immutable; the stored one stands
+			rec.skip = true
+			report.Skipped.Revisions++
+			return nil
+		case row.Number <= latest:
+			return fmt.Errorf("revision %d of post %s already exists (use upsert to skip it)", row.Number, row.PostID)
+		case row.Number != latest+1:
+			return fmt.Errorf("revision %d of post %s does not follow revision %d", row.Number, row.PostID, latest)
+		}
+		p.revisions[t][row.PostID] = row.Number
+		report.Created.Revisions++
+
+	case *followRow:
+		if row.FollowerID == row.FolloweeID {
+			return fmt.Errorf("user %s cannot follow themselves", row.FollowerID)
+		}
+		if err := p.requireUser(ctx, t, row.FollowerID, "follower"); err != nil {
+			return err
+		}
+		if err := p.requireUser(ctx, t, row.FolloweeID, "followee"); err != nil {
+			return err
+		}
+		pair := [2]string{row.FollowerID, row.FolloweeID}
+		if p.follows[t][pair] {
+			rec.skip = true
+			report.Skipped.Follows++
+			return nil
+		}
+		p.follows[t][pair] = true
+		report.Created.Follows++
+	}
+	return nil
+}
+
+// existing rejects a record for something already stored unless upserting
+func (p *planner) existing(rec *record, exists bool, kind, id string) error {
+	if !exists {
+		return nil
+	}
+	if !p.opts.Upsert {
+		return fmt.Errorf("%s %s already exists (use upsert to replace it)", kind, id)
+	}
+	rec.update = true
+	return nil
+}
+
+func (p *planner) userInStore(ctx context.Context, id string) (bool, error) {
+	_, err := p.repos.Users.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// emailFree checks that no other user in the file or the store has the row's email
+func (p *planner) emailFree(ctx context.Context, t string, row *userRow) error {
+	email := repository.NormalizeEmail(row.Email)
+	if owner, dup := p.emails[t][email]; dup {
+		return fmt.Errorf("user %s has the same email as user %s", row.ID, owner)
+	}
+	stored, err := p.repos.Users.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stored.ID != row.ID {
+		return fmt.Errorf("user %s has the same email as stored user %s", row.ID, stored.ID)
+	}
+	return nil
+}
+
+// requireUser checks that id is created earlier in the file or already stored
+func (p *planner) requireUser(ctx context.Context, t, id, role string) error {
+	if id == "" {
+		return fmt.Errorf("%s has no user id", role)
+	}
+	if p.users[t][id] {
+		return nil
+	}
+	exists, err := p.userInStore(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s refers to unknown user %s", role, id)
+	}
+	return nil
+}
+
+func tally(report *Report, rec *record, created, updated *int) {
+	if rec.update {
+		*updated++
+	} else {
+		*created++
+	}
+}
+
+func apply(ctx context.Context, repos Repositories, rec *record, report *Report) error {
+	switch row := rec.row.(type) {
+	case *userRow:
+		user := &model.User{ID: row.ID, Name: row.Name, Email: row.Email}
+		if rec.update {
+			return repos.Users.Update(ctx, user)
+		}
+		return repos.Users.Create(ctx, user)
+
+	case *postRow:
+		post := &model.Post{
+			ID:          row.ID,
+			Title:       row.Title,
+			Content:     row.Content,
+			AuthorID:    row.AuthorID,
+			Status:      model.PostStatus(row.Status),
+			PublishAt:   row.PublishAt,
+			PublishedAt: row.PublishedAt,
+			CreatedAt:   row.CreatedAt,
+		}
+		if rec.update {
+			return repos.Posts.Update(ctx, post)
+		}
+		return repos.Posts.Create(ctx, post)
+
+	case *revisionRow:
+		return repos.Revisions.Append(ctx, row.PostID, &model.PostRevision{
+			Number:       row.Number,
+			Title:        row.Title,
+			Content:      row.Content,
+			EditorID:     row.EditorID,
+			CreatedAt:    row.CreatedAt,
+			RevertedFrom: row.RevertedFrom,
+		})
+
+	case *followRow:
+		// The store assigns its own timestamp, so an import keeps the order of follows but not their times
+		err := repos.Follows.Follow(ctx, row.FollowerID, row.FolloweeID)
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			report.Created.Follows--
+			report.Skipped.Follows++
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package transfer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+const header = `{"kind":"header","schemaVersion":1,"exportedAt":"2026-01-01T00:00:00Z"}`
+
+// Lines reused across cases; every record is in tenant t1
+const (
+	alice     = `{"kind":"user","tenant":"t1","data":{"id":"u1","name":"Alice","email":"alice@example.com"}}`
+	bob       = `{"kind":"user","tenant":"t1","data":{"id":"u2","name":"Bob","email":"bob@example.com"}}`
+	alicePost = `{"kind":"post","tenant":"t1","data":{"id":"p1","title":"Hello","authorId":"u1","status":"PUBLISHED","createdAt":"2026-01-01T00:00:00Z"}}`
+)
+
+func revision(n int) string {
+	return `{"kind":"revision","tenant":"t1","data":{"postId":"p1","number":` + strconv.Itoa(n) + `,"title":"Hello","editorId":"u1","createdAt":"2026-01-01T00:00:00Z"}}`
+}
+
+func newRepos() Repositories {
+	return Repositories{
+		Users:     repository.NewInMemoryUserRepository(),
+		Posts:     repository.NewInMemoryPostRepository(),
+		Revisions: repository.NewInMemoryRevisionRepository(),
+		Follows:   repository.NewInMemoryFollowRepository(),
+	}
+}
+
+func ndjson(lines ...string) *strings.Reader {
+	return strings.NewReader(header + "\n" + strings.Join(lines, "\n") + "\n")
+}
+
+func TestImport_RejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "dangling author",
+			lines: []string{alicePost},
+			want:  "line 2: author of post p1 refers to unknown user u1",
+		},
+		{
+			name: "duplicate email",
+			lines: []string{alice,
+				`{"kind":"user","tenant":"t1","data":{"id":"u3","name":"Alias","email":"Alice@Example.com"}}`},
+			want: "line 3: user u3 has the same email as user u1",
+		},
+		{
+			name:  "revision gap",
+			lines: []string{alice, alicePost, revision(1), revision(3)},
+			want:  "line 5: revision 3 of post p1 does not follow revision 1",
+		},
+		{
+			name:  "revision of unknown post",
+			lines: []string{alice, revision(1)},
+			want:  "line 3: revision 1 refers to unknown post p1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newRepos()
+			report, err := Import(context.Background(), repos, ndjson(tt.lines...), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Errors) != 1 || report.Errors[0].Error() != tt.want {
+				t.Fatalf("errors = %v, want [%s]", report.Errors, tt.want)
+			}
+			// Nothing is written when any line is wrong
+			if tenants, _ := repos.Users.Tenants(context.Background()); len(tenants) != 0 {
+				t.Errorf("users were stored in %v", tenants)
+			}
+		})
+	}
+}
+
+func TestImport_DryRunWritesNothing(t *testing.T) {
+	repos := newRepos()
+	report, err := Import(context.Background(), repos, ndjson(alice, bob, alicePost, revision(1)), Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", report.Errors)
+	}
+	if want := (Counts{Users: 2, Posts: 1, Revisions: 1}); report.Created != want {
+		t.Errorf("created = %+v, want %+v", report.Created, want)
+	}
+
+	ctx := tenant.WithID(context.Background(), "t1")
+	for _, list := range []func(context.Context) ([]string, error){repos.Users.Tenants, repos.Posts.Tenants} {
+		if tenants, _ := list(ctx); len(tenants) != 0 {
+			t.Errorf("dry run stored records in %v", tenants)
+		}
+	}
+	if n, _ := repos.Revisions.Count(ctx, "p1"); n != 0 {
+		t.Errorf("dry run stored %d revisions", n)
+	}
+}
+
+func TestImport_Upsert(t *testing.T) {
+	ctx := context.Background()
+	repos := newRepos()
+	if _, err := Import(ctx, repos, ndjson(alice, alicePost, revision(1)), Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := strings.Replace(alice, `"Alice"`, `"Alice Smith"`, 1)
+	input := func() *strings.Reader { return ndjson(renamed, alicePost, revision(1), revision(2)) }
+
+	// Without upsert the existing records are errors
+	report, err := Import(ctx, repos, input(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Errors) != 3 {
+		t.Fatalf("got %d errors, want one each for the user, post and revision 1: %v", len(report.Errors), report.Errors)
+	}
+
+	report, err = Import(ctx, repos, input(), Options{Upsert: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", report.Errors)
+	}
+	if want := (Counts{Users: 1, Posts: 1}); report.Updated != want {
+		t.Errorf("updated = %+v, want %+v", report.Updated, want)
+	}
+	if want := (Counts{Revisions: 1}); report.Skipped != want {
+		t.Errorf("skipped = %+v, want %+v", report.Skipped, want)
+	}
+	if want := (Counts{Revisions: 1}); report.Created != want {
+		t.Errorf("created = %+v, want %+v", report.Created, want)
+	}
+
+	tctx := tenant.WithID(ctx, "t1")
+	user, err := repos.Users.GetByID(tctx, "u1")
+	if err != nil || user.Name != "Alice Smith" {
+		t.Errorf("user = %+v, %v; want the upserted name", user, err)
+	}
+	if n, _ := repos.Revisions.Count(tctx, "p1"); n != 2 {
+		t.Errorf("post has %d revisions, want 2", n)
+	}
+}
+
+func TestImport_EmailTakenInStore(t *testing.T) {
+	repos := newRepos()
+	ctx := tenant.WithID(context.Background(), "t1")
+	if err := repos.Users.Create(ctx, &model.User{ID: "u9", Name: "Existing", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Import(context.Background(), repos, ndjson(alice), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "line 2: user u1 has the same email as stored user u9"; len(report.Errors) != 1 || report.Errors[0].Error() != want {
+		t.Errorf("errors = %v, want [%s]", report.Errors, want)
+	}
+}
+
+func TestImport_RejectsUnreadableInput(t *testing.T) {
+	for name, input := range map[string]string{
+		"empty":          "",
+		"no header":      alice + "\n",
+		"newer version":  `{"kind":"header","schemaVersion":99}` + "\n",
+		"unknown kind":   header + "\n" + `{"kind":"comment","tenant":"t1","data":{}}` + "\n",
+		"unknown fields": header + "\n" + `{"kind":"user","tenant":"t1","data":{"id":"u1","role":"admin"}}` + "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Import(context.Background(), newRepos(), strings.NewReader(input), Options{}); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
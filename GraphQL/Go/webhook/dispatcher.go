@@ -0,0 +1,274 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+// Job is one event on its way to one subscription
+type Job struct {
+	Tenant         string
+	SubscriptionID string
+	URL            string
+	Secret         string
+	EventID        string
+	Event          model.WebhookEvent
+	EventType      string // wire name, e.g. "post.created"
+	Payload        []byte
+
+	attempt int
+}
+
+// Options tune delivery. Zero values fall back to DefaultOptions.
+type Options struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	// Attempt n waits BaseDelay * 2^(n-2) after the previous one, capped at MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Timeout   time.Duration
+}
+
+// DefaultOptions gives up after six attempts spread over about half a minute
+func DefaultOptions() Options {
+	return Options{
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: 6,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Dispatcher sends jobs from a bounded queue, records every attempt in the
+// delivery log and moves jobs that exhaust their attempts to the dead-letter list
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	opts   Options
+	queue  chan *Job
+
+	mu       sync.Mutex
+	stopped  bool
+	retrying map[*Job]*time.Timer // jobs waiting out their backoff
+}
+
+// NewDispatcher creates a dispatcher; call Run to start delivering
+func NewDispatcher(repo repository.WebhookRepository, opts Options) *Dispatcher {
+	defaults := DefaultOptions()
+	if opts.Workers <= 0 {
+		opts.Workers = defaults.Workers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaults.QueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = defaults.BaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = defaults.MaxDelay
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	return &Dispatcher{
+		repo:     repo,
+		client:   &http.Client{Timeout: opts.Timeout},
+		opts:     opts,
+		queue:    make(chan *Job, opts.QueueSize),
+		retrying: map[*Job]*time.Timer{},
+	}
+}
+
+// Enqueue schedules the first attempt without blocking. When the queue is full
+// the job goes straight to the dead-letter list so it can be redelivered later.
+//
+// The queue and the retries are held in memory, so at-least-once delivery
+// ends here: the outbox entry behind a job is acknowledged once the job is
+// enqueued. When Run stops, jobs still queued or waiting out a backoff are
+// moved to the dead-letter list rather than dropped, and a dead-letter list
+// that outlives the process is what keeps them across restarts.
+func (d *Dispatcher) Enqueue(job Job) {
+	job.attempt = 1
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.push(&job)
+}
+
+// Run delivers queued jobs until ctx is cancelled, then waits for in-flight
+// requests and dead-letters every job not yet delivered
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range d.opts.Workers {
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-d.queue:
+					d.attempt(job)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	d.stop()
+}
+
+// stop dead-letters queued jobs and pending retries; later jobs go straight
+// to the dead-letter list
+func (d *Dispatcher) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+	for job, timer := range d.retrying {
+		// A timer that already fired finds the dispatcher stopped and dead-letters its job
+		if timer.Stop() {
+			d.deadLetter(job, job.attempt-1, errStopped)
+		}
+	}
+	clear(d.retrying)
+	for {
+		select {
+		case job := <-d.queue:
+			d.deadLetter(job, job.attempt-1, errStopped)
+		default:
+			return
+		}
+	}
+}
+
+// errStopped is the dead-letter reason for jobs left when the dispatcher stops
+const errStopped = "dispatcher stopped before delivery"
+
+// Backoff is the wait before attempt n+1 after attempt n failed
+func Backoff(n int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < n && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// push queues job for its next attempt; d.mu must be held
+func (d *Dispatcher) push(job *Job) {
+	if d.stopped {
+		d.deadLetter(job, job.attempt-1, errStopped)
+		return
+	}
+	select {
+	case d.queue <- job:
+	default:
+		d.deadLetter(job, job.attempt-1, "delivery queue is full")
+	}
+}
+
+// retry pushes job again once delay has passed
+func (d *Dispatcher) retry(job *Job, delay time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		d.deadLetter(job, job.attempt-1, errStopped)
+		return
+	}
+	d.retrying[job] = time.AfterFunc(delay, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.retrying, job)
+		d.push(job)
+	})
+}
+
+func (d *Dispatcher) attempt(job *Job) {
+	ctx := tenant.WithID(context.Background(), job.Tenant)
+	start := time.Now()
+	status, err := d.send(ctx, job, start)
+
+	delivery := &model.WebhookDelivery{
+		SubscriptionID: job.SubscriptionID,
+		EventID:        job.EventID,
+		Event:          job.Event,
+		Attempt:        int32(job.attempt),
+		DurationMs:     int32(time.Since(start).Milliseconds()),
+		DeliveredAt:    start,
+	}
+	if status != 0 {
+		code := int32(status)
+		delivery.StatusCode = &code
+	}
+	if err != nil {
+		msg := err.Error()
+		delivery.Error = &msg
+	}
+	if recErr := d.repo.RecordDelivery(ctx, delivery); recErr != nil {
+		log.Printf("webhook: record delivery of %s to %s: %v", job.EventID, job.SubscriptionID, recErr)
+	}
+	if err == nil {
+		return
+	}
+
+	if job.attempt >= d.opts.MaxAttempts {
+		d.deadLetter(job, job.attempt, err.Error())
+		return
+	}
+	delay := Backoff(job.attempt, d.opts.BaseDelay, d.opts.MaxDelay)
+	job.attempt++
+	d.retry(job, delay)
+}
+
+// send posts the job once and returns the response status, if there was one
+func (d *Dispatcher) send(ctx context.Context, job *Job, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "API-Hub-Webhooks/1")
+	req.Header.Set(EventHeader, job.EventType)
+	req.Header.Set(DeliveryHeader, job.EventID)
+	req.Header.Set(SignatureHeader, Sign(job.Secret, now, job.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) deadLetter(job *Job, attempts int, reason string) {
+	ctx := tenant.WithID(context.Background(), job.Tenant)
+	letter := &model.WebhookDeadLetter{
+		SubscriptionID: job.SubscriptionID,
+		EventID:        job.EventID,
+		Event:          job.Event,
+		Payload:        string(job.Payload),
+		Attempts:       int32(attempts),
+		LastError:      reason,
+		FailedAt:       time.Now(),
+	}
+	if err := d.repo.AddDeadLetter(ctx, letter); err != nil {
+		log.Printf("webhook: dead-letter %s for %s: %v", job.EventID, job.SubscriptionID, err)
+	}
+}
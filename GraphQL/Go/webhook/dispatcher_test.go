@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/graph/model"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/repository"
+	"github.com/Krushnal121/API-Hub/GraphQL/Go/tenant"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{50, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, time.Second, time.Minute); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+// failingReceiver answers 500 to the first failures requests and 204 after,
+// checking every signature
+func failingReceiver(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify("secret", r.Header.Get(SignatureHeader), body, time.Minute, time.Now()); err != nil {
+			t.Errorf("request %d: %v", n, err)
+		}
+		if n <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testJob(url string) Job {
+	return Job{
+		Tenant:         tenant.Default,
+		SubscriptionID: "sub1",
+		URL:            url,
+		Secret:         "secret",
+		EventID:        "evt1",
+		Event:          model.WebhookEventPostCreated,
+		EventType:      "post.created",
+		Payload:        []byte(`{"id":"evt1"}`),
+	}
+}
+
+// startDispatcher runs d until the test ends and returns a func that stops
+// it and waits for Run to return
+func startDispatcher(t *testing.T, d *Dispatcher) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// eventually polls cond until it holds or a second has passed
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatcher_RetriesUntilDelivered(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	repo := repository.NewInMemoryWebhookRepository()
+	srv, calls := failingReceiver(t, 2)
+	d := NewDispatcher(repo, Options{Workers: 1, MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond})
+	startDispatcher(t, d)
+
+	d.Enqueue(testJob(srv.URL))
+
+	var log []*model.WebhookDelivery
+	eventually(t, "three attempts", func() bool {
+		log, _ = repo.ListDeliveries(ctx, "sub1", 10)
+		return len(log) == 3
+	})
+	// Newest first: two failures, then the success
+	for i, want := range []int32{http.StatusNoContent, http.StatusInternalServerError, http.StatusInternalServerError} {
+		if got := log[i]; got.StatusCode == nil || *got.StatusCode != want || got.Attempt != int32(3-i) {
+			t.Errorf("delivery %d = attempt %d status %v, want attempt %d status %d", i, got.Attempt, got.StatusCode, 3-i, want)
+		}
+	}
+	if log[0].Error != nil || log[1].Error == nil {
+		t.Errorf("errors = %v, %v; want none on success and one on failure", log[0].Error, log[1].Error)
+	}
+	if letters, _ := repo.ListDeadLetters(ctx); len(letters) != 0 {
+		t.Errorf("got %d dead letters, want none", len(letters))
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("receiver saw %d requests, want 3", n)
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	repo := repository.NewInMemoryWebhookRepository()
+	srv, calls := failingReceiver(t, 100)
+	d := NewDispatcher(repo, Options{Workers: 1, MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond})
+	startDispatcher(t, d)
+
+	d.Enqueue(testJob(srv.URL))
+
+	var letters []*model.WebhookDeadLetter
+	eventually(t, "a dead letter", func() bool {
+		letters, _ = repo.ListDeadLetters(ctx)
+		return len(letters) == 1
+	})
+	letter := letters[0]
+	if letter.Attempts != 3 || letter.EventID != "evt1" || letter.Payload != `{"id":"evt1"}` {
+		t.Errorf("dead letter = %+v", letter)
+	}
+	if letter.LastError != "unexpected status 500" {
+		t.Errorf("last error = %q", letter.LastError)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("receiver saw %d requests, want 3", n)
+	}
+}
+
+func TestDispatcher_StopDeadLettersPendingRetries(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	repo := repository.NewInMemoryWebhookRepository()
+	srv, _ := failingReceiver(t, 100)
+	d := NewDispatcher(repo, Options{Workers: 1, MaxAttempts: 5, BaseDelay: time.Hour})
+	stop := startDispatcher(t, d)
+
+	d.Enqueue(testJob(srv.URL))
+	eventually(t, "the first attempt", func() bool {
+		log, _ := repo.ListDeliveries(ctx, "sub1", 10)
+		return len(log) == 1
+	})
+	stop()
+
+	letters, _ := repo.ListDeadLetters(ctx)
+	if len(letters) != 1 || letters[0].LastError != errStopped || letters[0].Attempts != 1 {
+		t.Fatalf("dead letters = %+v, want the job waiting for its retry", letters)
+	}
+
+	// Jobs that arrive after Run returned are not lost either
+	d.Enqueue(testJob(srv.URL))
+	if letters, _ := repo.ListDeadLetters(ctx); len(letters) != 2 || letters[1].Attempts != 0 {
+		t.Errorf("dead letters = %+v, want the late job with no attempts", letters)
+	}
+}
+
+func TestDispatcher_FullQueueDeadLetters(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.Default)
+	repo := repository.NewInMemoryWebhookRepository()
+	d := NewDispatcher(repo, Options{QueueSize: 1})
+
+	// Not running, so the first job fills the queue
+	d.Enqueue(testJob("http://127.0.0.1:0"))
+	d.Enqueue(testJob("http://127.0.0.1:0"))
+
+	letters, _ := repo.ListDeadLetters(ctx)
+	if len(letters) != 1 || letters[0].LastError != "delivery queue is full" {
+		t.Errorf("dead letters = %+v, want one for the full queue", letters)
+	}
+}
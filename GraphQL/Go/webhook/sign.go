@@ -0,0 +1,80 @@
+// Package webhook delivers events to subscriber URLs. Each request body is
+// the event as JSON, signed with the subscription's secret:
+//
+//	X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+//
+// Receivers should recompute the HMAC, compare it in constant time, reject
+// stale timestamps and use X-Webhook-Delivery (the event ID) to drop repeats,
+// since a retry may follow a delivery whose response was lost.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Request headers
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	ErrBadSignature   = errors.New("webhook signature does not match")
+	ErrStaleSignature = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// Sign returns the signature header value for body sent at ts
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify checks a signature header produced by Sign, allowing the timestamp
+// to be at most tolerance away from now
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+	got, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(got, mac(secret, t, body)) {
+		return ErrBadSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrStaleSignature
+	}
+	return nil
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+func mac(secret, t string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(t))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"type":"post.created"}`)
+	sentAt := time.Unix(1_700_000_000, 0)
+	header := Sign(secret, sentAt, body)
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"valid", secret, header, body, sentAt.Add(time.Minute), nil},
+		{"clock skew within tolerance", secret, header, body, sentAt.Add(-time.Minute), nil},
+		{"tampered body", secret, header, []byte(`{"type":"post.deleted"}`), sentAt, ErrBadSignature},
+		{"wrong secret", "whsec_other", header, body, sentAt, ErrBadSignature},
+		{"stale timestamp", secret, header, body, sentAt.Add(10 * time.Minute), ErrStaleSignature},
+		{"future timestamp", secret, header, body, sentAt.Add(-10 * time.Minute), ErrStaleSignature},
+		{"timestamp swapped", secret, strings.Replace(header, "t=1700000000", "t=1700000300", 1), body, sentAt.Add(5 * time.Minute), ErrBadSignature},
+		{"missing signature", secret, "t=1700000000", body, sentAt, ErrBadSignature},
+		{"malformed header", secret, "garbage", body, sentAt, ErrBadSignature},
+		{"empty header", secret, "", body, sentAt, ErrBadSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign_Format(t *testing.T) {
+	header := Sign("s", time.Unix(42, 0), []byte("{}"))
+	if !strings.HasPrefix(header, "t=42,v1=") || len(header) != len("t=42,v1=")+64 {
+		t.Errorf("Sign = %q, want t=42,v1=<64 hex digits>", header)
+	}
+}